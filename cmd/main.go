@@ -43,8 +43,32 @@ func main() {
 	}
 	logrus.Info("Database connected!")
 
-	// init google oauth config
-	config.InitGoogleOauthConfig()
+	// go run cmd/main.go rebuild-aggregates membangun ulang monthly_aggregates lalu keluar
+	if len(os.Args) > 1 && os.Args[1] == "rebuild-aggregates" {
+		if err := config.RebuildMonthlyAggregates(db); err != nil {
+			logrus.Fatalf("Failed to rebuild monthly aggregates: %v", err)
+		}
+		logrus.Info("Monthly aggregates rebuilt")
+		return
+	}
+
+	// init email sender
+	config.InitEmailSender()
+
+	// init oauth providers, redirect URL default memakai APP_URL dari email sender
+	config.InitOAuthProviders()
+
+	// init unverified email policy
+	config.InitVerificationPolicy()
+
+	// init login brute-force protection
+	config.InitLoginProtection()
+
+	// init direktori upload foto profil
+	config.InitStorage()
+
+	// init cache response dashboard
+	config.InitDashboardCache()
 
 	// set gin mode
 	ginMode := os.Getenv("GIN_MODE")
@@ -58,7 +82,7 @@ func main() {
 	r := gin.Default()
 	r.Use(utility.Recovery())
 	r.Use(middleware.LoggingMiddleware())
-	r.Use(middleware.CorsMiddleware())
+	r.Use(middleware.CorsMiddleware(config.AppURL))
 
 	// setup router
 	router.InitRoutes(r, db)
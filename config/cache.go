@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// jumlah maksimal response dashboard di cache LRU in-memory, 0 menonaktifkan cache
+	DashboardCacheSize int
+	// umur maksimal entry cache dashboard, invalidasi utama tetap lewat perubahan transaksi/kategori
+	DashboardCacheTTL time.Duration
+)
+
+func InitDashboardCache() {
+	DashboardCacheSize = 1000
+	if value := os.Getenv("DASHBOARD_CACHE_SIZE"); value != "" {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 0 {
+			logrus.Fatalf("Invalid DASHBOARD_CACHE_SIZE: %s", value)
+		}
+		DashboardCacheSize = size
+	}
+
+	DashboardCacheTTL = 5 * time.Minute
+	if value := os.Getenv("DASHBOARD_CACHE_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			logrus.Fatalf("Invalid DASHBOARD_CACHE_TTL: %s", value)
+		}
+		DashboardCacheTTL = ttl
+	}
+
+	logrus.Infof("Dashboard cache size: %d, ttl: %s", DashboardCacheSize, DashboardCacheTTL)
+}
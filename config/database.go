@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/utility"
 	"os"
 
 	"github.com/sirupsen/logrus"
@@ -29,9 +30,111 @@ func ConnectDB() *gorm.DB {
 		&entity.User{},
 		&entity.Category{},
 		&entity.Transaction{},
+		&entity.Session{},
+		&entity.PasswordReset{},
+		&entity.EmailVerification{},
+		&entity.RecoveryCode{},
+		&entity.LoginAttempt{},
+		&entity.SecurityEvent{},
+		&entity.APIToken{},
+		&entity.UserActivity{},
+		&entity.ChatUsage{},
+		&entity.Workspace{},
+		&entity.WorkspaceMember{},
+		&entity.WorkspaceInvitation{},
+		&entity.OAuthState{},
+		&entity.UserIdentity{},
+		&entity.UserPreference{},
+		&entity.DataExport{},
+		&entity.MonthlyAggregate{},
+		&entity.SpendingAnomaly{},
+		&entity.NetWorthSnapshot{},
 	); err != nil {
 		logrus.Fatal("Auto migration failed:", err)
 	}
 
+	migrateLegacyAdminFlag(db)
+	migrateWorkspaces(db)
+	migrateMonthlyAggregates(db)
+
 	return db
 }
+
+// migrateLegacyAdminFlag memindahkan flag is_admin lama ke kolom role, admin lama mendapat role superadmin
+// agar hak aksesnya tetap sama. Kolom is_admin dihapus setelah dimigrasi sehingga hanya berjalan sekali.
+func migrateLegacyAdminFlag(db *gorm.DB) {
+	if !db.Migrator().HasColumn(&entity.User{}, "is_admin") {
+		return
+	}
+
+	if err := db.Exec("UPDATE users SET role = ? WHERE is_admin = true AND role = ?", entity.RoleSuperadmin, entity.RoleUser).Error; err != nil {
+		logrus.Fatal("Failed to migrate admin flag:", err)
+	}
+
+	if err := db.Migrator().DropColumn(&entity.User{}, "is_admin"); err != nil {
+		logrus.Fatal("Failed to drop is_admin column:", err)
+	}
+}
+
+// migrateWorkspaces membuat workspace personal untuk user lama dan memindahkan kategori serta
+// transaksi yang belum punya workspace ke workspace personal pembuatnya. Aman dijalankan berulang.
+func migrateWorkspaces(db *gorm.DB) {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO workspaces (name, owner_id, is_personal, created_at, updated_at)
+			SELECT 'Personal', u.id, true, NOW(), NOW() FROM users u
+			WHERE NOT EXISTS (SELECT 1 FROM workspaces w WHERE w.owner_id = u.id AND w.is_personal = true AND w.deleted_at IS NULL)`).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO workspace_members (workspace_id, user_id, role, created_at, updated_at)
+			SELECT w.id, w.owner_id, ?, NOW(), NOW() FROM workspaces w
+			WHERE w.is_personal = true AND w.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM workspace_members m WHERE m.workspace_id = w.id AND m.user_id = w.owner_id)`,
+			entity.WorkspaceRoleOwner).Error; err != nil {
+			return err
+		}
+
+		for _, table := range []string{"categories", "transactions"} {
+			if err := tx.Exec(`
+				UPDATE ` + table + ` t SET workspace_id = w.id FROM workspaces w
+				WHERE t.workspace_id = 0 AND w.owner_id = t.user_id AND w.is_personal = true AND w.deleted_at IS NULL`).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Fatal("Failed to migrate workspaces:", err)
+	}
+}
+
+// migrateMonthlyAggregates mengisi monthly_aggregates dari transaksi yang sudah ada saat tabel baru dibuat.
+// Setelah terisi, agregat dijaga oleh TransactionService dan bisa diperbaiki dengan perintah rebuild-aggregates.
+func migrateMonthlyAggregates(db *gorm.DB) {
+	var aggregated, transactions int64
+	if err := db.Model(&entity.MonthlyAggregate{}).Count(&aggregated).Error; err != nil {
+		logrus.Fatal("Failed to check monthly aggregates:", err)
+	}
+	if aggregated > 0 {
+		return
+	}
+	if err := db.Model(&entity.Transaction{}).Count(&transactions).Error; err != nil {
+		logrus.Fatal("Failed to check monthly aggregates:", err)
+	}
+	if transactions == 0 {
+		return
+	}
+
+	if err := RebuildMonthlyAggregates(db); err != nil {
+		logrus.Fatal("Failed to migrate monthly aggregates:", err)
+	}
+}
+
+// RebuildMonthlyAggregates membangun ulang seluruh monthly_aggregates dari tabel transactions
+func RebuildMonthlyAggregates(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		return utility.RebuildMonthlyAggregates(tx)
+	})
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"go-fintrack/internal/utility"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	EmailSender utility.EmailSender
+	AppURL      string
+)
+
+func InitEmailSender() {
+	AppURL = os.Getenv("APP_URL")
+	if AppURL == "" {
+		AppURL = "http://localhost:3000"
+	}
+
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "no-reply@fintrack.local"
+	}
+
+	switch os.Getenv("MAIL_DRIVER") {
+	case "smtp":
+		host := os.Getenv("SMTP_HOST")
+		port := os.Getenv("SMTP_PORT")
+		if host == "" || port == "" {
+			logrus.Fatal("Please set SMTP_HOST and SMTP_PORT env variable")
+		}
+
+		EmailSender = &utility.SMTPSender{
+			Host:     host,
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		}
+		logrus.Infof("Email sender initialized with SMTP host: %s", host)
+	default:
+		dir := os.Getenv("MAIL_OUTBOX_DIR")
+		if dir == "" {
+			dir = "outbox"
+		}
+
+		EmailSender = &utility.FileSender{Dir: dir, From: from}
+		logrus.Infof("Email sender initialized with file outbox: %s", dir)
+	}
+}
@@ -1,35 +1,109 @@
 package config
 
 import (
+	"fmt"
+	"go-fintrack/internal/service"
 	"os"
+	"strings"
 
 	"github.com/sirupsen/logrus"
-	"golang.org/x/oauth2"
-	"golang.org/x/oauth2/google"
 )
 
 var (
-	GoogleOauthConfig *oauth2.Config
+	// provider login OAuth/OIDC yang aktif, urutannya sesuai OAUTH_PROVIDERS
+	OAuthProviders []service.OAuthProviderConfig
+	// driver penyimpanan state OAuth: "memory" (default) atau "db" untuk multi instance
+	OAuthStateStoreDriver string
 )
 
-func InitGoogleOauthConfig() {
-	clientId := os.Getenv("GOOGLE_CLIENT_ID")
-	clientSecret := os.Getenv("GOOGLE_CLIENT_SECRET")
+// InitOAuthProviders membaca provider dari OAUTH_PROVIDERS (mis. "google,github,keycloak"), setiap
+// provider dikonfigurasi lewat OAUTH_<NAME>_* sehingga provider baru cukup ditambahkan di environment.
+// Harus dipanggil setelah InitEmailSender karena redirect URL default memakai APP_URL.
+func InitOAuthProviders() {
+	OAuthStateStoreDriver = os.Getenv("OAUTH_STATE_STORE")
+	if OAuthStateStoreDriver == "" {
+		OAuthStateStoreDriver = "memory"
+	}
+
+	names := splitList(os.Getenv("OAUTH_PROVIDERS"))
+	// kompatibilitas konfigurasi lama yang hanya memakai GOOGLE_CLIENT_ID
+	if len(names) == 0 && os.Getenv("GOOGLE_CLIENT_ID") != "" {
+		names = []string{"google"}
+	}
 
-	if clientId == "" || clientSecret == "" {
-		logrus.Fatal("Please set GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET env variable")
+	OAuthProviders = nil
+	for _, name := range names {
+		name = strings.ToLower(name)
+		cfg := oauthProviderFromEnv(name)
+
+		if cfg.ClientID == "" || cfg.ClientSecret == "" {
+			logrus.Fatalf("Please set %s and %s env variable", oauthEnvKey(name, "CLIENT_ID"), oauthEnvKey(name, "CLIENT_SECRET"))
+		}
+
+		OAuthProviders = append(OAuthProviders, cfg)
+		logrus.Infof("OAuth provider %s (%s) initialized with client ID: %s", cfg.Name, cfg.Type, cfg.ClientID)
 	}
 
-	GoogleOauthConfig = &oauth2.Config{
-		ClientID:     clientId,
-		ClientSecret: clientSecret,
-		RedirectURL:  "http://localhost:3000/auth/google/callback",
-		Scopes: []string{
-			"https://www.googleapis.com/auth/userinfo.email",
-			"https://www.googleapis.com/auth/userinfo.profile",
-		},
-		Endpoint: google.Endpoint,
+	if len(OAuthProviders) == 0 {
+		logrus.Warn("No OAuth providers configured, social login is disabled")
 	}
+}
 
-	logrus.Infof("Google Oauth initialized with client ID: %s", clientId)
+func oauthProviderFromEnv(name string) service.OAuthProviderConfig {
+	get := func(key string) string {
+		return os.Getenv(oauthEnvKey(name, key))
+	}
+
+	providerType := get("TYPE")
+	if providerType == "" {
+		switch name {
+		case service.OAuthTypeGoogle, service.OAuthTypeGitHub:
+			providerType = name
+		default:
+			providerType = service.OAuthTypeOIDC
+		}
+	}
+
+	cfg := service.OAuthProviderConfig{
+		Name:         name,
+		DisplayName:  get("DISPLAY_NAME"),
+		Type:         providerType,
+		ClientID:     get("CLIENT_ID"),
+		ClientSecret: get("CLIENT_SECRET"),
+		RedirectURL:  get("REDIRECT_URL"),
+		Scopes:       splitList(get("SCOPES")),
+		IssuerURL:    get("ISSUER_URL"),
+		AuthURL:      get("AUTH_URL"),
+		TokenURL:     get("TOKEN_URL"),
+		UserInfoURL:  get("USERINFO_URL"),
+	}
+
+	if name == "google" {
+		if cfg.ClientID == "" {
+			cfg.ClientID = os.Getenv("GOOGLE_CLIENT_ID")
+		}
+		if cfg.ClientSecret == "" {
+			cfg.ClientSecret = os.Getenv("GOOGLE_CLIENT_SECRET")
+		}
+	}
+
+	if cfg.RedirectURL == "" {
+		cfg.RedirectURL = fmt.Sprintf("%s/auth/%s/callback", strings.TrimSuffix(AppURL, "/"), name)
+	}
+
+	return cfg
+}
+
+func oauthEnvKey(name, key string) string {
+	return "OAUTH_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_" + key
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
 }
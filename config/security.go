@@ -0,0 +1,23 @@
+package config
+
+import (
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// driver penyimpanan gagal login: "memory" (default) atau "db" untuk multi instance
+var LoginAttemptStoreDriver string
+
+func InitLoginProtection() {
+	LoginAttemptStoreDriver = os.Getenv("LOGIN_ATTEMPT_STORE")
+	if LoginAttemptStoreDriver == "" {
+		LoginAttemptStoreDriver = "memory"
+	}
+	// salah ketik (mis. "database") tidak boleh diam-diam jatuh ke store per instance
+	if LoginAttemptStoreDriver != "memory" && LoginAttemptStoreDriver != "db" {
+		logrus.Fatalf("Invalid LOGIN_ATTEMPT_STORE %q, use memory or db", LoginAttemptStoreDriver)
+	}
+
+	logrus.Infof("Login attempt store: %s", LoginAttemptStoreDriver)
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// direktori file upload user (foto profil), dilayani router di path /uploads
+	UploadDir string
+	// direktori ZIP export data user, hanya bisa diunduh lewat endpoint yang membutuhkan login
+	ExportDir string
+	// masa tenggang sebelum akun yang diminta dihapus benar-benar dihapus permanen
+	AccountDeletionGracePeriod time.Duration
+)
+
+func InitStorage() {
+	UploadDir = os.Getenv("UPLOAD_DIR")
+	if UploadDir == "" {
+		UploadDir = "uploads"
+	}
+
+	ExportDir = os.Getenv("EXPORT_DIR")
+	if ExportDir == "" {
+		ExportDir = "exports"
+	}
+
+	for _, dir := range []string{UploadDir, ExportDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			logrus.Fatalf("Failed to create directory %s: %v", dir, err)
+		}
+	}
+
+	graceDays := 14
+	if value := os.Getenv("ACCOUNT_DELETION_GRACE_DAYS"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			logrus.Fatalf("Invalid ACCOUNT_DELETION_GRACE_DAYS: %s", value)
+		}
+		graceDays = days
+	}
+	AccountDeletionGracePeriod = time.Duration(graceDays) * 24 * time.Hour
+
+	logrus.Infof("Upload directory: %s, export directory: %s, account deletion grace period: %d days", UploadDir, ExportDir, graceDays)
+}
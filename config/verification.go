@@ -0,0 +1,32 @@
+package config
+
+import (
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fitur yang diblokir untuk akun dengan email belum terverifikasi
+var UnverifiedRestrictions = map[string]bool{}
+
+func InitVerificationPolicy() {
+	features, exists := os.LookupEnv("UNVERIFIED_RESTRICTED_FEATURES")
+	if !exists {
+		features = "export,chat"
+	}
+
+	UnverifiedRestrictions = map[string]bool{}
+	for _, feature := range strings.Split(features, ",") {
+		feature = strings.TrimSpace(feature)
+		if feature != "" {
+			UnverifiedRestrictions[feature] = true
+		}
+	}
+
+	logrus.Infof("Unverified email restrictions: %s", features)
+}
+
+func IsRestrictedForUnverified(feature string) bool {
+	return UnverifiedRestrictions[feature]
+}
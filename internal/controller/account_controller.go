@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountController struct {
+	AccountService *service.AccountService
+}
+
+// RequestDataExportHandler godoc
+// @Summary 	Request data export
+// @Description Start a background job that builds a ZIP (JSON and CSV) with all data owned by the logged in user
+// @Tags 		me
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	202 {object} response.SuccessResponse{data=response.DataExportResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse "Email not verified"
+// @Failure 	409 {object} response.SuccessResponse
+// @Router 		/me/exports [post]
+func (c *AccountController) RequestDataExportHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	export, err := c.AccountService.RequestExport(userID)
+	if err != nil {
+		if err == service.ErrDataExportInProgress {
+			utility.ErrorResponse(ctx, http.StatusConflict, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to request data export", err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Data export requested, you will receive an email when it is ready",
+		Data:            export,
+	})
+}
+
+// GetDataExportsHandler godoc
+// @Summary 	Get data exports
+// @Description Get the data export jobs of the logged in user
+// @Tags 		me
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=[]response.DataExportResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/me/exports [get]
+func (c *AccountController) GetDataExportsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	exports, err := c.AccountService.ListExports(userID)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get data exports", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get data exports successful",
+		Data:            exports,
+	})
+}
+
+// DownloadDataExportHandler godoc
+// @Summary 	Download data export
+// @Description Download a completed data export ZIP, available for 7 days
+// @Tags 		me
+// @Produce 	application/zip
+// @Security 	BearerAuth
+// @Param 		id path int true "Data export ID"
+// @Success 	200 {file} file
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse "Email not verified"
+// @Failure 	404 {object} response.SuccessResponse
+// @Failure 	410 {object} response.SuccessResponse
+// @Router 		/me/exports/{id}/download [get]
+func (c *AccountController) DownloadDataExportHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	exportID, ok := parseUintParam(ctx, "id", "Invalid data export ID")
+	if !ok {
+		return
+	}
+
+	path, err := c.AccountService.GetExportFile(userID, exportID)
+	if err != nil {
+		switch err {
+		case service.ErrDataExportNotFound:
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+		case service.ErrDataExportUnavailable:
+			utility.ErrorResponse(ctx, http.StatusGone, err.Error(), nil)
+		default:
+			utility.InternalServerErrorResponse(ctx, "Failed to get data export", err)
+		}
+		return
+	}
+
+	ctx.FileAttachment(path, filepath.Base(path))
+}
+
+// RequestAccountDeletionHandler godoc
+// @Summary 	Delete account
+// @Description Schedule permanent deletion of the account and all owned data after the grace period.
+// @Description Logging in and calling the cancel endpoint before then keeps the account.
+// @Tags 		me
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.AccountDeletionRequest true "Current password"
+// @Success 	202 {object} response.SuccessResponse{data=response.AccountDeletionResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/me/deletion [post]
+func (c *AccountController) RequestAccountDeletionHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.AccountDeletionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	deletion, err := c.AccountService.ScheduleDeletion(userID, req.Password)
+	if err != nil {
+		if err == service.ErrDeletionPasswordInvalid {
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to schedule account deletion", err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Account deletion scheduled",
+		Data:            deletion,
+	})
+}
+
+// CancelAccountDeletionHandler godoc
+// @Summary 	Cancel account deletion
+// @Description Cancel a scheduled account deletion during the grace period
+// @Tags 		me
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/me/deletion [delete]
+func (c *AccountController) CancelAccountDeletionHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	if err := c.AccountService.CancelDeletion(userID); err != nil {
+		if err == service.ErrDeletionNotScheduled {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to cancel account deletion", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Account deletion canceled",
+		Data:            nil,
+	})
+}
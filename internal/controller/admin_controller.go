@@ -0,0 +1,355 @@
+package controller
+
+import (
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminController struct {
+	LoginGuard   *service.LoginGuard
+	AdminService *service.AdminService
+	StatsService *service.AdminStatsService
+	RBACService  *service.RBACService
+}
+
+// getTargetUserID mengambil ID admin yang login dan ID user dari path, false jika response error sudah dikirim
+func getTargetUserID(ctx *gin.Context) (adminID uint, userID uint, ok bool) {
+	adminID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return 0, 0, false
+	}
+
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid user ID", nil)
+		return 0, 0, false
+	}
+
+	return adminID, uint(id), true
+}
+
+func respondAdminError(ctx *gin.Context, message string, err error) {
+	switch err {
+	case service.ErrUserNotFound:
+		utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+	case service.ErrCannotModifySelf, service.ErrInvalidRole, service.ErrLastSuperadmin:
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+	case service.ErrInsufficientRole:
+		utility.ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
+	default:
+		utility.InternalServerErrorResponse(ctx, message, err)
+	}
+}
+
+// GetUsersHandler godoc
+// @Summary 	List users
+// @Description List and search users with pagination
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		search query string false "Search by name, email or username"
+// @Param 		status query string false "Account status" Enums(active, suspended)
+// @Param 		role query string false "Filter by role" Enums(user, support-readonly, admin, superadmin)
+// @Param 		page query int false "Page number" default(1)
+// @Param 		limit query int false "Items per page" default(10)
+// @Success 	200 {object} response.SuccessResponse{data=response.AdminUserListResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Router 		/admin/users [get]
+func (c *AdminController) GetUsersHandler(ctx *gin.Context) {
+	var filter request.AdminUserFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	users, err := c.AdminService.ListUsers(filter)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get users", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get users successful",
+		Data:            users,
+	})
+}
+
+// GetUserDetailHandler godoc
+// @Summary 	Get user detail
+// @Description Get a user with account status and usage stats (transaction count, last login, provider)
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "User ID"
+// @Success 	200 {object} response.SuccessResponse{data=response.AdminUserDetailResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/admin/users/{id} [get]
+func (c *AdminController) GetUserDetailHandler(ctx *gin.Context) {
+	_, userID, ok := getTargetUserID(ctx)
+	if !ok {
+		return
+	}
+
+	user, err := c.AdminService.GetUserDetail(userID)
+	if err != nil {
+		respondAdminError(ctx, "Failed to get user", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get user successful",
+		Data:            user,
+	})
+}
+
+// SuspendUserHandler godoc
+// @Summary 	Suspend user
+// @Description Suspend a user account and revoke all of its sessions
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "User ID"
+// @Param 		request body request.SuspendUserRequest false "Suspend reason"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/admin/users/{id}/suspend [post]
+func (c *AdminController) SuspendUserHandler(ctx *gin.Context) {
+	adminID, userID, ok := getTargetUserID(ctx)
+	if !ok {
+		return
+	}
+
+	// body bersifat opsional
+	var req request.SuspendUserRequest
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			utility.BindingErrorResponse(ctx, err)
+			return
+		}
+	}
+
+	if err := c.AdminService.SuspendUser(adminID, userID, req.Reason); err != nil {
+		respondAdminError(ctx, "Failed to suspend user", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "User suspended",
+		Data:            nil,
+	})
+}
+
+// ReactivateUserHandler godoc
+// @Summary 	Reactivate user
+// @Description Lift the suspension of a user account
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "User ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/admin/users/{id}/reactivate [post]
+func (c *AdminController) ReactivateUserHandler(ctx *gin.Context) {
+	adminID, userID, ok := getTargetUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.AdminService.ReactivateUser(adminID, userID); err != nil {
+		respondAdminError(ctx, "Failed to reactivate user", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "User reactivated",
+		Data:            nil,
+	})
+}
+
+// UpdateUserRoleHandler godoc
+// @Summary 	Assign role
+// @Description Assign a role (user, support-readonly, admin, superadmin) to a user, it applies without re-login
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "User ID"
+// @Param 		request body request.UpdateUserRoleRequest true "New role"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/admin/users/{id}/role [put]
+func (c *AdminController) UpdateUserRoleHandler(ctx *gin.Context) {
+	adminID, userID, ok := getTargetUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var req request.UpdateUserRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	if err := c.RBACService.AssignRole(adminID, userID, req.Role); err != nil {
+		respondAdminError(ctx, "Failed to update user role", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "User role updated",
+		Data:            nil,
+	})
+}
+
+// ForcePasswordResetHandler godoc
+// @Summary 	Force password reset
+// @Description Block login until the user resets the password, revoke all sessions and email a reset link
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "User ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/admin/users/{id}/force-password-reset [post]
+func (c *AdminController) ForcePasswordResetHandler(ctx *gin.Context) {
+	adminID, userID, ok := getTargetUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.AdminService.ForcePasswordReset(adminID, userID); err != nil {
+		respondAdminError(ctx, "Failed to force password reset", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Password reset link sent to the user",
+		Data:            nil,
+	})
+}
+
+// DeleteUserHandler godoc
+// @Summary 	Delete user
+// @Description Soft-delete a user account and revoke all of its sessions and API tokens
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "User ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/admin/users/{id} [delete]
+func (c *AdminController) DeleteUserHandler(ctx *gin.Context) {
+	adminID, userID, ok := getTargetUserID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.AdminService.DeleteUser(adminID, userID); err != nil {
+		respondAdminError(ctx, "Failed to delete user", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "User deleted",
+		Data:            nil,
+	})
+}
+
+// UnlockUserHandler godoc
+// @Summary 	Unlock user login
+// @Description Clear failed login attempts and lift a temporary lockout for a user
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "User ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Router 		/admin/users/{id}/unlock [post]
+func (c *AdminController) UnlockUserHandler(ctx *gin.Context) {
+	adminID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	userID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid user ID", nil)
+		return
+	}
+
+	if err := c.LoginGuard.Unlock(uint(userID), adminID); err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to unlock user", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "User unlocked",
+		Data:            nil,
+	})
+}
+
+// GetRolesHandler godoc
+// @Summary 	List roles
+// @Description List available roles and their permissions
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=response.RoleListResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Router 		/admin/roles [get]
+func (c *AdminController) GetRolesHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get roles successful",
+		Data: response.RoleListResponse{
+			Roles: c.RBACService.ListRoles(),
+		},
+	})
+}
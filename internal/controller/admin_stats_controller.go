@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPlatformOverviewHandler godoc
+// @Summary 	Get platform overview
+// @Description Get platform totals: users by provider, verified and suspended users, DAU, MAU, transaction count and chat usage
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=response.PlatformOverviewResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Router 		/admin/stats/overview [get]
+func (c *AdminController) GetPlatformOverviewHandler(ctx *gin.Context) {
+	overview, err := c.StatsService.GetOverview()
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get platform overview", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get platform overview successful",
+		Data:            overview,
+	})
+}
+
+// bindStatsFilter membaca query filter statistik, false jika response error sudah dikirim
+func bindStatsFilter(ctx *gin.Context) (request.AdminStatsFilter, bool) {
+	var filter request.AdminStatsFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return filter, false
+	}
+	return filter, true
+}
+
+func respondStatsError(ctx *gin.Context, message string, err error) {
+	if err == service.ErrInvalidStatsRange {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	utility.InternalServerErrorResponse(ctx, message, err)
+}
+
+// GetSignupStatsHandler godoc
+// @Summary 	Get signup stats
+// @Description Count user signups per day or month grouped by provider (email, google)
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		start_date query string false "Start date (YYYY-MM-DD)"
+// @Param 		end_date query string false "End date (YYYY-MM-DD)"
+// @Param 		granularity query string false "Group by" Enums(day, month) default(day)
+// @Success 	200 {object} response.SuccessResponse{data=response.SignupStatsResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Router 		/admin/stats/signups [get]
+func (c *AdminController) GetSignupStatsHandler(ctx *gin.Context) {
+	filter, ok := bindStatsFilter(ctx)
+	if !ok {
+		return
+	}
+
+	stats, err := c.StatsService.GetSignupStats(filter)
+	if err != nil {
+		respondStatsError(ctx, "Failed to get signup stats", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get signup stats successful",
+		Data:            stats,
+	})
+}
+
+// GetActiveUserStatsHandler godoc
+// @Summary 	Get active user stats
+// @Description Count unique active users per day (DAU) or month (MAU)
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		start_date query string false "Start date (YYYY-MM-DD)"
+// @Param 		end_date query string false "End date (YYYY-MM-DD)"
+// @Param 		granularity query string false "Group by" Enums(day, month) default(day)
+// @Success 	200 {object} response.SuccessResponse{data=response.ActiveUserStatsResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Router 		/admin/stats/active-users [get]
+func (c *AdminController) GetActiveUserStatsHandler(ctx *gin.Context) {
+	filter, ok := bindStatsFilter(ctx)
+	if !ok {
+		return
+	}
+
+	stats, err := c.StatsService.GetActiveUserStats(filter)
+	if err != nil {
+		respondStatsError(ctx, "Failed to get active user stats", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get active user stats successful",
+		Data:            stats,
+	})
+}
+
+// GetTransactionVolumeStatsHandler godoc
+// @Summary 	Get transaction volume stats
+// @Description Count recorded transactions per day or month, amounts are never included
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		start_date query string false "Start date (YYYY-MM-DD)"
+// @Param 		end_date query string false "End date (YYYY-MM-DD)"
+// @Param 		granularity query string false "Group by" Enums(day, month) default(day)
+// @Success 	200 {object} response.SuccessResponse{data=response.TransactionVolumeResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Router 		/admin/stats/transactions [get]
+func (c *AdminController) GetTransactionVolumeStatsHandler(ctx *gin.Context) {
+	filter, ok := bindStatsFilter(ctx)
+	if !ok {
+		return
+	}
+
+	stats, err := c.StatsService.GetTransactionVolumeStats(filter)
+	if err != nil {
+		respondStatsError(ctx, "Failed to get transaction volume stats", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get transaction volume stats successful",
+		Data:            stats,
+	})
+}
+
+// GetChatUsageStatsHandler godoc
+// @Summary 	Get chat usage stats
+// @Description Count chat messages and unique chat users per day or month
+// @Tags 		admin
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		start_date query string false "Start date (YYYY-MM-DD)"
+// @Param 		end_date query string false "End date (YYYY-MM-DD)"
+// @Param 		granularity query string false "Group by" Enums(day, month) default(day)
+// @Success 	200 {object} response.SuccessResponse{data=response.ChatUsageResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Router 		/admin/stats/chat [get]
+func (c *AdminController) GetChatUsageStatsHandler(ctx *gin.Context) {
+	filter, ok := bindStatsFilter(ctx)
+	if !ok {
+		return
+	}
+
+	stats, err := c.StatsService.GetChatUsageStats(filter)
+	if err != nil {
+		respondStatsError(ctx, "Failed to get chat usage stats", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get chat usage stats successful",
+		Data:            stats,
+	})
+}
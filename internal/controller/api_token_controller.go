@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"errors"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APITokenController struct {
+	APITokenService *service.APITokenService
+}
+
+// GetAPITokensHandler godoc
+// @Summary 	Get personal access tokens
+// @Description Get all personal access tokens of the logged in user, the token value itself is never returned
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=response.APITokenListResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/auth/tokens [get]
+func (c *APITokenController) GetAPITokensHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	tokens, err := c.APITokenService.ListTokens(userID)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get API tokens", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get API tokens successful",
+		Data: response.APITokenListResponse{
+			Tokens: tokens,
+		},
+	})
+}
+
+// CreateAPITokenHandler godoc
+// @Summary 	Create personal access token
+// @Description Create a scoped personal access token, the token is only shown once in this response
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.CreateAPITokenRequest true "Token name, scopes and expiry"
+// @Success 	201 {object} response.SuccessResponse{data=response.APITokenCreatedResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/auth/tokens [post]
+func (c *APITokenController) CreateAPITokenHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.CreateAPITokenRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	token, err := c.APITokenService.CreateToken(userID, req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAPITokenScope) {
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to create API token", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "API token created, copy it now because it will not be shown again",
+		Data:            token,
+	})
+}
+
+// RevokeAPITokenHandler godoc
+// @Summary 	Revoke personal access token
+// @Description Revoke a personal access token by ID
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Token ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/auth/tokens/{id} [delete]
+func (c *APITokenController) RevokeAPITokenHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	tokenID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid token ID", nil)
+		return
+	}
+
+	if err := c.APITokenService.RevokeToken(userID, uint(tokenID)); err != nil {
+		if err == service.ErrAPITokenNotFound {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to revoke API token", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "API token revoked",
+		Data:            nil,
+	})
+}
@@ -1,17 +1,15 @@
 package controller
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
-	"go-fintrack/config"
 	"go-fintrack/internal/payload/request"
 	"go-fintrack/internal/payload/response"
 	"go-fintrack/internal/service"
 	"go-fintrack/internal/utility"
-	"io/ioutil"
+	"math"
 	"net/http"
-	"sync"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -94,6 +92,7 @@ func (c *UserController) RegisterHandler(ctx *gin.Context) {
 // @Success 	200 {object} response.SuccessResponse
 // @Failure 	400 {object} response.SuccessResponse
 // @Failure 	401 {object} response.SuccessResponse
+// @Failure 	429 {object} response.SuccessResponse
 // @Router 		/auth/login [post]
 func (c *UserController) LoginHandler(ctx *gin.Context) {
 	var loginPayload request.LoginRequest
@@ -119,155 +118,181 @@ func (c *UserController) LoginHandler(ctx *gin.Context) {
 	}
 
 	// proses login
-	token, user, err := c.UserService.Login(loginPayload.EmailOrUsername, loginPayload.Password)
+	token, user, err := c.UserService.Login(loginPayload.EmailOrUsername, loginPayload.Password, utility.GetClientInfo(ctx))
 	if err != nil {
+		if respondLoginLocked(ctx, err) {
+			return
+		}
 		switch err {
 		case service.ErrInvalidCredentials:
 			utility.ErrorResponse(ctx, http.StatusUnauthorized, "Invalid email/username or password", nil)
+		case service.ErrAccountSuspended, service.ErrPasswordResetRequired:
+			utility.ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
 		default:
 			utility.InternalServerErrorResponse(ctx, "Failed to login", err)
 		}
 		return
 	}
 
+	if user.TwoFactorEnabled {
+		respondTwoFactorChallenge(ctx, token)
+		return
+	}
+
 	ctx.JSON(http.StatusOK, response.SuccessResponse{
 		ResponseStatus:  true,
 		ResponseMessage: "Login successful",
 		Data: response.LoginResponse{
-			Name:        user.Name,
-			AccessToken: token,
-			Expiration:  time.Now().Add(24 * time.Hour),
-			IsAdmin:     user.IsAdmin,
+			Name:          user.Name,
+			AccessToken:   token,
+			Expiration:    time.Now().Add(24 * time.Hour),
+			IsAdmin:       service.IsStaffRole(user.Role),
+			Role:          user.Role,
+			Permissions:   service.RolePermissions[user.Role],
+			EmailVerified: user.EmailVerified,
 		},
 	})
 }
 
-func (c *UserController) GoogleLogin(ctx *gin.Context) {
-	if config.GoogleOauthConfig == nil {
-		utility.InternalServerErrorResponse(ctx, "Google OAuth config is not initialized", errors.New("oauth config is nil"))
+// ForgotPasswordHandler godoc
+// @Summary 	Forgot password
+// @Description Send a password reset link to the given email if it is registered
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Param 		request body request.ForgotPasswordRequest true "Registered email"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Router 		/auth/forgot-password [post]
+func (c *UserController) ForgotPasswordHandler(ctx *gin.Context) {
+	var req request.ForgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
 		return
 	}
 
-	state := utility.GenerateRandomString(32)
-	url := config.GoogleOauthConfig.AuthCodeURL(state)
+	if err := c.UserService.ForgotPassword(req.Email); err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to process forgot password", err)
+		return
+	}
 
+	// response selalu sama agar tidak membocorkan email yang terdaftar
 	ctx.JSON(http.StatusOK, response.SuccessResponse{
 		ResponseStatus:  true,
-		ResponseMessage: "Successfully generated Google login URL",
-		Data: gin.H{
-			"redirect_url": url,
-			"state":        state,
-		},
+		ResponseMessage: "If the email is registered, a password reset link has been sent",
+		Data:            nil,
 	})
 }
 
-func (c *UserController) GoogleCallback(ctx *gin.Context) {
-	code := ctx.Query("code")
-	state := ctx.Query("state")
-
-	if code == "" || state == "" {
-		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request parameters", []response.ErrorDetail{
-			{
-				Field:   "code",
-				Message: "Authorization code is required",
-			},
-			{
-				Field:   "state",
-				Message: "Authorization state is required",
-			},
-		})
+// ResetPasswordHandler godoc
+// @Summary 	Reset password
+// @Description Set a new password using the token from the reset email
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Param 		request body request.ResetPasswordRequest true "Reset token and new password"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Router 		/auth/reset-password [post]
+func (c *UserController) ResetPasswordHandler(ctx *gin.Context) {
+	var req request.ResetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
 		return
 	}
 
-	userChan := make(chan *request.GoogleUser)
-	errorChan := make(chan error)
-
-	var wg sync.WaitGroup
-	wg.Add(1)
-
-	// timeout context
-	ctx.Request = ctx.Request.WithContext(
-		utility.ContextWithTimeout(ctx, 10*time.Second),
-	)
-
-	// goroutine for fetching user data
-	go func() {
-		defer wg.Done()
-
-		// exchange code with token
-		token, err := config.GoogleOauthConfig.Exchange(ctx, code)
-		if err != nil {
-			errorChan <- err
-			return
+	if err := c.UserService.ResetPassword(req.Token, req.Password); err != nil {
+		switch err {
+		case service.ErrWeakPassword, service.ErrInvalidResetToken:
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		default:
+			utility.InternalServerErrorResponse(ctx, "Failed to reset password", err)
 		}
+		return
+	}
 
-		// create http client with token
-		client := config.GoogleOauthConfig.Client(ctx, token)
-		resp, err := client.Get("https://www.googleapis.com/oauth2/v2/userinfo")
-		if err != nil {
-			errorChan <- err
-			return
-		}
-		defer resp.Body.Close()
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Password has been reset",
+		Data:            nil,
+	})
+}
 
-		// read response body
-		userData, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			errorChan <- err
-			return
-		}
+// VerifyEmailHandler godoc
+// @Summary 	Verify email
+// @Description Verify the account email using the token from the verification email
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Param 		request body request.VerifyEmailRequest true "Verification token"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Router 		/auth/verify-email [post]
+func (c *UserController) VerifyEmailHandler(ctx *gin.Context) {
+	var req request.VerifyEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
 
-		// unmarshal data user
-		var googleUser request.GoogleUser
-		if err := json.Unmarshal(userData, &googleUser); err != nil {
-			errorChan <- err
+	if err := c.UserService.VerifyEmail(req.Token); err != nil {
+		if err == service.ErrInvalidVerificationToken {
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
 			return
 		}
+		utility.InternalServerErrorResponse(ctx, "Failed to verify email", err)
+		return
+	}
 
-		userChan <- &googleUser
-	}()
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Email verified",
+		Data:            nil,
+	})
+}
 
-	// goroutine for cleanup
-	go func() {
-		wg.Wait()
-		close(userChan)
-		close(errorChan)
-	}()
+// ResendVerificationHandler godoc
+// @Summary 	Resend verification email
+// @Description Send a new verification email to the logged in user
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/auth/verify-email/resend [post]
+func (c *UserController) ResendVerificationHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
 
-	// handle response using select
-	select {
-	case user := <-userChan:
-		// save or delete user data ke database
-		dbUser, err := c.UserService.UpsertGoogleUser(ctx, user)
-		if err != nil {
-			utility.InternalServerErrorResponse(ctx, "Failed to process user data", err)
+	if err := c.UserService.ResendVerificationEmail(userID); err != nil {
+		if err == service.ErrEmailAlreadyVerified {
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
 			return
 		}
+		utility.InternalServerErrorResponse(ctx, "Failed to resend verification email", err)
+		return
+	}
 
-		token, err := utility.GenerateJWT(dbUser.ID, dbUser.Username, dbUser.IsAdmin)
-		if err != nil {
-			utility.InternalServerErrorResponse(ctx, "Failed to generate JWT", err)
-		}
-
-		ctx.JSON(http.StatusOK, response.SuccessResponse{
-			ResponseStatus:  true,
-			ResponseMessage: "Successfully authenticated with Google",
-			Data: gin.H{
-				"access_token": token,
-				"is_admin":     false,
-				"user":         user,
-			},
-		})
-
-	case err := <-errorChan:
-		utility.InternalServerErrorResponse(ctx, "Authentication failed", err)
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Verification email sent",
+		Data:            nil,
+	})
+}
 
-	case <-ctx.Request.Context().Done():
-		utility.ErrorResponse(ctx, http.StatusGatewayTimeout, "Request timeout", []response.ErrorDetail{
-			{
-				Field:   "timeout",
-				Message: "The request took too long to process",
-			},
-		})
+// respondLoginLocked mengirim 429 dengan header Retry-After jika login sedang dikunci
+func respondLoginLocked(ctx *gin.Context, err error) bool {
+	var lockedErr *service.LoginLockedError
+	if !errors.As(err, &lockedErr) {
+		return false
 	}
+
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(lockedErr.RetryAfter.Seconds()))))
+	utility.ErrorResponse(ctx, http.StatusTooManyRequests, lockedErr.Error(), nil)
+	return true
 }
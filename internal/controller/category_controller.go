@@ -27,13 +27,13 @@ type CategoryController struct {
 // @Failure 	401 {object} response.SuccessResponse
 // @Router 		/category [get]
 func (c *CategoryController) GetAllCategoriesHandler(ctx *gin.Context) {
-	userID, err := utility.GetUserIDFromContext(ctx)
+	scope, err := utility.GetWorkspaceScope(ctx)
 	if err != nil {
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
 	}
 
-	categories, err := c.CategoryService.GetCategories(userID)
+	categories, err := c.CategoryService.GetCategories(scope)
 	if err != nil {
 		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
 		return
@@ -59,9 +59,10 @@ func (c *CategoryController) GetAllCategoriesHandler(ctx *gin.Context) {
 // @Success 	200 {object} response.SuccessResponse
 // @Failure 	400 {object} response.SuccessResponse
 // @Failure 	401 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
 // @Router 		/category/{id} [get]
 func (c *CategoryController) GetCategoryIdHandler(ctx *gin.Context) {
-	userID, err := utility.GetUserIDFromContext(ctx)
+	scope, err := utility.GetWorkspaceScope(ctx)
 	if err != nil {
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
@@ -73,9 +74,9 @@ func (c *CategoryController) GetCategoryIdHandler(ctx *gin.Context) {
 		return
 	}
 
-	category, err := c.CategoryService.GetCategoryByID(uint(id), userID)
+	category, err := c.CategoryService.GetCategoryByID(uint(id), scope)
 	if err != nil {
-		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		respondResourceError(ctx, err)
 		return
 	}
 
@@ -97,9 +98,10 @@ func (c *CategoryController) GetCategoryIdHandler(ctx *gin.Context) {
 // @Success 	201 {object} response.SuccessResponse
 // @Failure 	400 {object} response.SuccessResponse
 // @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
 // @Router 		/category [post]
 func (c *CategoryController) CreateCategoryHandler(ctx *gin.Context) {
-	userID, err := utility.GetUserIDFromContext(ctx)
+	scope, err := utility.GetWorkspaceScope(ctx)
 	if err != nil {
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
@@ -111,9 +113,9 @@ func (c *CategoryController) CreateCategoryHandler(ctx *gin.Context) {
 		return
 	}
 
-	category, err := c.CategoryService.CreateCategory(&req, userID)
+	category, err := c.CategoryService.CreateCategory(&req, scope)
 	if err != nil {
-		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		respondResourceError(ctx, err)
 		return
 	}
 
@@ -136,9 +138,11 @@ func (c *CategoryController) CreateCategoryHandler(ctx *gin.Context) {
 // @Success 	200 {object} response.SuccessResponse
 // @Failure 	400 {object} response.SuccessResponse
 // @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
 // @Router 		/category/{id} [put]
 func (c *CategoryController) UpdateCategoryHandler(ctx *gin.Context) {
-	userID, err := utility.GetUserIDFromContext(ctx)
+	scope, err := utility.GetWorkspaceScope(ctx)
 	if err != nil {
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
@@ -156,9 +160,9 @@ func (c *CategoryController) UpdateCategoryHandler(ctx *gin.Context) {
 		return
 	}
 
-	category, err := c.CategoryService.UpdateCategory(uint(id), userID, &req)
+	category, err := c.CategoryService.UpdateCategory(uint(id), scope, &req)
 	if err != nil {
-		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		respondResourceError(ctx, err)
 		return
 	}
 
@@ -180,9 +184,11 @@ func (c *CategoryController) UpdateCategoryHandler(ctx *gin.Context) {
 // @Success 	200 {object} response.SuccessResponse
 // @Failure 	400 {object} response.SuccessResponse
 // @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
 // @Router 		/category/{id} [delete]
 func (c *CategoryController) DeleteCategoryHandler(ctx *gin.Context) {
-	userID, err := utility.GetUserIDFromContext(ctx)
+	scope, err := utility.GetWorkspaceScope(ctx)
 	if err != nil {
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
@@ -194,8 +200,8 @@ func (c *CategoryController) DeleteCategoryHandler(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.CategoryService.DeleteCategory(uint(id), userID); err != nil {
-		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+	if err := c.CategoryService.DeleteCategory(uint(id), scope); err != nil {
+		respondResourceError(ctx, err)
 		return
 	}
 
@@ -4,20 +4,52 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
 	"go-fintrack/internal/utility"
 	"io"
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
 type ChatRequest struct {
 	Message string `json:"message"`
 }
 
+type ChatController struct {
+	UserService *service.UserService
+}
+
+// buildChatPrompt menambahkan preferensi user ke prompt agar jawaban memakai bahasa, mata uang dan format angka user
+func buildChatPrompt(message string, preferences *response.PreferenceResponse) string {
+	if preferences == nil {
+		return message
+	}
+
+	loc, err := time.LoadLocation(preferences.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	return fmt.Sprintf(
+		"User preferences: reply in the language of locale %s, express amounts in %s formatted like %s, "+
+			"the user's current local time is %s (%s).\n\n%s",
+		preferences.Locale,
+		preferences.BaseCurrency,
+		utility.FormatAmount(1234567.89, preferences.NumberFormat),
+		time.Now().In(loc).Format("2006-01-02 15:04"),
+		preferences.Timezone,
+		message,
+	)
+}
+
 func cleanResponse(text string) string {
 	// Hapus tag XML tetapi pertahankan kontennya
 	re := regexp.MustCompile(`<think>(.*?)</think>`)
@@ -91,13 +123,25 @@ func prosesChunk(chunk string) string {
 	return strings.Join(processed, current)
 }
 
-func StreamChat(ctx *gin.Context) {
+func (c *ChatController) StreamChat(ctx *gin.Context) {
 	var req ChatRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
 		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
 		return
 	}
 
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	// preferensi gagal dimuat tidak menggagalkan chat, prompt dikirim apa adanya
+	preferences, err := c.UserService.GetPreferences(userID)
+	if err != nil {
+		logrus.Warnf("Failed to load chat preferences: %v", err)
+	}
+
 	// Set headers for SSE
 	ctx.Header("Content-Type", "text/event-stream")
 	ctx.Header("Cache-Control", "no-cache")
@@ -108,7 +152,7 @@ func StreamChat(ctx *gin.Context) {
 	// Prepare Ollama request body
 	ollamaBody := map[string]interface{}{
 		"model":  "deepseek-r1:7b",
-		"prompt": req.Message,
+		"prompt": buildChatPrompt(req.Message, preferences),
 		"stream": true,
 	}
 
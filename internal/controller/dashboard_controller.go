@@ -1,87 +1,325 @@
 package controller
 
 import (
+	"encoding/json"
+	"go-fintrack/internal/payload/request"
 	"go-fintrack/internal/payload/response"
 	"go-fintrack/internal/service"
 	"go-fintrack/internal/utility"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
 type DashboardController struct {
-	DashboardService *service.DashboardService
+	DashboardService *service.CachedDashboardService
 }
 
-func NewDashboardController(dashboardService *service.DashboardService) *DashboardController {
+func NewDashboardController(dashboardService *service.CachedDashboardService) *DashboardController {
 	return &DashboardController{
 		DashboardService: dashboardService,
 	}
 }
 
+// bindDashboardFilter membaca rentang dan granularity dari query, false jika response error sudah dikirim
+func bindDashboardFilter(ctx *gin.Context) (request.DashboardFilter, bool) {
+	var filter request.DashboardFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return filter, false
+	}
+	return filter, true
+}
+
+// respondCachedDashboard mengirim ETag dan 304 jika SPA sudah memegang data yang sama lewat If-None-Match
+func respondCachedDashboard(ctx *gin.Context, message string, dashboard *service.CachedDashboard) {
+	ctx.Header("ETag", dashboard.ETag)
+	// response bergantung pada token user, browser boleh menyimpan tapi wajib revalidasi
+	ctx.Header("Cache-Control", "private, no-cache")
+	if etagMatches(ctx.GetHeader("If-None-Match"), dashboard.ETag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: message,
+		Data:            json.RawMessage(dashboard.Body),
+	})
+}
+
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func respondDashboardError(ctx *gin.Context, message string, err error) {
+	if err == service.ErrInvalidDashboardRange || err == service.ErrInvalidComparison || err == service.ErrInvalidTrendCategories {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	utility.InternalServerErrorResponse(ctx, message, err)
+}
+
 // GetFinancialOverviewHandler godoc
 // @Summary 	Get financial overview
-// @Description Get user's financial overview including current balance, monthly income, monthly expense, and monthly savings
+// @Description Get user's financial overview: balance at the end of the range and income, expense and savings within it.
+// @Description Without dates the range is the current day/week/month/quarter/year in the user's timezone.
+// @Description Responses carry an ETag, send it back as If-None-Match to get 304 while the data is unchanged.
 // @Tags 		dashboard
 // @Accept 		json
 // @Produce 	json
 // @Security 	BearerAuth
+// @Param 		start_date query string false "Start date (YYYY-MM-DD)"
+// @Param 		end_date query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param 		granularity query string false "Period: day, week, month (default), quarter or year"
+// @Param 		If-None-Match header string false "ETag from a previous response"
 // @Success 	200 {object} response.SuccessResponse{data=response.RespFinancialOverview}
+// @Success 	304 "Not modified"
+// @Failure 	400 {object} response.SuccessResponse
 // @Failure 	401 {object} response.SuccessResponse
 // @Failure 	500 {object} response.SuccessResponse
 // @Router 		/dashboard/overview [get]
 func (c *DashboardController) GetFinancialOverviewHandler(ctx *gin.Context) {
-	userID, err := utility.GetUserIDFromContext(ctx)
+	scope, err := utility.GetWorkspaceScope(ctx)
 	if err != nil {
 		logrus.Errorf("Failed to get user ID from context: %v", err)
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
 	}
 
-	overview, err := c.DashboardService.GetFinancialOverview(userID)
+	filter, ok := bindDashboardFilter(ctx)
+	if !ok {
+		return
+	}
+
+	overview, err := c.DashboardService.GetFinancialOverview(ctx.Request.Context(), scope, filter)
 	if err != nil {
 		logrus.Errorf("Error getting financial overview: %v", err)
-		utility.InternalServerErrorResponse(ctx, "Failed to get financial overview", err)
+		respondDashboardError(ctx, "Failed to get financial overview", err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.SuccessResponse{
-		ResponseStatus:  true,
-		ResponseMessage: "Get Financial Overview successful",
-		Data:            overview,
-	})
+	respondCachedDashboard(ctx, "Get Financial Overview successful", overview)
 }
 
 // GetDashboardChartsHandler godoc
 // @Summary 	Get dashboard charts data
-// @Description Get user's dashboard charts including income vs expense, category distribution, and top expenses
+// @Description Get user's dashboard charts including income vs expense per period, category distribution, and top expenses.
+// @Description Without dates the chart covers the last 6 periods of the granularity in the user's timezone.
+// @Description Responses carry an ETag, send it back as If-None-Match to get 304 while the data is unchanged.
 // Tags 		dashboard
 // @Accept 		json
 // @Produce 	json
 // @Security 	BearerAuth
+// @Param 		start_date query string false "Start date (YYYY-MM-DD)"
+// @Param 		end_date query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param 		granularity query string false "Period: day, week, month (default), quarter or year"
+// @Param 		If-None-Match header string false "ETag from a previous response"
 // @Success 	200 {object} response.SuccessResponse{data=response.RespDashboardCharts}
+// @Success 	304 "Not modified"
+// @Failure 	400 {object} response.ErrorResponse
 // @Failure 	401 {object} response.ErrorResponse
 // @Failure 	500 {object} response.ErrorResponse
 // @Router 		/dashboard/charts [get]
 func (c *DashboardController) GetDashboardChartsHandler(ctx *gin.Context) {
-	userID, err := utility.GetUserIDFromContext(ctx)
+	scope, err := utility.GetWorkspaceScope(ctx)
 	if err != nil {
 		logrus.Errorf("Failed to get user ID from context: %v", err)
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
 	}
 
-	charts, err := c.DashboardService.GetDashboardCharts(userID)
+	filter, ok := bindDashboardFilter(ctx)
+	if !ok {
+		return
+	}
+
+	charts, err := c.DashboardService.GetDashboardCharts(ctx.Request.Context(), scope, filter)
 	if err != nil {
 		logrus.Errorf("Error getting dashboard charts: %v", err)
-		utility.InternalServerErrorResponse(ctx, "Failed to get dashboard charts", err)
+		respondDashboardError(ctx, "Failed to get dashboard charts", err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, response.SuccessResponse{
-		ResponseStatus:  true,
-		ResponseMessage: "Get Dashboard Charts successful",
-		Data:            charts,
-	})
+	respondCachedDashboard(ctx, "Get Dashboard Charts successful", charts)
+}
+
+// GetCashFlowForecastHandler godoc
+// @Summary 	Get cash-flow forecast
+// @Description Project daily balances for the next N days from the current balance, future-dated transactions,
+// @Description monthly recurring transactions detected from the last 3 months and the 90-day average variable spend per category.
+// @Description Returns the projected low point and the date of the first negative balance (null if none).
+// @Tags 		dashboard
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		days query int false "Number of days to project (1-365, default 30)"
+// @Param 		If-None-Match header string false "ETag from a previous response"
+// @Success 	200 {object} response.SuccessResponse{data=response.RespCashFlowForecast}
+// @Success 	304 "Not modified"
+// @Failure 	400 {object} response.ErrorResponse
+// @Failure 	401 {object} response.ErrorResponse
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/dashboard/forecast [get]
+func (c *DashboardController) GetCashFlowForecastHandler(ctx *gin.Context) {
+	scope, err := utility.GetWorkspaceScope(ctx)
+	if err != nil {
+		logrus.Errorf("Failed to get user ID from context: %v", err)
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.ForecastFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	forecast, err := c.DashboardService.GetCashFlowForecast(ctx.Request.Context(), scope, filter)
+	if err != nil {
+		logrus.Errorf("Error getting cash-flow forecast: %v", err)
+		respondDashboardError(ctx, "Failed to get cash-flow forecast", err)
+		return
+	}
+
+	respondCachedDashboard(ctx, "Get Cash-flow Forecast successful", forecast)
+}
+
+// GetPeriodComparisonHandler godoc
+// @Summary 	Compare two periods
+// @Description Compare per-category totals of two periods with absolute and percentage deltas, new and disappeared categories
+// @Description and the biggest movers. Each period uses the transaction filter semantics (inclusive start/end dates or the user's
+// @Description monthly cycle) and echoes its dates so the transactions can be listed with GET /transaction.
+// @Description Without current dates the preset compares the current week/month/quarter/year with the previous one; without
+// @Description previous dates the period right before current (previous cycle month or the same number of days) is used.
+// @Tags 		dashboard
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		preset query string false "week, month (default), quarter or year"
+// @Param 		current_start_date query string false "Current period start date (YYYY-MM-DD)"
+// @Param 		current_end_date query string false "Current period end date, inclusive (YYYY-MM-DD)"
+// @Param 		current_month query string false "Current period as monthly cycle (YYYY-MM), replaces current dates"
+// @Param 		previous_start_date query string false "Previous period start date (YYYY-MM-DD)"
+// @Param 		previous_end_date query string false "Previous period end date, inclusive (YYYY-MM-DD)"
+// @Param 		previous_month query string false "Previous period as monthly cycle (YYYY-MM), replaces previous dates"
+// @Param 		type query string false "expense (default) or income"
+// @Param 		top query int false "Number of biggest movers (1-50, default 5)"
+// @Param 		If-None-Match header string false "ETag from a previous response"
+// @Success 	200 {object} response.SuccessResponse{data=response.RespPeriodComparison}
+// @Success 	304 "Not modified"
+// @Failure 	400 {object} response.ErrorResponse
+// @Failure 	401 {object} response.ErrorResponse
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/dashboard/compare [get]
+func (c *DashboardController) GetPeriodComparisonHandler(ctx *gin.Context) {
+	scope, err := utility.GetWorkspaceScope(ctx)
+	if err != nil {
+		logrus.Errorf("Failed to get user ID from context: %v", err)
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.ComparisonFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	comparison, err := c.DashboardService.GetPeriodComparison(ctx.Request.Context(), scope, filter)
+	if err != nil {
+		logrus.Errorf("Error getting period comparison: %v", err)
+		respondDashboardError(ctx, "Failed to get period comparison", err)
+		return
+	}
+
+	respondCachedDashboard(ctx, "Get Period Comparison successful", comparison)
+}
+
+// GetCategoryTrendHandler godoc
+// @Summary 	Get category trend
+// @Description Get monthly totals per category over the range (user's monthly cycle), one dataset per category colored with
+// @Description the category's color. Without category_ids the top 5 categories by total in the range are used.
+// @Tags 		dashboard
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		start_date query string false "Start date (YYYY-MM-DD), default 6 months back"
+// @Param 		end_date query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param 		category_ids query []int false "Category IDs (max 10), repeat the parameter for each category" collectionFormat(multi)
+// @Param 		type query string false "expense (default) or income"
+// @Param 		If-None-Match header string false "ETag from a previous response"
+// @Success 	200 {object} response.SuccessResponse{data=response.RespCategoryTrend}
+// @Success 	304 "Not modified"
+// @Failure 	400 {object} response.ErrorResponse
+// @Failure 	401 {object} response.ErrorResponse
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/dashboard/category-trend [get]
+func (c *DashboardController) GetCategoryTrendHandler(ctx *gin.Context) {
+	scope, err := utility.GetWorkspaceScope(ctx)
+	if err != nil {
+		logrus.Errorf("Failed to get user ID from context: %v", err)
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.CategoryTrendFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	trend, err := c.DashboardService.GetCategoryTrend(ctx.Request.Context(), scope, filter)
+	if err != nil {
+		logrus.Errorf("Error getting category trend: %v", err)
+		respondDashboardError(ctx, "Failed to get category trend", err)
+		return
+	}
+
+	respondCachedDashboard(ctx, "Get Category Trend successful", trend)
+}
+
+// GetSpendingHeatmapHandler godoc
+// @Summary 	Get daily spending heatmap
+// @Description Get daily expense totals for every date of a calendar year, with totals and daily averages per day of week
+// @Description (starting at the user's first day of week) and weekday vs weekend. Averages only count days up to today.
+// @Tags 		dashboard
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		year query int false "Year, default the current year in the user's timezone"
+// @Param 		If-None-Match header string false "ETag from a previous response"
+// @Success 	200 {object} response.SuccessResponse{data=response.RespSpendingHeatmap}
+// @Success 	304 "Not modified"
+// @Failure 	400 {object} response.ErrorResponse
+// @Failure 	401 {object} response.ErrorResponse
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/dashboard/heatmap [get]
+func (c *DashboardController) GetSpendingHeatmapHandler(ctx *gin.Context) {
+	scope, err := utility.GetWorkspaceScope(ctx)
+	if err != nil {
+		logrus.Errorf("Failed to get user ID from context: %v", err)
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.HeatmapFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	heatmap, err := c.DashboardService.GetSpendingHeatmap(ctx.Request.Context(), scope, filter)
+	if err != nil {
+		logrus.Errorf("Error getting spending heatmap: %v", err)
+		respondDashboardError(ctx, "Failed to get spending heatmap", err)
+		return
+	}
+
+	respondCachedDashboard(ctx, "Get Spending Heatmap successful", heatmap)
 }
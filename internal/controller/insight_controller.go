@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type InsightController struct {
+	InsightService *service.InsightService
+}
+
+// GetInsightsHandler godoc
+// @Summary 	Get spending insights
+// @Description Get unusual spending flags of the active workspace, newest first. large_transaction marks an expense at least 3x
+// @Description the median of its category over the previous 180 days, category_pace marks a category whose spend this monthly cycle
+// @Description runs at least 50% above its typical pace. Days and monthly cycles follow the workspace owner's timezone and
+// @Description cycle_start_day. Flags are computed by a background job shortly after transactions change.
+// @Tags 		insights
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		kind query string false "large_transaction or category_pace"
+// @Param 		month query string false "Cycle month (YYYY-MM), default the last 3 months"
+// @Success 	200 {object} response.SuccessResponse{data=[]response.InsightResponse}
+// @Failure 	400 {object} response.ErrorResponse
+// @Failure 	401 {object} response.ErrorResponse
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/insights [get]
+func (c *InsightController) GetInsightsHandler(ctx *gin.Context) {
+	scope, err := utility.GetWorkspaceScope(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.InsightFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	insights, err := c.InsightService.GetInsights(scope, filter)
+	if err != nil {
+		logrus.Errorf("Error getting insights: %v", err)
+		utility.InternalServerErrorResponse(ctx, "Failed to get insights", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get Insights successful",
+		Data:            insights,
+	})
+}
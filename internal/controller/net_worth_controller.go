@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+type NetWorthController struct {
+	NetWorthService *service.NetWorthService
+}
+
+// GetNetWorthChartHandler godoc
+// @Summary 	Get net worth history
+// @Description Get the workspace net worth (cumulative balance of all transactions) at the end of each day or month,
+// @Description read from snapshots a background job rebuilds from transaction history. Periods without a snapshot carry
+// @Description the previous value. Without dates the range is the last 12 months or 30 days.
+// @Tags 		dashboard
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		start_date query string false "Start date (YYYY-MM-DD), daily history covers the last 366 days"
+// @Param 		end_date query string false "End date, inclusive (YYYY-MM-DD)"
+// @Param 		granularity query string false "Period: day or month (default)"
+// @Success 	200 {object} response.SuccessResponse{data=response.RespNetWorthChart}
+// @Failure 	400 {object} response.ErrorResponse
+// @Failure 	401 {object} response.ErrorResponse
+// @Failure 	500 {object} response.ErrorResponse
+// @Router 		/dashboard/net-worth [get]
+func (c *NetWorthController) GetNetWorthChartHandler(ctx *gin.Context) {
+	scope, err := utility.GetWorkspaceScope(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var filter request.NetWorthFilter
+	if err := ctx.ShouldBindQuery(&filter); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	chart, err := c.NetWorthService.GetNetWorthChart(scope, filter)
+	if err != nil {
+		if err == service.ErrInvalidNetWorthRange {
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		logrus.Errorf("Error getting net worth chart: %v", err)
+		utility.InternalServerErrorResponse(ctx, "Failed to get net worth chart", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get Net Worth Chart successful",
+		Data:            chart,
+	})
+}
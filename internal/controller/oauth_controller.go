@@ -0,0 +1,349 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OAuthController struct {
+	UserService     *service.UserService
+	OAuthService    *service.OAuthService
+	IdentityService *service.IdentityService
+}
+
+// cookie nonce OAuth, hanya dikirim ke endpoint auth dan tidak bisa dibaca JavaScript
+const (
+	oauthNonceCookie     = "oauth_nonce"
+	oauthNonceCookiePath = "/api/auth"
+)
+
+// setOAuthNonceCookie mengikat state ke browser yang memulai alur. SameSite Lax tetap terkirim saat kembali dari
+// redirect provider, tetapi tidak pada request lintas situs yang dipicu halaman lain.
+func setOAuthNonceCookie(ctx *gin.Context, nonce string, maxAge int) {
+	secure := ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https"
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oauthNonceCookie, nonce, maxAge, oauthNonceCookiePath, "", secure, true)
+}
+
+// WithProvider mengisi path parameter provider, dipakai route lama /auth/google/*
+func WithProvider(provider string, handler gin.HandlerFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Params = append(ctx.Params, gin.Param{Key: "provider", Value: provider})
+		handler(ctx)
+	}
+}
+
+// GetOAuthProvidersHandler godoc
+// @Summary 	Get OAuth providers
+// @Description Get the OAuth/OIDC providers that can be used to log in
+// @Tags 		auth
+// @Produce 	json
+// @Success 	200 {object} response.SuccessResponse{data=response.OAuthProviderListResponse}
+// @Router 		/auth/oauth/providers [get]
+func (c *OAuthController) GetOAuthProvidersHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get OAuth providers successful",
+		Data: response.OAuthProviderListResponse{
+			Providers: c.OAuthService.ListProviders(),
+		},
+	})
+}
+
+// OAuthLoginHandler godoc
+// @Summary 	Start OAuth login
+// @Description Generate the provider login URL. State and PKCE verifier are stored server side for 10 minutes.
+// @Description The response sets an HttpOnly oauth_nonce cookie that must be sent with the callback request.
+// @Tags 		auth
+// @Produce 	json
+// @Param 		provider path string true "Provider name, e.g. google or github"
+// @Success 	200 {object} response.SuccessResponse{data=response.OAuthLoginResponse}
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/auth/oauth/{provider}/login [get]
+func (c *OAuthController) OAuthLoginHandler(ctx *gin.Context) {
+	login, err := c.OAuthService.BeginAuth(ctx.Param("provider"))
+	if err != nil {
+		if errors.Is(err, service.ErrOAuthProviderNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to generate login URL", err)
+		return
+	}
+	setOAuthNonceCookie(ctx, login.Nonce, int(service.OAuthStateTTL.Seconds()))
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Successfully generated login URL",
+		Data:            login,
+	})
+}
+
+// OAuthCallbackHandler godoc
+// @Summary 	Complete OAuth login
+// @Description Validate the state issued by the login or link endpoint and exchange the code with PKCE.
+// @Description The state is only accepted together with the oauth_nonce cookie set by the same browser.
+// @Description Logs the user in, or links the identity to the account that started the link flow.
+// @Tags 		auth
+// @Produce 	json
+// @Param 		provider path string true "Provider name, e.g. google or github"
+// @Param 		code query string true "Authorization code"
+// @Param 		state query string true "State from the login endpoint"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Failure 	409 {object} response.SuccessResponse
+// @Failure 	504 {object} response.SuccessResponse
+// @Router 		/auth/oauth/{provider}/callback [get]
+func (c *OAuthController) OAuthCallbackHandler(ctx *gin.Context) {
+	code := ctx.Query("code")
+	state := ctx.Query("state")
+
+	if code == "" || state == "" {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid request parameters", []response.ErrorDetail{
+			{
+				Field:   "code",
+				Message: "Authorization code is required",
+			},
+			{
+				Field:   "state",
+				Message: "Authorization state is required",
+			},
+		})
+		return
+	}
+
+	// timeout untuk request ke provider
+	timeoutCtx, cancel := context.WithTimeout(ctx.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	// nonce sekali pakai seperti state, cookie langsung dihapus
+	nonce, _ := ctx.Cookie(oauthNonceCookie)
+	setOAuthNonceCookie(ctx, "", -1)
+
+	result, err := c.OAuthService.CompleteAuth(timeoutCtx, ctx.Param("provider"), state, nonce, code)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOAuthProviderNotFound):
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+		case errors.Is(err, service.ErrInvalidOAuthState), errors.Is(err, service.ErrOAuthEmailMissing):
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		case errors.Is(timeoutCtx.Err(), context.DeadlineExceeded):
+			utility.ErrorResponse(ctx, http.StatusGatewayTimeout, "Request timeout", []response.ErrorDetail{
+				{
+					Field:   "timeout",
+					Message: "The request took too long to process",
+				},
+			})
+		default:
+			utility.InternalServerErrorResponse(ctx, "Authentication failed", err)
+		}
+		return
+	}
+
+	if result.LinkUserID != nil {
+		c.completeLink(ctx, result)
+		return
+	}
+
+	dbUser, err := c.IdentityService.ResolveOAuthLogin(result.User)
+	if err != nil {
+		switch err {
+		case service.ErrOAuthAccountExists:
+			utility.ErrorResponse(ctx, http.StatusConflict, err.Error(), nil)
+		case service.ErrAccountInactive:
+			utility.ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
+		default:
+			utility.InternalServerErrorResponse(ctx, "Failed to process user data", err)
+		}
+		return
+	}
+
+	if err := c.UserService.CheckAccountStatus(dbUser); err != nil {
+		utility.ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	if dbUser.TwoFactorEnabled {
+		challenge, err := utility.GenerateChallengeJWT(dbUser.ID)
+		if err != nil {
+			utility.InternalServerErrorResponse(ctx, "Failed to generate JWT", err)
+			return
+		}
+		respondTwoFactorChallenge(ctx, challenge)
+		return
+	}
+
+	token, err := c.UserService.IssueToken(dbUser, utility.GetClientInfo(ctx))
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to generate JWT", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Successfully authenticated",
+		Data: gin.H{
+			"access_token": token,
+			"is_admin":     service.IsStaffRole(dbUser.Role),
+			"role":         dbUser.Role,
+			"user":         result.User,
+		},
+	})
+}
+
+// completeLink menghubungkan identitas hasil callback ke user yang memulai alur link. Callback tidak wajib login,
+// jadi alur link hanya diterima jika request membawa session yang sama dengan yang memulai link.
+func (c *OAuthController) completeLink(ctx *gin.Context, result *service.OAuthResult) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil || !result.LinkAllowed(userID, utility.GetSessionIDFromContext(ctx)) {
+		utility.ErrorResponse(ctx, http.StatusForbidden, service.ErrOAuthLinkSession.Error(), nil)
+		return
+	}
+
+	if err := c.UserService.CheckAccountActive(userID); err != nil {
+		if err == service.ErrAccountInactive {
+			utility.ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to check account status", err)
+		return
+	}
+
+	identity, err := c.IdentityService.LinkIdentity(userID, result.User)
+	if err != nil {
+		if err == service.ErrIdentityAlreadyLinked {
+			utility.ErrorResponse(ctx, http.StatusConflict, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to link identity", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Identity linked",
+		Data:            identity,
+	})
+}
+
+// GetIdentitiesHandler godoc
+// @Summary 	Get linked identities
+// @Description Get the OAuth identities linked to the logged in account and whether it has a password
+// @Tags 		auth
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=response.IdentityListResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/auth/identities [get]
+func (c *OAuthController) GetIdentitiesHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	identities, err := c.IdentityService.ListIdentities(userID)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get identities", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get identities successful",
+		Data:            identities,
+	})
+}
+
+// LinkIdentityHandler godoc
+// @Summary 	Link OAuth identity
+// @Description Generate the provider login URL for linking. The callback links the identity to the logged in account
+// @Description and must be sent with the same session token (Authorization header) and oauth_nonce cookie.
+// @Tags 		auth
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		provider path string true "Provider name, e.g. google or github"
+// @Success 	200 {object} response.SuccessResponse{data=response.OAuthLoginResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/auth/identities/link/{provider} [post]
+func (c *OAuthController) LinkIdentityHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	login, err := c.OAuthService.BeginLink(ctx.Param("provider"), userID, utility.GetSessionIDFromContext(ctx))
+	if err != nil {
+		if errors.Is(err, service.ErrOAuthProviderNotFound) {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		if errors.Is(err, service.ErrOAuthLinkSession) {
+			utility.ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to generate link URL", err)
+		return
+	}
+	setOAuthNonceCookie(ctx, login.Nonce, int(service.OAuthStateTTL.Seconds()))
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Successfully generated link URL",
+		Data:            login,
+	})
+}
+
+// UnlinkIdentityHandler godoc
+// @Summary 	Unlink OAuth identity
+// @Description Remove a linked identity. The last login method of an account without a password cannot be removed.
+// @Tags 		auth
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Identity ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/auth/identities/{id} [delete]
+func (c *OAuthController) UnlinkIdentityHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	identityID, ok := parseUintParam(ctx, "id", "Invalid identity ID")
+	if !ok {
+		return
+	}
+
+	if err := c.IdentityService.UnlinkIdentity(userID, identityID); err != nil {
+		switch err {
+		case service.ErrIdentityNotFound:
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+		case service.ErrLastLoginMethod:
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		default:
+			utility.InternalServerErrorResponse(ctx, "Failed to unlink identity", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Identity unlinked",
+		Data:            nil,
+	})
+}
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProfileHandler godoc
+// @Summary 	Get profile
+// @Description Get the logged in user's profile and preferences
+// @Tags 		me
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=response.ProfileResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/me [get]
+func (c *UserController) GetProfileHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	profile, err := c.UserService.GetProfile(userID)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get profile", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get profile successful",
+		Data:            profile,
+	})
+}
+
+// UpdateProfileHandler godoc
+// @Summary 	Update profile
+// @Description Update the name and/or username of the logged in user
+// @Tags 		me
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.UpdateProfileRequest true "Profile fields to update"
+// @Success 	200 {object} response.SuccessResponse{data=response.ProfileResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	409 {object} response.SuccessResponse
+// @Router 		/me [put]
+func (c *UserController) UpdateProfileHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.UpdateProfileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	profile, err := c.UserService.UpdateProfile(userID, req)
+	if err != nil {
+		if err == service.ErrUsernameTaken {
+			utility.ErrorResponse(ctx, http.StatusConflict, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to update profile", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Profile updated",
+		Data:            profile,
+	})
+}
+
+// ChangePasswordHandler godoc
+// @Summary 	Change password
+// @Description Change the password with the current password. Other sessions are logged out.
+// @Description Accounts created with OAuth that have no password yet can set one without current_password.
+// @Tags 		me
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.ChangePasswordRequest true "Current and new password"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/me/password [put]
+func (c *UserController) ChangePasswordHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.ChangePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	if err := c.UserService.ChangePassword(userID, utility.GetSessionIDFromContext(ctx), req); err != nil {
+		switch err {
+		case service.ErrWeakPassword, service.ErrInvalidCurrentPassword:
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		default:
+			utility.InternalServerErrorResponse(ctx, "Failed to change password", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Password changed, other sessions have been logged out",
+		Data:            nil,
+	})
+}
+
+// UploadProfilePictureHandler godoc
+// @Summary 	Upload profile picture
+// @Description Upload a JPEG, PNG or WebP profile picture (max 2 MB)
+// @Tags 		me
+// @Accept 		multipart/form-data
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		file formData file true "Profile picture"
+// @Success 	200 {object} response.SuccessResponse{data=response.ProfileResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/me/avatar [post]
+func (c *UserController) UploadProfilePictureHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Profile picture file is required", []response.ErrorDetail{
+			{
+				Field:   "file",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to read profile picture", err)
+		return
+	}
+	defer file.Close()
+
+	profile, err := c.UserService.UploadProfilePicture(userID, file)
+	if err != nil {
+		switch err {
+		case service.ErrProfilePicTooLarge, service.ErrProfilePicInvalidType:
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		default:
+			utility.InternalServerErrorResponse(ctx, "Failed to upload profile picture", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Profile picture updated",
+		Data:            profile,
+	})
+}
+
+// GetPreferencesHandler godoc
+// @Summary 	Get preferences
+// @Description Get base currency, timezone, locale, first day of week and number format
+// @Tags 		me
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=response.PreferenceResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/me/preferences [get]
+func (c *UserController) GetPreferencesHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	preferences, err := c.UserService.GetPreferences(userID)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get preferences", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get preferences successful",
+		Data:            preferences,
+	})
+}
+
+// UpdatePreferencesHandler godoc
+// @Summary 	Update preferences
+// @Description Update preferences used by the dashboard, exports and chat. Omitted fields are unchanged.
+// @Tags 		me
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.UpdatePreferencesRequest true "Preferences to update"
+// @Success 	200 {object} response.SuccessResponse{data=response.PreferenceResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/me/preferences [put]
+func (c *UserController) UpdatePreferencesHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.UpdatePreferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	preferences, err := c.UserService.UpdatePreferences(userID, req)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to update preferences", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Preferences updated",
+		Data:            preferences,
+	})
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"errors"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondResourceError memetakan AccessError dari Authorizer ke 404/403, error lain tetap 400
+func respondResourceError(ctx *gin.Context, err error) {
+	var accessErr *service.AccessError
+	if errors.As(err, &accessErr) {
+		status := http.StatusNotFound
+		if errors.Is(err, service.ErrResourceForbidden) {
+			status = http.StatusForbidden
+		}
+		utility.ErrorResponse(ctx, status, err.Error(), nil)
+		return
+	}
+
+	utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+}
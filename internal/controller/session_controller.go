@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionController struct {
+	SessionService *service.SessionService
+}
+
+// GetSessionsHandler godoc
+// @Summary 	Get active sessions
+// @Description Get every device where the account is currently signed in
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=response.SessionListResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	500 {object} response.SuccessResponse
+// @Router 		/auth/sessions [get]
+func (c *SessionController) GetSessionsHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	sessions, err := c.SessionService.ListSessions(userID, utility.GetSessionIDFromContext(ctx))
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get sessions", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get sessions successful",
+		Data: response.SessionListResponse{
+			Sessions: sessions,
+		},
+	})
+}
+
+// RevokeSessionHandler godoc
+// @Summary 	Revoke session
+// @Description Sign out a single device by session ID
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Session ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/auth/sessions/{id} [delete]
+func (c *SessionController) RevokeSessionHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	sessionID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid session ID", nil)
+		return
+	}
+
+	if err := c.SessionService.RevokeSession(userID, uint(sessionID)); err != nil {
+		if err == service.ErrSessionNotFound {
+			utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to revoke session", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Session revoked",
+		Data:            nil,
+	})
+}
+
+// LogoutHandler godoc
+// @Summary 	Logout
+// @Description Revoke the session of the current access token
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/auth/logout [post]
+func (c *SessionController) LogoutHandler(ctx *gin.Context) {
+	if err := c.SessionService.RevokeSessionByTokenID(utility.GetSessionIDFromContext(ctx)); err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to logout", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Logout successful",
+		Data:            nil,
+	})
+}
@@ -29,6 +29,7 @@ type TransactionController struct {
 // @Param 		end_date 	query 	string 	false 	"End date (YYYY-MM-DD)"
 // @Param 		category_id	query 	int 	false 	"Category ID"
 // @Param 		type 		query 	string 	false 	"Transaction type (income/expense)"
+// @Param 		month 		query 	string 	false 	"Monthly cycle (YYYY-MM), overrides start_date/end_date"
 // @Param 		page 		query 	int 	false 	"Page number"
 // @Param 		limit 		query 	int 	false 	"Limit per page"
 // @Success 	200 {object} response.SuccessResponse{data=response.TransactionListResponse}
@@ -36,7 +37,7 @@ type TransactionController struct {
 // @Failure 	401 {object} response.SuccessResponse
 // @Router 		/transaction [get]
 func (c *TransactionController) GetTransactionHandler(ctx *gin.Context) {
-	userID, err := utility.GetUserIDFromContext(ctx)
+	scope, err := utility.GetWorkspaceScope(ctx)
 	if err != nil {
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
@@ -51,7 +52,7 @@ func (c *TransactionController) GetTransactionHandler(ctx *gin.Context) {
 
 	logrus.Infof("Received filter: %+v", filter) // debug
 
-	transactions, err := c.TransactionService.GetTransactionByUser(userID, filter)
+	transactions, err := c.TransactionService.GetTransactionByUser(scope, filter)
 	if err != nil {
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
@@ -75,9 +76,11 @@ func (c *TransactionController) GetTransactionHandler(ctx *gin.Context) {
 // @Success 	201 {object} response.SuccessResponse{data=response.TransactionResponse}
 // @Failure 	400 {object} response.SuccessResponse
 // @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
 // @Router 		/transaction [post]
 func (c *TransactionController) CreateTransactionHandler(ctx *gin.Context) {
-	userID, err := utility.GetUserIDFromContext(ctx)
+	scope, err := utility.GetWorkspaceScope(ctx)
 	if err != nil {
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
@@ -89,9 +92,9 @@ func (c *TransactionController) CreateTransactionHandler(ctx *gin.Context) {
 		return
 	}
 
-	transaction, err := c.TransactionService.CreateTransaction(userID, req)
+	transaction, err := c.TransactionService.CreateTransaction(scope, req)
 	if err != nil {
-		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		respondResourceError(ctx, err)
 		return
 	}
 
@@ -114,9 +117,11 @@ func (c *TransactionController) CreateTransactionHandler(ctx *gin.Context) {
 // @Success 	201 {object} response.SuccessResponse{data=response.TransactionResponse}
 // @Failure 	400 {object} response.SuccessResponse
 // @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
 // @Router 		/transaction/{id} [put]
 func (c *TransactionController) UpdateTransactionHandler(ctx *gin.Context) {
-	userID, err := utility.GetUserIDFromContext(ctx)
+	scope, err := utility.GetWorkspaceScope(ctx)
 	if err != nil {
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
@@ -134,9 +139,9 @@ func (c *TransactionController) UpdateTransactionHandler(ctx *gin.Context) {
 		return
 	}
 
-	transaction, err := c.TransactionService.UpdateTransaction(userID, uint(transactionID), req)
+	transaction, err := c.TransactionService.UpdateTransaction(scope, uint(transactionID), req)
 	if err != nil {
-		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		respondResourceError(ctx, err)
 		return
 	}
 
@@ -158,9 +163,11 @@ func (c *TransactionController) UpdateTransactionHandler(ctx *gin.Context) {
 // @Success 	200 {object} response.SuccessResponse
 // @Failure 	400 {object} response.SuccessResponse
 // @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
 // @Router 		/transaction/{id} [delete]
 func (c *TransactionController) DeleteTransactionHandler(ctx *gin.Context) {
-	userID, err := utility.GetUserIDFromContext(ctx)
+	scope, err := utility.GetWorkspaceScope(ctx)
 	if err != nil {
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
 		return
@@ -172,8 +179,8 @@ func (c *TransactionController) DeleteTransactionHandler(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.TransactionService.DeleteTransaction(userID, uint(transactionID)); err != nil {
-		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+	if err := c.TransactionService.DeleteTransaction(scope, uint(transactionID)); err != nil {
+		respondResourceError(ctx, err)
 		return
 	}
 
@@ -184,6 +191,46 @@ func (c *TransactionController) DeleteTransactionHandler(ctx *gin.Context) {
 	})
 }
 
+// RestoreTransactionHandler godoc
+// @Summary 	Restore transaction
+// @Description Restore a deleted transaction by ID
+// @Tags 		transactions
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Transaction ID"
+// @Success 	200 {object} response.SuccessResponse{data=response.TransactionResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/transaction/{id}/restore [post]
+func (c *TransactionController) RestoreTransactionHandler(ctx *gin.Context) {
+	scope, err := utility.GetWorkspaceScope(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	transactionID, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid transaction ID", nil)
+		return
+	}
+
+	transaction, err := c.TransactionService.RestoreTransaction(scope, uint(transactionID))
+	if err != nil {
+		respondResourceError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Transaction restored",
+		Data:            transaction,
+	})
+}
+
 // ExportTransactionsExcelHandler godoc
 // @Summary 	Export transactions to Excel
 // @Description Export transactions to Excel file
@@ -195,12 +242,13 @@ func (c *TransactionController) DeleteTransactionHandler(ctx *gin.Context) {
 // @Param 		end_date 	query 	string 	false 	"End date (YYYY-MM-DD)"
 // @Param 		category_id	query 	int 	false 	"Category ID"
 // @Param 		type 		query 	string 	false 	"Transaction type (income/expense)"
+// @Param 		month 		query 	string 	false 	"Monthly report for the user's cycle (YYYY-MM), overrides start_date/end_date"
 // @Success 	200 {file} file "Excel file download"
 // @Failure 	400 {object} response.SuccessResponse
 // @Failure 	401 {object} response.SuccessResponse
 // @Router 		/transaction/export [get]
 func (c *TransactionController) ExportTransactionsExcelHandler(ctx *gin.Context) {
-	userID, err := utility.GetUserIDFromContext(ctx)
+	scope, err := utility.GetWorkspaceScope(ctx)
 	if err != nil {
 		logrus.Errorf("Error getting user ID: %v", err)
 		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
@@ -214,7 +262,7 @@ func (c *TransactionController) ExportTransactionsExcelHandler(ctx *gin.Context)
 		return
 	}
 
-	buffer, err := c.TransactionService.ExportTransactionsExcel(userID, filter)
+	buffer, err := c.TransactionService.ExportTransactionsExcel(scope, filter)
 	if err != nil {
 		logrus.Errorf("Error exporting transactions: %v", err)
 		utility.InternalServerErrorResponse(ctx, "Failed while export Excel", err)
@@ -223,6 +271,9 @@ func (c *TransactionController) ExportTransactionsExcelHandler(ctx *gin.Context)
 
 	// Set response headers
 	filename := fmt.Sprintf("transactions_%s.xlsx", time.Now().Format("20060102"))
+	if filter.Month != "" {
+		filename = fmt.Sprintf("transactions_%s.xlsx", filter.Month)
+	}
 	ctx.Header("Content-Description", "File Transfer")
 	ctx.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
 	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
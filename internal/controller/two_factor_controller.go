@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func respondTwoFactorChallenge(ctx *gin.Context, challengeToken string) {
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Two-factor authentication required",
+		Data: response.TwoFactorChallengeResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+			Expiration:        time.Now().Add(utility.ChallengeTokenTTL),
+		},
+	})
+}
+
+// SetupTwoFactorHandler godoc
+// @Summary 	Start two-factor setup
+// @Description Generate a TOTP secret, otpauth URI and QR code PNG (base64) for an authenticator app
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=response.TwoFactorSetupResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/auth/2fa/setup [post]
+func (c *UserController) SetupTwoFactorHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	setup, err := c.UserService.SetupTwoFactor(userID)
+	if err != nil {
+		if err == service.ErrTwoFactorAlreadyEnabled {
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		utility.InternalServerErrorResponse(ctx, "Failed to setup two-factor authentication", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Scan the QR code with your authenticator app and confirm with a code",
+		Data:            setup,
+	})
+}
+
+// ConfirmTwoFactorHandler godoc
+// @Summary 	Confirm two-factor setup
+// @Description Enable two-factor authentication with a code from the authenticator app and get recovery codes
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.TwoFactorCodeRequest true "TOTP code"
+// @Success 	200 {object} response.SuccessResponse{data=response.TwoFactorConfirmResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/auth/2fa/confirm [post]
+func (c *UserController) ConfirmTwoFactorHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	recoveryCodes, err := c.UserService.ConfirmTwoFactor(userID, req.Code)
+	if err != nil {
+		switch err {
+		case service.ErrTwoFactorAlreadyEnabled, service.ErrTwoFactorNotSetup, service.ErrInvalidTwoFactorCode:
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		default:
+			utility.InternalServerErrorResponse(ctx, "Failed to confirm two-factor authentication", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Two-factor authentication enabled, store the recovery codes in a safe place",
+		Data: response.TwoFactorConfirmResponse{
+			RecoveryCodes: recoveryCodes,
+		},
+	})
+}
+
+// DisableTwoFactorHandler godoc
+// @Summary 	Disable two-factor authentication
+// @Description Disable two-factor authentication with a TOTP or recovery code
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.TwoFactorCodeRequest true "TOTP or recovery code"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/auth/2fa/disable [post]
+func (c *UserController) DisableTwoFactorHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.TwoFactorCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	if err := c.UserService.DisableTwoFactor(userID, req.Code); err != nil {
+		switch err {
+		case service.ErrTwoFactorNotEnabled, service.ErrInvalidTwoFactorCode:
+			utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+		default:
+			utility.InternalServerErrorResponse(ctx, "Failed to disable two-factor authentication", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Two-factor authentication disabled",
+		Data:            nil,
+	})
+}
+
+// TwoFactorLoginHandler godoc
+// @Summary 	Login second step
+// @Description Exchange the challenge token from login and a TOTP or recovery code for an access token
+// @Tags 		auth
+// @Accept 		json
+// @Produce 	json
+// @Param 		request body request.TwoFactorLoginRequest true "Challenge token and code"
+// @Success 	200 {object} response.SuccessResponse{data=response.LoginResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	429 {object} response.SuccessResponse
+// @Router 		/auth/login/2fa [post]
+func (c *UserController) TwoFactorLoginHandler(ctx *gin.Context) {
+	var req request.TwoFactorLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	token, user, err := c.UserService.VerifyTwoFactorLogin(req.ChallengeToken, req.Code, utility.GetClientInfo(ctx))
+	if err != nil {
+		if respondLoginLocked(ctx, err) {
+			return
+		}
+		switch err {
+		case utility.ErrInvalidChallengeToken, service.ErrInvalidTwoFactorCode:
+			utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		case service.ErrAccountSuspended, service.ErrPasswordResetRequired:
+			utility.ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
+		default:
+			utility.InternalServerErrorResponse(ctx, "Failed to login", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Login successful",
+		Data: response.LoginResponse{
+			Name:          user.Name,
+			AccessToken:   token,
+			Expiration:    time.Now().Add(utility.TokenTTL),
+			IsAdmin:       service.IsStaffRole(user.Role),
+			Role:          user.Role,
+			Permissions:   service.RolePermissions[user.Role],
+			EmailVerified: user.EmailVerified,
+		},
+	})
+}
@@ -0,0 +1,401 @@
+package controller
+
+import (
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WorkspaceController struct {
+	WorkspaceService *service.WorkspaceService
+}
+
+func respondWorkspaceError(ctx *gin.Context, message string, err error) {
+	switch err {
+	case service.ErrWorkspaceAccessDenied, service.ErrWorkspaceOwnerRequired, service.ErrInvitationEmailMismatch:
+		utility.ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
+	case service.ErrWorkspaceMemberNotFound:
+		utility.ErrorResponse(ctx, http.StatusNotFound, err.Error(), nil)
+	case service.ErrLastWorkspaceOwner, service.ErrPersonalWorkspace, service.ErrAlreadyWorkspaceMember, service.ErrInvalidInvitation:
+		utility.ErrorResponse(ctx, http.StatusBadRequest, err.Error(), nil)
+	default:
+		utility.InternalServerErrorResponse(ctx, message, err)
+	}
+}
+
+// parseUintParam membaca path parameter numerik, false jika response error sudah dikirim
+func parseUintParam(ctx *gin.Context, name, message string) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param(name), 10, 64)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusBadRequest, message, nil)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// GetWorkspacesHandler godoc
+// @Summary 	Get workspaces
+// @Description Get all workspaces the logged in user is a member of, including the personal workspace
+// @Tags 		workspace
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Success 	200 {object} response.SuccessResponse{data=response.WorkspaceListResponse}
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/workspaces [get]
+func (c *WorkspaceController) GetWorkspacesHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	workspaces, err := c.WorkspaceService.ListWorkspaces(userID, utility.GetWorkspaceClaim(ctx))
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to get workspaces", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get workspaces successful",
+		Data: response.WorkspaceListResponse{
+			Workspaces: workspaces,
+		},
+	})
+}
+
+// CreateWorkspaceHandler godoc
+// @Summary 	Create workspace
+// @Description Create a shared workspace (household), the creator becomes its owner
+// @Tags 		workspace
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.CreateWorkspaceRequest true "Workspace name"
+// @Success 	201 {object} response.SuccessResponse{data=response.WorkspaceResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Router 		/workspaces [post]
+func (c *WorkspaceController) CreateWorkspaceHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.CreateWorkspaceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	workspace, err := c.WorkspaceService.CreateWorkspace(userID, req.Name)
+	if err != nil {
+		utility.InternalServerErrorResponse(ctx, "Failed to create workspace", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Workspace created",
+		Data:            workspace,
+	})
+}
+
+// DeleteWorkspaceHandler godoc
+// @Summary 	Delete workspace
+// @Description Delete a shared workspace with all of its categories and transactions, owner only
+// @Tags 		workspace
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Workspace ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Router 		/workspaces/{id} [delete]
+func (c *WorkspaceController) DeleteWorkspaceHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	workspaceID, ok := parseUintParam(ctx, "id", "Invalid workspace ID")
+	if !ok {
+		return
+	}
+
+	if err := c.WorkspaceService.DeleteWorkspace(userID, workspaceID); err != nil {
+		respondWorkspaceError(ctx, "Failed to delete workspace", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Workspace deleted",
+		Data:            nil,
+	})
+}
+
+// SwitchWorkspaceHandler godoc
+// @Summary 	Switch workspace
+// @Description Get a new access token for the current session with the given workspace as the active workspace
+// @Tags 		workspace
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Workspace ID"
+// @Success 	200 {object} response.SuccessResponse{data=response.WorkspaceSwitchResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Router 		/workspaces/{id}/switch [post]
+func (c *WorkspaceController) SwitchWorkspaceHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	workspaceID, ok := parseUintParam(ctx, "id", "Invalid workspace ID")
+	if !ok {
+		return
+	}
+
+	result, err := c.WorkspaceService.SwitchWorkspace(userID, utility.GetSessionIDFromContext(ctx), workspaceID)
+	if err != nil {
+		respondWorkspaceError(ctx, "Failed to switch workspace", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Workspace switched",
+		Data:            result,
+	})
+}
+
+// GetWorkspaceMembersHandler godoc
+// @Summary 	Get workspace members
+// @Description Get members of a workspace and their roles
+// @Tags 		workspace
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Workspace ID"
+// @Success 	200 {object} response.SuccessResponse{data=response.WorkspaceMemberListResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Router 		/workspaces/{id}/members [get]
+func (c *WorkspaceController) GetWorkspaceMembersHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	workspaceID, ok := parseUintParam(ctx, "id", "Invalid workspace ID")
+	if !ok {
+		return
+	}
+
+	members, err := c.WorkspaceService.ListMembers(userID, workspaceID)
+	if err != nil {
+		respondWorkspaceError(ctx, "Failed to get workspace members", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Get workspace members successful",
+		Data: response.WorkspaceMemberListResponse{
+			Members: members,
+		},
+	})
+}
+
+// UpdateWorkspaceMemberHandler godoc
+// @Summary 	Update workspace member role
+// @Description Change the role (owner, editor, viewer) of a workspace member, owner only
+// @Tags 		workspace
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Workspace ID"
+// @Param 		userId path int true "Member user ID"
+// @Param 		request body request.UpdateWorkspaceMemberRequest true "New role"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/workspaces/{id}/members/{userId} [put]
+func (c *WorkspaceController) UpdateWorkspaceMemberHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	workspaceID, ok := parseUintParam(ctx, "id", "Invalid workspace ID")
+	if !ok {
+		return
+	}
+	memberUserID, ok := parseUintParam(ctx, "userId", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	var req request.UpdateWorkspaceMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	if err := c.WorkspaceService.UpdateMemberRole(userID, workspaceID, memberUserID, req.Role); err != nil {
+		respondWorkspaceError(ctx, "Failed to update workspace member", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Workspace member updated",
+		Data:            nil,
+	})
+}
+
+// RemoveWorkspaceMemberHandler godoc
+// @Summary 	Remove workspace member
+// @Description Remove a member from a workspace (owner only) or leave a workspace by using your own user ID
+// @Tags 		workspace
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Workspace ID"
+// @Param 		userId path int true "Member user ID"
+// @Success 	200 {object} response.SuccessResponse
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Failure 	404 {object} response.SuccessResponse
+// @Router 		/workspaces/{id}/members/{userId} [delete]
+func (c *WorkspaceController) RemoveWorkspaceMemberHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	workspaceID, ok := parseUintParam(ctx, "id", "Invalid workspace ID")
+	if !ok {
+		return
+	}
+	memberUserID, ok := parseUintParam(ctx, "userId", "Invalid user ID")
+	if !ok {
+		return
+	}
+
+	if err := c.WorkspaceService.RemoveMember(userID, workspaceID, memberUserID); err != nil {
+		respondWorkspaceError(ctx, "Failed to remove workspace member", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Workspace member removed",
+		Data:            nil,
+	})
+}
+
+// InviteWorkspaceMemberHandler godoc
+// @Summary 	Invite workspace member
+// @Description Send an email invitation to join a shared workspace as editor or viewer, owner only
+// @Tags 		workspace
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		id path int true "Workspace ID"
+// @Param 		request body request.InviteWorkspaceMemberRequest true "Invitee email and role"
+// @Success 	201 {object} response.SuccessResponse{data=response.WorkspaceInvitationResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Router 		/workspaces/{id}/invitations [post]
+func (c *WorkspaceController) InviteWorkspaceMemberHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	workspaceID, ok := parseUintParam(ctx, "id", "Invalid workspace ID")
+	if !ok {
+		return
+	}
+
+	var req request.InviteWorkspaceMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	invitation, err := c.WorkspaceService.InviteMember(userID, workspaceID, req.Email, req.Role)
+	if err != nil {
+		respondWorkspaceError(ctx, "Failed to invite workspace member", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Invitation sent",
+		Data:            invitation,
+	})
+}
+
+// AcceptWorkspaceInvitationHandler godoc
+// @Summary 	Accept workspace invitation
+// @Description Join a workspace with the token from the invitation email, the logged in email must match the invitation
+// @Tags 		workspace
+// @Accept 		json
+// @Produce 	json
+// @Security 	BearerAuth
+// @Param 		request body request.AcceptWorkspaceInvitationRequest true "Invitation token"
+// @Success 	200 {object} response.SuccessResponse{data=response.WorkspaceResponse}
+// @Failure 	400 {object} response.SuccessResponse
+// @Failure 	401 {object} response.SuccessResponse
+// @Failure 	403 {object} response.SuccessResponse
+// @Router 		/workspaces/invitations/accept [post]
+func (c *WorkspaceController) AcceptWorkspaceInvitationHandler(ctx *gin.Context) {
+	userID, err := utility.GetUserIDFromContext(ctx)
+	if err != nil {
+		utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+		return
+	}
+
+	var req request.AcceptWorkspaceInvitationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		utility.BindingErrorResponse(ctx, err)
+		return
+	}
+
+	workspace, err := c.WorkspaceService.AcceptInvitation(userID, req.Token)
+	if err != nil {
+		respondWorkspaceError(ctx, "Failed to accept invitation", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response.SuccessResponse{
+		ResponseStatus:  true,
+		ResponseMessage: "Invitation accepted",
+		Data:            workspace,
+	})
+}
@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIToken adalah personal access token untuk script dan integrasi
+type APIToken struct {
+	gorm.Model
+	UserID      uint   `gorm:"not null;index"`
+	Name        string `gorm:"type:varchar(100);not null"`
+	TokenHash   string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	TokenPrefix string `gorm:"type:varchar(20);not null"`  // potongan awal token untuk ditampilkan
+	Scopes      string `gorm:"type:varchar(255);not null"` // dipisah koma
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+}
+
+func (t *APIToken) ScopeList() []string {
+	if t.Scopes == "" {
+		return []string{}
+	}
+	return strings.Split(t.Scopes, ",")
+}
+
+func (t *APIToken) HasScope(scope string) bool {
+	for _, s := range t.ScopeList() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *APIToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && now.After(*t.ExpiresAt)
+}
@@ -8,10 +8,11 @@ import (
 
 type Category struct {
 	gorm.Model
-	UserID    uint   `gorm:"not null;index"`
-	Name      string `gorm:"type:varchar(100);not null"`
-	Color     string `gorm:"type:varchar(50);default:'bg-blue-100'"`
-	IconColor string `gorm:"type:varchar(50);default:'text-blue-500'"`
+	WorkspaceID uint   `gorm:"not null;default:0;index"`
+	UserID      uint   `gorm:"not null;index"` // member yang membuat kategori
+	Name        string `gorm:"type:varchar(100);not null"`
+	Color       string `gorm:"type:varchar(50);default:'bg-blue-100'"`
+	IconColor   string `gorm:"type:varchar(50);default:'text-blue-500'"`
 }
 
 func (c *Category) BeforeCreate(tx *gorm.DB) error {
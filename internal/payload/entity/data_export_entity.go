@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// status job export data
+const (
+	DataExportPending    = "pending"
+	DataExportProcessing = "processing"
+	DataExportCompleted  = "completed"
+	DataExportFailed     = "failed"
+)
+
+// DataExport adalah job pembuatan ZIP berisi seluruh data milik user, diproses oleh worker AccountService
+type DataExport struct {
+	gorm.Model
+	UserID      uint   `gorm:"not null;index"`
+	Status      string `gorm:"type:varchar(20);not null;index"`
+	FilePath    string `gorm:"type:varchar(255)"` // path file ZIP di ExportDir, tidak dilayani sebagai static file
+	FileSize    int64
+	Error       string `gorm:"type:text"`
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time `gorm:"index"`
+}
+
+func (e *DataExport) IsDownloadable(now time.Time) bool {
+	return e.Status == DataExportCompleted && e.ExpiresAt != nil && now.Before(*e.ExpiresAt)
+}
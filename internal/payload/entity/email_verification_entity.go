@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type EmailVerification struct {
+	gorm.Model
+	UserID    uint      `gorm:"not null;index"`
+	Email     string    `gorm:"type:varchar(255);not null"` // email yang diverifikasi, token tidak berlaku jika email user berubah
+	TokenHash string    `gorm:"type:varchar(64);uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+}
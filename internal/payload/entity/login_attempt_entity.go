@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// LoginAttempt menyimpan jumlah gagal login per key (akun atau IP)
+type LoginAttempt struct {
+	ID            uint      `gorm:"primarykey"`
+	AttemptKey    string    `gorm:"type:varchar(255);uniqueIndex;not null"`
+	Failures      int       `gorm:"not null;default:0"`
+	LastFailureAt time.Time `gorm:"not null"`
+	LockedUntil   time.Time
+	UpdatedAt     time.Time
+}
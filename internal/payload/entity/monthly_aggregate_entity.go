@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// MonthlyAggregate adalah total transaksi aktif per workspace, user pencatat, kategori, bulan kalender dan tipe.
+// Diperbarui dalam database transaction yang sama dengan perubahan transaksi, bisa dibangun ulang dengan
+// perintah rebuild-aggregates jika tidak konsisten.
+type MonthlyAggregate struct {
+	ID               uint      `gorm:"primarykey"`
+	WorkspaceID      uint      `gorm:"not null;uniqueIndex:idx_monthly_aggregate,priority:1"`
+	UserID           uint      `gorm:"not null;uniqueIndex:idx_monthly_aggregate,priority:2;index"`
+	CategoryID       uint      `gorm:"not null;uniqueIndex:idx_monthly_aggregate,priority:3"`
+	Month            time.Time `gorm:"not null;uniqueIndex:idx_monthly_aggregate,priority:4"` // tanggal 1 bulan kalender, 00:00 UTC
+	Type             string    `gorm:"size:20;not null;uniqueIndex:idx_monthly_aggregate,priority:5"`
+	Total            float64   `gorm:"not null;default:0"`
+	TransactionCount int64     `gorm:"not null;default:0"`
+	UpdatedAt        time.Time
+}
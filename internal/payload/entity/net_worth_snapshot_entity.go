@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// granularity snapshot net worth
+const (
+	NetWorthDaily   = "day"
+	NetWorthMonthly = "month"
+)
+
+// NetWorthSnapshot adalah net worth workspace di akhir satu hari atau bulan kalender. Saat ini net worth adalah
+// saldo kumulatif seluruh transaksi; snapshot dibangun ulang worker NetWorthService dari riwayat transaksi.
+type NetWorthSnapshot struct {
+	ID          uint      `gorm:"primarykey"`
+	WorkspaceID uint      `gorm:"not null;uniqueIndex:idx_net_worth_snapshot,priority:1"`
+	Granularity string    `gorm:"size:10;not null;uniqueIndex:idx_net_worth_snapshot,priority:2"`
+	Date        time.Time `gorm:"not null;uniqueIndex:idx_net_worth_snapshot,priority:3"` // hari terakhir periode, 00:00 UTC
+	Income      float64   `gorm:"not null;default:0"`                                     // income di dalam periode
+	Expense     float64   `gorm:"not null;default:0"`                                     // expense di dalam periode
+	NetWorth    float64   `gorm:"not null;default:0"`                                     // saldo kumulatif di akhir periode
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// OAuthState menyimpan state dan PKCE verifier login OAuth yang sedang berjalan, dihapus saat callback
+type OAuthState struct {
+	ID        uint   `gorm:"primarykey"`
+	StateHash string `gorm:"type:varchar(64);uniqueIndex;not null"`
+	Provider  string `gorm:"type:varchar(50);not null"`
+	Verifier  string `gorm:"type:varchar(128);not null"`
+	// terisi jika alur dimulai dari endpoint link identity, callback menghubungkan identitas ke user ini
+	LinkUserID *uint
+	// session yang memulai alur link, callback link harus dikirim dengan session yang sama
+	LinkSessionID string    `gorm:"type:varchar(64)"`
+	ExpiresAt     time.Time `gorm:"not null;index"`
+	CreatedAt     time.Time
+}
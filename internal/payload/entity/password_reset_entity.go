@@ -0,0 +1,15 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PasswordReset struct {
+	gorm.Model
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"type:varchar(64);uniqueIndex;not null"` // sha256 dari token, token asli hanya dikirim via email
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+}
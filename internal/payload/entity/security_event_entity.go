@@ -0,0 +1,33 @@
+package entity
+
+import "gorm.io/gorm"
+
+// tipe security event
+const (
+	SecurityEventAccountLocked   = "account_locked"
+	SecurityEventIPLocked        = "ip_locked"
+	SecurityEventAccountUnlocked = "account_unlocked"
+	// aksi admin terhadap akun user
+	SecurityEventAccountSuspended   = "account_suspended"
+	SecurityEventAccountReactivated = "account_reactivated"
+	SecurityEventRoleChanged        = "role_changed"
+	SecurityEventPasswordResetForce = "password_reset_forced"
+	SecurityEventAccountDeleted     = "account_deleted"
+	// identitas OAuth yang terhubung ke akun
+	SecurityEventIdentityLinked   = "identity_linked"
+	SecurityEventIdentityUnlinked = "identity_unlinked"
+	// perubahan kredensial oleh user sendiri
+	SecurityEventPasswordChanged = "password_changed"
+	// penghapusan akun yang diminta user
+	SecurityEventDeletionScheduled = "account_deletion_scheduled"
+	SecurityEventDeletionCanceled  = "account_deletion_canceled"
+)
+
+type SecurityEvent struct {
+	gorm.Model
+	UserID    *uint  `gorm:"index"`
+	Type      string `gorm:"type:varchar(50);not null;index"`
+	IPAddress string `gorm:"type:varchar(64)"`
+	UserAgent string `gorm:"type:varchar(512)"`
+	Details   string `gorm:"type:text"`
+}
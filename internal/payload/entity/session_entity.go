@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Session struct {
+	gorm.Model
+	UserID     uint       `gorm:"not null;index"`
+	TokenID    string     `gorm:"type:varchar(64);uniqueIndex;not null"` // jti/sid di dalam JWT
+	UserAgent  string     `gorm:"type:varchar(512)"`
+	IPAddress  string     `gorm:"type:varchar(64)"`
+	LastUsedAt time.Time  `gorm:"not null"`
+	ExpiresAt  time.Time  `gorm:"not null"`
+	RevokedAt  *time.Time `gorm:"index"`
+}
+
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// jenis anomali pengeluaran
+const (
+	AnomalyLargeTransaction = "large_transaction" // satu expense jauh di atas nominal biasa kategorinya
+	AnomalyCategoryPace     = "category_pace"     // pengeluaran kategori siklus bulanan berjalan di atas laju normal
+)
+
+// SpendingAnomaly adalah hasil analisis pengeluaran tidak biasa di workspace, dihitung ulang oleh worker
+// InsightService setiap kali transaksi workspace berubah
+type SpendingAnomaly struct {
+	ID            uint      `gorm:"primarykey"`
+	WorkspaceID   uint      `gorm:"not null;index"`
+	Kind          string    `gorm:"type:varchar(30);not null"`
+	TransactionID *uint     `gorm:"index"` // hanya untuk large_transaction
+	CategoryID    uint      `gorm:"not null"`
+	Date          time.Time `gorm:"not null"` // tanggal transaksi, atau hari analisis untuk category_pace
+	Month         time.Time `gorm:"not null"` // tanggal 1 bulan siklus pemilik workspace (utility.CycleMonthOf), 00:00 UTC
+	Amount        float64   `gorm:"not null"` // nominal transaksi atau pengeluaran kategori siklus berjalan
+	Baseline      float64   `gorm:"not null"` // median nominal kategori atau pengeluaran normal sampai hari yang sama
+	Ratio         float64   `gorm:"not null"`
+	DetectedAt    time.Time `gorm:"not null"`
+}
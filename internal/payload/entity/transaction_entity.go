@@ -8,12 +8,13 @@ import (
 
 type Transaction struct {
 	gorm.Model
-	UserID      uint      `gorm:"not null"`
+	WorkspaceID uint      `gorm:"not null;default:0;index;index:idx_transactions_workspace_date,priority:1"`
+	UserID      uint      `gorm:"not null"` // member yang mencatat transaksi
 	CategoryID  uint      `gorm:"not null"`
 	Amount      float64   `gorm:"not null"`
 	Type        string    `gorm:"size:20;not null"` // income atau expense
 	Description string    `gorm:"type:text"`
-	Date        time.Time `gorm:"not null"`
+	Date        time.Time `gorm:"not null;index:idx_transactions_workspace_date,priority:2"`
 	User        User      `gorm:"foreignKey:UserID"`
 	Category    Category  `gorm:"foreignKey:CategoryID"`
 }
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// UserActivity mencatat hari ketika user aktif (maksimal satu baris per user per hari), dipakai untuk statistik DAU/MAU
+type UserActivity struct {
+	ID           uint      `gorm:"primarykey"`
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_user_activity_day"`
+	ActivityDate time.Time `gorm:"type:date;not null;uniqueIndex:idx_user_activity_day;index"`
+}
+
+// ChatUsage mencatat satu pesan chat tanpa menyimpan isi pesannya
+type ChatUsage struct {
+	ID        uint      `gorm:"primarykey"`
+	UserID    uint      `gorm:"not null;index"`
+	CreatedAt time.Time `gorm:"index"`
+}
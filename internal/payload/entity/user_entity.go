@@ -1,14 +1,43 @@
 package entity
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// role user, permission tiap role didefinisikan di service.RolePermissions
+const (
+	RoleUser            = "user"
+	RoleSupportReadonly = "support-readonly"
+	RoleAdmin           = "admin"
+	RoleSuperadmin      = "superadmin"
+)
 
 type User struct {
 	gorm.Model
-	Name       string `gorm:"type:varchar(255);not null"`
-	Email      string `gorm:"type:varchar(255);unique;not null"`
-	Username   string `gorm:"type:varchar(50);unique;not null"`
-	Password   string `gorm:"type:varchar(255);omitempty"`
-	IsAdmin    bool   `gorm:"type:boolean;default:false"`
-	Provider   string `gorm:"type:varchar(50);omitempty"`
-	ProfilePic string `gorm:"type:varchar(255);omitempty"`
+	Name            string `gorm:"type:varchar(255);not null"`
+	Email           string `gorm:"type:varchar(255);unique;not null"`
+	Username        string `gorm:"type:varchar(50);unique;not null"`
+	Password        string `gorm:"type:varchar(255);omitempty"`
+	Role            string `gorm:"type:varchar(30);not null;default:user;index"`
+	Provider        string `gorm:"type:varchar(50);omitempty"`
+	ProfilePic      string `gorm:"type:varchar(255);omitempty"`
+	EmailVerified   bool   `gorm:"type:boolean;default:false"`
+	EmailVerifiedAt *time.Time
+	// 2FA TOTP, secret terisi sejak enrollment tetapi baru aktif setelah dikonfirmasi
+	TwoFactorEnabled  bool   `gorm:"type:boolean;default:false"`
+	TwoFactorSecret   string `gorm:"type:varchar(64)"`
+	TwoFactorLastStep int64  `gorm:"default:0"` // step TOTP terakhir yang dipakai, mencegah replay kode
+	// status akun yang dikelola admin
+	SuspendedAt           *time.Time `gorm:"index"`
+	SuspendedReason       string     `gorm:"type:varchar(255)"`
+	PasswordResetRequired bool       `gorm:"type:boolean;default:false"`
+	LastLoginAt           *time.Time
+	// penghapusan akun oleh user sendiri, data dihapus permanen setelah masa tenggang
+	DeletionScheduledFor *time.Time `gorm:"index"`
+}
+
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt != nil
 }
@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserIdentity menghubungkan akun user dengan identitas di provider OAuth/OIDC.
+// Satu user bisa memiliki password dan beberapa identitas sekaligus.
+type UserIdentity struct {
+	gorm.Model
+	UserID         uint   `gorm:"not null;index"`
+	Provider       string `gorm:"type:varchar(50);not null;uniqueIndex:idx_identity_provider_subject;index:idx_identity_user_provider"`
+	ProviderUserID string `gorm:"type:varchar(255);not null;uniqueIndex:idx_identity_provider_subject"`
+	Email          string `gorm:"type:varchar(255)"`
+	LastLoginAt    *time.Time
+	User           User `gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// format angka: pemisah ribuan lalu pemisah desimal
+const (
+	NumberFormatCommaDot   = "comma_dot"   // 1,234.56
+	NumberFormatDotComma   = "dot_comma"   // 1.234,56
+	NumberFormatSpaceComma = "space_comma" // 1 234,56
+)
+
+// default preferensi untuk user yang belum pernah menyimpan preferensi
+const (
+	DefaultBaseCurrency   = "IDR"
+	DefaultTimezone       = "Asia/Jakarta"
+	DefaultLocale         = "id-ID"
+	DefaultFirstDayOfWeek = int(time.Monday)
+	DefaultNumberFormat   = NumberFormatDotComma
+	DefaultCycleStartDay  = 1
+)
+
+type UserPreference struct {
+	gorm.Model
+	UserID         uint   `gorm:"not null;uniqueIndex"`
+	BaseCurrency   string `gorm:"type:varchar(3);not null"`
+	Timezone       string `gorm:"type:varchar(64);not null"`
+	Locale         string `gorm:"type:varchar(35);not null"`
+	FirstDayOfWeek int    `gorm:"not null"` // 0 = minggu, 1 = senin, mengikuti time.Weekday
+	NumberFormat   string `gorm:"type:varchar(20);not null"`
+	CycleStartDay  int    `gorm:"not null;default:1"` // tanggal mulai siklus bulanan (1-31), mis. tanggal gajian
+}
+
+func DefaultUserPreference(userID uint) UserPreference {
+	return UserPreference{
+		UserID:         userID,
+		BaseCurrency:   DefaultBaseCurrency,
+		Timezone:       DefaultTimezone,
+		Locale:         DefaultLocale,
+		FirstDayOfWeek: DefaultFirstDayOfWeek,
+		NumberFormat:   DefaultNumberFormat,
+		CycleStartDay:  DefaultCycleStartDay,
+	}
+}
+
+// Location mengembalikan zona waktu user, fallback ke UTC jika nama zona tidak dikenal
+func (p *UserPreference) Location() *time.Location {
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// role member workspace
+const (
+	WorkspaceRoleOwner  = "owner"
+	WorkspaceRoleEditor = "editor"
+	WorkspaceRoleViewer = "viewer"
+)
+
+// Workspace (household) memiliki kategori dan transaksi yang bisa dipakai bersama oleh beberapa member
+type Workspace struct {
+	gorm.Model
+	Name       string `gorm:"type:varchar(100);not null"`
+	OwnerID    uint   `gorm:"not null;index;uniqueIndex:idx_workspaces_personal_owner,where:is_personal AND deleted_at IS NULL"`
+	IsPersonal bool   `gorm:"type:boolean;default:false"` // workspace default milik setiap user (satu per user), tidak bisa dihapus
+}
+
+type WorkspaceMember struct {
+	gorm.Model
+	WorkspaceID uint      `gorm:"not null;uniqueIndex:idx_workspace_member"`
+	UserID      uint      `gorm:"not null;uniqueIndex:idx_workspace_member;index"`
+	Role        string    `gorm:"type:varchar(20);not null"`
+	Workspace   Workspace `gorm:"foreignKey:WorkspaceID"`
+	User        User      `gorm:"foreignKey:UserID"`
+}
+
+func (m *WorkspaceMember) CanWrite() bool {
+	return m.Role == WorkspaceRoleOwner || m.Role == WorkspaceRoleEditor
+}
+
+type WorkspaceInvitation struct {
+	gorm.Model
+	WorkspaceID uint      `gorm:"not null;index"`
+	Email       string    `gorm:"type:varchar(255);not null"`
+	Role        string    `gorm:"type:varchar(20);not null"`
+	TokenHash   string    `gorm:"type:varchar(64);uniqueIndex;not null"`
+	InvitedByID uint      `gorm:"not null"`
+	ExpiresAt   time.Time `gorm:"not null"`
+	AcceptedAt  *time.Time
+	Workspace   Workspace `gorm:"foreignKey:WorkspaceID"`
+}
@@ -0,0 +1,6 @@
+package request
+
+// Password wajib untuk akun yang memiliki password, akun OAuth tanpa password boleh mengosongkan
+type AccountDeletionRequest struct {
+	Password string `json:"password"`
+}
@@ -0,0 +1,23 @@
+package request
+
+type AdminUserFilter struct {
+	Search string `form:"search"` // cari berdasarkan nama, email atau username
+	Status string `form:"status" binding:"omitempty,oneof=active suspended"`
+	Role   string `form:"role" binding:"omitempty,oneof=user support-readonly admin superadmin"`
+	Page   int    `form:"page,default=1" binding:"min=1"`
+	Limit  int    `form:"limit,default=10" binding:"min=1,max=100"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+type UpdateUserRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user support-readonly admin superadmin"`
+}
+
+type AdminStatsFilter struct {
+	StartDate   string `form:"start_date"` // format 2006-01-02
+	EndDate     string `form:"end_date"`   // format 2006-01-02
+	Granularity string `form:"granularity,default=day" binding:"omitempty,oneof=day month"`
+}
@@ -0,0 +1,48 @@
+package request
+
+type DashboardFilter struct {
+	StartDate   string `form:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate     string `form:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	Granularity string `form:"granularity,default=month" binding:"omitempty,oneof=day week month quarter year"`
+}
+
+// ForecastFilter menentukan jumlah hari proyeksi arus kas, default 30 hari
+type ForecastFilter struct {
+	Days int `form:"days,default=30" binding:"omitempty,min=1,max=365"`
+}
+
+// NetWorthFilter menentukan rentang dan granularity chart net worth, hanya harian atau bulanan
+type NetWorthFilter struct {
+	StartDate   string `form:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate     string `form:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	Granularity string `form:"granularity,default=month" binding:"omitempty,oneof=day month"`
+}
+
+// ComparisonFilter membandingkan dua periode. Setiap periode memakai arti yang sama dengan TransactionFilter:
+// start_date dan end_date inklusif, atau month untuk siklus bulanan user. Tanpa periode current, kedua periode
+// diambil dari preset (periode berjalan dan sebelumnya); tanpa periode previous, dipakai periode sebelum current.
+type ComparisonFilter struct {
+	Preset            string `form:"preset,default=month" binding:"omitempty,oneof=week month quarter year"`
+	CurrentStartDate  string `form:"current_start_date" binding:"omitempty,datetime=2006-01-02"`
+	CurrentEndDate    string `form:"current_end_date" binding:"omitempty,datetime=2006-01-02"`
+	CurrentMonth      string `form:"current_month" binding:"omitempty,datetime=2006-01"`
+	PreviousStartDate string `form:"previous_start_date" binding:"omitempty,datetime=2006-01-02"`
+	PreviousEndDate   string `form:"previous_end_date" binding:"omitempty,datetime=2006-01-02"`
+	PreviousMonth     string `form:"previous_month" binding:"omitempty,datetime=2006-01"`
+	Type              string `form:"type,default=expense" binding:"omitempty,oneof=income expense"`
+	Top               int    `form:"top,default=5" binding:"omitempty,min=1,max=50"`
+}
+
+// CategoryTrendFilter memilih kategori untuk tren bulanan, category_ids dikirim berulang (?category_ids=1&category_ids=2).
+// Tanpa category_ids dipakai kategori dengan total terbesar di rentang.
+type CategoryTrendFilter struct {
+	StartDate   string `form:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate     string `form:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	CategoryIDs []uint `form:"category_ids" binding:"omitempty,max=10,dive,min=1"`
+	Type        string `form:"type,default=expense" binding:"omitempty,oneof=income expense"`
+}
+
+// HeatmapFilter menentukan tahun heatmap expense harian, default tahun berjalan di timezone user
+type HeatmapFilter struct {
+	Year int `form:"year" binding:"omitempty,min=1970,max=9999"`
+}
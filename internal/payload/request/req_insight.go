@@ -0,0 +1,7 @@
+package request
+
+// InsightFilter membatasi daftar anomali, tanpa month semua anomali 3 bulan terakhir dikembalikan
+type InsightFilter struct {
+	Kind  string `form:"kind" binding:"omitempty,oneof=large_transaction category_pace"`
+	Month string `form:"month" binding:"omitempty,datetime=2006-01"`
+}
@@ -0,0 +1,12 @@
+package request
+
+// OAuthUser adalah profil user yang sudah dinormalisasi dari userinfo provider OAuth/OIDC
+type OAuthUser struct {
+	Provider       string `json:"provider"`
+	ProviderUserID string `json:"provider_user_id"`
+	Email          string `json:"email"`
+	EmailVerified  bool   `json:"email_verified"`
+	Name           string `json:"name"`
+	Username       string `json:"username,omitempty"` // login GitHub, kosong untuk provider lain
+	Picture        string `json:"picture"`
+}
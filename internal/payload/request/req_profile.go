@@ -0,0 +1,24 @@
+package request
+
+// field kosong (nil) tidak diubah
+type UpdateProfileRequest struct {
+	Name     *string `json:"name" binding:"omitempty,min=3,max=50"`
+	Username *string `json:"username" binding:"omitempty,username"`
+}
+
+// CurrentPassword boleh kosong hanya untuk akun OAuth yang belum punya password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+	ConfirmPassword string `json:"confirm_password" binding:"required,eqfield=NewPassword"`
+}
+
+type UpdatePreferencesRequest struct {
+	BaseCurrency   *string `json:"base_currency" binding:"omitempty,iso4217"`
+	Timezone       *string `json:"timezone" binding:"omitempty,timezone"`
+	Locale         *string `json:"locale" binding:"omitempty,bcp47_language_tag"`
+	FirstDayOfWeek *int    `json:"first_day_of_week" binding:"omitempty,min=0,max=6"`
+	NumberFormat   *string `json:"number_format" binding:"omitempty,oneof=comma_dot dot_comma space_comma"`
+	// tanggal mulai siklus bulanan, bulan yang lebih pendek memakai tanggal terakhirnya
+	CycleStartDay *int `json:"cycle_start_day" binding:"omitempty,min=1,max=31"`
+}
@@ -0,0 +1,7 @@
+package request
+
+// ClientInfo berisi informasi device yang dicatat pada session
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
@@ -17,8 +17,10 @@ type UpdateTransactionRequest struct {
 }
 
 type TransactionFilter struct {
-	StartDate  string `form:"start_date"` // format 2006-01-02
-	EndDate    string `form:"end_date"`   // format 2006-01-02
+	StartDate string `form:"start_date"` // format 2006-01-02
+	EndDate   string `form:"end_date"`   // format 2006-01-02
+	// siklus bulanan user (format 2006-01), menggantikan start_date dan end_date
+	Month      string `form:"month" binding:"omitempty,datetime=2006-01"`
 	CategoryID uint   `form:"category_id"`
 	Type       string `form:"type" binding:"omitempty,oneof=income expense"`
 	Page       int    `form:"page,default=1"`
@@ -0,0 +1,25 @@
+package request
+
+// WorkspaceScope menentukan workspace yang diakses dan member yang melakukan aksi, diisi oleh middleware WorkspaceContext
+type WorkspaceScope struct {
+	WorkspaceID uint
+	UserID      uint
+	Role        string
+}
+
+type CreateWorkspaceRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type InviteWorkspaceMemberRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=editor viewer"`
+}
+
+type UpdateWorkspaceMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner editor viewer"`
+}
+
+type AcceptWorkspaceInvitationRequest struct {
+	Token string `json:"token" binding:"required"`
+}
@@ -0,0 +1,17 @@
+package response
+
+import "time"
+
+type DataExportResponse struct {
+	ID          uint       `json:"id"`
+	Status      string     `json:"status"` // pending, processing, completed, failed
+	FileSize    int64      `json:"file_size"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+type AccountDeletionResponse struct {
+	ScheduledFor time.Time `json:"scheduled_for"`
+}
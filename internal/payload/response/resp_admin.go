@@ -0,0 +1,46 @@
+package response
+
+import "time"
+
+type AdminUserResponse struct {
+	ID                    uint       `json:"id"`
+	Name                  string     `json:"name"`
+	Email                 string     `json:"email"`
+	Username              string     `json:"username"`
+	Role                  string     `json:"role"`
+	Provider              string     `json:"provider"`
+	EmailVerified         bool       `json:"email_verified"`
+	TwoFactorEnabled      bool       `json:"two_factor_enabled"`
+	Suspended             bool       `json:"suspended"`
+	SuspendedAt           *time.Time `json:"suspended_at"`
+	SuspendedReason       string     `json:"suspended_reason"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	LastLoginAt           *time.Time `json:"last_login_at"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+type AdminUserListResponse struct {
+	Users      []AdminUserResponse `json:"users"`
+	Pagination Pagination          `json:"pagination"`
+}
+
+type AdminUserStats struct {
+	TransactionCount int64 `json:"transaction_count"`
+	CategoryCount    int64 `json:"category_count"`
+	ActiveSessions   int64 `json:"active_sessions"`
+	APITokens        int64 `json:"api_tokens"`
+}
+
+type AdminUserDetailResponse struct {
+	AdminUserResponse
+	Stats AdminUserStats `json:"stats"`
+}
+
+type RoleResponse struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+type RoleListResponse struct {
+	Roles []RoleResponse `json:"roles"`
+}
@@ -0,0 +1,64 @@
+package response
+
+// statistik platform hanya berisi jumlah (count), tidak ada nominal atau deskripsi milik user
+
+type PlatformOverviewResponse struct {
+	TotalUsers         int64            `json:"total_users"`
+	UsersByProvider    map[string]int64 `json:"users_by_provider"`
+	VerifiedUsers      int64            `json:"verified_users"`
+	SuspendedUsers     int64            `json:"suspended_users"`
+	DailyActiveUsers   int64            `json:"daily_active_users"`
+	MonthlyActiveUsers int64            `json:"monthly_active_users"` // user aktif 30 hari terakhir
+	TotalTransactions  int64            `json:"total_transactions"`
+	ChatMessages30d    int64            `json:"chat_messages_30d"`
+}
+
+type SignupStatsPoint struct {
+	Period     string           `json:"period"`
+	Total      int64            `json:"total"`
+	ByProvider map[string]int64 `json:"by_provider"`
+}
+
+type ActiveUserStatsPoint struct {
+	Period      string `json:"period"`
+	ActiveUsers int64  `json:"active_users"`
+}
+
+type TransactionVolumePoint struct {
+	Period  string `json:"period"`
+	Total   int64  `json:"total"`
+	Income  int64  `json:"income"`
+	Expense int64  `json:"expense"`
+}
+
+type ChatUsagePoint struct {
+	Period   string `json:"period"`
+	Messages int64  `json:"messages"`
+	Users    int64  `json:"users"`
+}
+
+type StatsRange struct {
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	Granularity string `json:"granularity"`
+}
+
+type SignupStatsResponse struct {
+	StatsRange
+	Points []SignupStatsPoint `json:"points"`
+}
+
+type ActiveUserStatsResponse struct {
+	StatsRange
+	Points []ActiveUserStatsPoint `json:"points"`
+}
+
+type TransactionVolumeResponse struct {
+	StatsRange
+	Points []TransactionVolumePoint `json:"points"`
+}
+
+type ChatUsageResponse struct {
+	StatsRange
+	Points []ChatUsagePoint `json:"points"`
+}
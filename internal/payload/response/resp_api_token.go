@@ -0,0 +1,22 @@
+package response
+
+import "time"
+
+type APITokenResponse struct {
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"token_prefix"`
+	Scopes      []string   `json:"scopes"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type APITokenCreatedResponse struct {
+	APITokenResponse
+	Token string `json:"token"` // hanya ditampilkan sekali saat dibuat
+}
+
+type APITokenListResponse struct {
+	Tokens []APITokenResponse `json:"tokens"`
+}
@@ -1,11 +1,23 @@
 package response
 
+// DashboardRange adalah rentang tanggal (inklusif) yang dipakai perhitungan dashboard
+type DashboardRange struct {
+	StartDate   string `json:"start_date"`
+	EndDate     string `json:"end_date"`
+	Granularity string `json:"granularity"`
+	Timezone    string `json:"timezone"`
+}
+
 // Financial Overview
 type RespFinancialOverview struct {
-	CurrentBalance float64 `json:"current_balance"`
-	MonthlyIncome  float64 `json:"monthly_income"`
-	MonthlyExpense float64 `json:"monthly_expense"`
-	TotalSavings   float64 `json:"total_savings"`
+	CurrentBalance float64 `json:"current_balance"` // saldo sampai akhir rentang
+	// income, expense dan selisihnya di dalam rentang, default periode berjalan sesuai granularity
+	MonthlyIncome  float64        `json:"monthly_income"`
+	MonthlyExpense float64        `json:"monthly_expense"`
+	TotalSavings   float64        `json:"total_savings"`
+	Range          DashboardRange `json:"range"`
+	// preferensi user untuk format tampilan nominal dan tanggal
+	Preferences PreferenceResponse `json:"preferences"`
 }
 
 // Expense Analysis
@@ -18,6 +30,7 @@ type ChartDataset struct {
 
 type RespIncomeVsExpense struct {
 	Labels   []string       `json:"labels"`
+	Keys     []string       `json:"keys"` // key ISO 8601 per periode, urutannya sama dengan labels
 	Datasets []ChartDataset `json:"datasets"`
 }
 
@@ -33,7 +46,7 @@ type TopExpenses struct {
 	Labels   []string `json:"labels"`
 	Datasets []struct {
 		Data            []float64 `json:"data"`
-		BackgroundColor string    `json:"background_color"`
+		BackgroundColor []string  `json:"background_color"` // satu warna per kategori
 	} `json:"datasets"`
 }
 
@@ -41,4 +54,130 @@ type RespDashboardCharts struct {
 	IncomeVsExpense      RespIncomeVsExpense  `json:"income_vs_expense"`
 	CategoryDistribution CategoryDistribution `json:"category_distribution"`
 	TopExpenses          TopExpenses          `json:"top_expenses"`
+	Range                DashboardRange       `json:"range"`
+	Preferences          PreferenceResponse   `json:"preferences"`
+}
+
+// Cash-flow Forecast
+type ForecastDay struct {
+	Date    string  `json:"date"`
+	Income  float64 `json:"income"`
+	Expense float64 `json:"expense"` // termasuk rata-rata pengeluaran variabel harian
+	Balance float64 `json:"balance"` // saldo di akhir hari
+}
+
+type ForecastRecurringItem struct {
+	CategoryID uint    `json:"category_id"`
+	Category   string  `json:"category"`
+	Type       string  `json:"type"`
+	Amount     float64 `json:"amount"`
+	DayOfMonth int     `json:"day_of_month"`
+}
+
+type ForecastCategorySpend struct {
+	CategoryID   uint    `json:"category_id"`
+	Category     string  `json:"category"`
+	DailyAverage float64 `json:"daily_average"`
+}
+
+type RespCashFlowForecast struct {
+	StartDate         string  `json:"start_date"` // hari pertama proyeksi, besok di timezone user
+	EndDate           string  `json:"end_date"`
+	CurrentBalance    float64 `json:"current_balance"` // saldo sampai hari ini
+	LowestBalance     float64 `json:"lowest_balance"`
+	LowestBalanceDate string  `json:"lowest_balance_date"`
+	// tanggal pertama saldo proyeksi negatif, null jika saldo tetap positif
+	FirstNegativeDate  *string                 `json:"first_negative_date"`
+	DailyVariableSpend float64                 `json:"daily_variable_spend"`
+	Days               []ForecastDay           `json:"days"`
+	RecurringItems     []ForecastRecurringItem `json:"recurring_items"`
+	VariableSpend      []ForecastCategorySpend `json:"variable_spend"`
+	Preferences        PreferenceResponse      `json:"preferences"`
+}
+
+// Net Worth, bentuknya sama dengan chart income vs expense
+type RespNetWorthChart struct {
+	Labels   []string       `json:"labels"`
+	Keys     []string       `json:"keys"`
+	Datasets []ChartDataset `json:"datasets"`
+	Range    DashboardRange `json:"range"`
+}
+
+// Period Comparison
+// ComparisonPeriod memakai start_date/end_date yang sama dengan filter transaksi untuk drill-down
+type ComparisonPeriod struct {
+	StartDate string  `json:"start_date"`
+	EndDate   string  `json:"end_date"`
+	Month     string  `json:"month,omitempty"` // siklus bulanan jika periode dipilih lewat month
+	Total     float64 `json:"total"`
+}
+
+type CategoryComparison struct {
+	CategoryID uint    `json:"category_id"`
+	Category   string  `json:"category"`
+	Current    float64 `json:"current"`
+	Previous   float64 `json:"previous"`
+	Delta      float64 `json:"delta"`
+	// persentase perubahan terhadap previous, null jika previous 0
+	DeltaPercent *float64 `json:"delta_percent"`
+	Status       string   `json:"status"` // new, disappeared, changed atau unchanged
+}
+
+type RespPeriodComparison struct {
+	Type                  string               `json:"type"`
+	Current               ComparisonPeriod     `json:"current"`
+	Previous              ComparisonPeriod     `json:"previous"`
+	Delta                 float64              `json:"delta"`
+	DeltaPercent          *float64             `json:"delta_percent"`
+	Categories            []CategoryComparison `json:"categories"`
+	NewCategories         []CategoryComparison `json:"new_categories"`
+	DisappearedCategories []CategoryComparison `json:"disappeared_categories"`
+	BiggestMovers         []CategoryComparison `json:"biggest_movers"` // urut dari perubahan absolut terbesar
+}
+
+// Category Trend
+type RespCategoryTrend struct {
+	Type     string                 `json:"type"`
+	Labels   []string               `json:"labels"`
+	Keys     []string               `json:"keys"`
+	Datasets []CategoryTrendDataset `json:"datasets"`
+	Range    DashboardRange         `json:"range"`
+}
+
+type CategoryTrendDataset struct {
+	CategoryID uint `json:"category_id"`
+	ChartDataset
+}
+
+// Spending Heatmap
+type HeatmapDay struct {
+	Date  string  `json:"date"`
+	Total float64 `json:"total"`
+	Count int64   `json:"count"`
+}
+
+// DayOfWeekSpend berisi total expense satu hari dalam seminggu, average dibagi jumlah hari tersebut yang sudah lewat
+type DayOfWeekSpend struct {
+	Day     int     `json:"day"` // 0 = Minggu, sama dengan first_day_of_week
+	Label   string  `json:"label"`
+	Total   float64 `json:"total"`
+	Average float64 `json:"average"`
+}
+
+type SpendBreakdown struct {
+	Total        float64 `json:"total"`
+	Days         int     `json:"days"`
+	DailyAverage float64 `json:"daily_average"`
+}
+
+type RespSpendingHeatmap struct {
+	Year     int          `json:"year"`
+	Days     []HeatmapDay `json:"days"` // setiap tanggal dalam tahun, termasuk yang tanpa expense
+	MaxDaily float64      `json:"max_daily"`
+	Total    float64      `json:"total"`
+	// urut mulai dari first_day_of_week user
+	DayOfWeek   []DayOfWeekSpend   `json:"day_of_week"`
+	Weekday     SpendBreakdown     `json:"weekday"`
+	Weekend     SpendBreakdown     `json:"weekend"`
+	Preferences PreferenceResponse `json:"preferences"`
 }
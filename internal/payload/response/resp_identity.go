@@ -0,0 +1,16 @@
+package response
+
+import "time"
+
+type IdentityResponse struct {
+	ID          uint       `json:"id"`
+	Provider    string     `json:"provider"`
+	Email       string     `json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+	LinkedAt    time.Time  `json:"linked_at"`
+}
+
+type IdentityListResponse struct {
+	HasPassword bool               `json:"has_password"`
+	Identities  []IdentityResponse `json:"identities"`
+}
@@ -0,0 +1,26 @@
+package response
+
+import "time"
+
+type InsightResponse struct {
+	ID            uint      `json:"id"`
+	Kind          string    `json:"kind"`                     // large_transaction atau category_pace
+	TransactionID *uint     `json:"transaction_id,omitempty"` // hanya untuk large_transaction
+	CategoryID    uint      `json:"category_id"`
+	Category      string    `json:"category"`
+	Date          string    `json:"date"`
+	Month         string    `json:"month"`
+	Amount        float64   `json:"amount"`
+	Baseline      float64   `json:"baseline"` // nominal atau pengeluaran yang dianggap normal
+	Ratio         float64   `json:"ratio"`    // amount dibanding baseline
+	Message       string    `json:"message"`
+	DetectedAt    time.Time `json:"detected_at"`
+}
+
+// TransactionAnomalyResponse adalah anomali yang melekat pada satu transaksi
+type TransactionAnomalyResponse struct {
+	Kind     string  `json:"kind"`
+	Baseline float64 `json:"baseline"`
+	Ratio    float64 `json:"ratio"`
+	Message  string  `json:"message"`
+}
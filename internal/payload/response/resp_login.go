@@ -3,8 +3,11 @@ package response
 import "time"
 
 type LoginResponse struct {
-	Name        string    `json:"name"`
-	AccessToken string    `json:"access_token"`
-	Expiration  time.Time `json:"expiration"`
-	IsAdmin     bool      `json:"is_admin"`
+	Name          string    `json:"name"`
+	AccessToken   string    `json:"access_token"`
+	Expiration    time.Time `json:"expiration"`
+	IsAdmin       bool      `json:"is_admin"` // true jika role memiliki akses ke dashboard admin
+	Role          string    `json:"role"`
+	Permissions   []string  `json:"permissions"`
+	EmailVerified bool      `json:"email_verified"`
 }
@@ -0,0 +1,18 @@
+package response
+
+type OAuthProviderResponse struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"`
+}
+
+type OAuthProviderListResponse struct {
+	Providers []OAuthProviderResponse `json:"providers"`
+}
+
+type OAuthLoginResponse struct {
+	RedirectURL string `json:"redirect_url"`
+	State       string `json:"state"`
+	// Nonce tidak dikirim di body, controller menyimpannya di cookie HttpOnly agar state terikat ke browser
+	Nonce string `json:"-"`
+}
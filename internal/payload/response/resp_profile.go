@@ -0,0 +1,28 @@
+package response
+
+import "time"
+
+type ProfileResponse struct {
+	ID               uint               `json:"id"`
+	Name             string             `json:"name"`
+	Username         string             `json:"username"`
+	Email            string             `json:"email"`
+	EmailVerified    bool               `json:"email_verified"`
+	ProfilePic       string             `json:"profile_pic"`
+	Role             string             `json:"role"`
+	HasPassword      bool               `json:"has_password"`
+	TwoFactorEnabled bool               `json:"two_factor_enabled"`
+	Preferences      PreferenceResponse `json:"preferences"`
+	CreatedAt        time.Time          `json:"created_at"`
+	// terisi jika user meminta penghapusan akun dan masih dalam masa tenggang
+	DeletionScheduledFor *time.Time `json:"deletion_scheduled_for"`
+}
+
+type PreferenceResponse struct {
+	BaseCurrency   string `json:"base_currency"`
+	Timezone       string `json:"timezone"`
+	Locale         string `json:"locale"`
+	FirstDayOfWeek int    `json:"first_day_of_week"`
+	NumberFormat   string `json:"number_format"`
+	CycleStartDay  int    `json:"cycle_start_day"`
+}
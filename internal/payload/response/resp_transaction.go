@@ -10,8 +10,11 @@ type TransactionResponse struct {
 	Type        string    `json:"type"`
 	Description string    `json:"description"`
 	Date        time.Time `json:"date"`
+	CreatedBy   uint      `json:"created_by"` // user ID member yang mencatat transaksi
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+	// anomali hasil analisis worker, kosong sampai transaksi dianalisis
+	Anomalies []TransactionAnomalyResponse `json:"anomalies,omitempty"`
 }
 
 type TransactionSummary struct {
@@ -0,0 +1,42 @@
+package response
+
+import "time"
+
+type WorkspaceResponse struct {
+	ID         uint      `json:"id"`
+	Name       string    `json:"name"`
+	IsPersonal bool      `json:"is_personal"`
+	Role       string    `json:"role"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WorkspaceListResponse struct {
+	Workspaces []WorkspaceResponse `json:"workspaces"`
+}
+
+type WorkspaceMemberResponse struct {
+	UserID   uint      `json:"user_id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type WorkspaceMemberListResponse struct {
+	Members []WorkspaceMemberResponse `json:"members"`
+}
+
+type WorkspaceInvitationResponse struct {
+	ID        uint      `json:"id"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// WorkspaceSwitchResponse berisi access token baru dengan claim workspace aktif
+type WorkspaceSwitchResponse struct {
+	WorkspaceID uint      `json:"workspace_id"`
+	AccessToken string    `json:"access_token"`
+	Expiration  time.Time `json:"expiration"`
+}
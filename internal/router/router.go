@@ -1,43 +1,126 @@
 package router
 
 import (
+	"context"
+	"go-fintrack/config"
 	"go-fintrack/internal/controller"
 	"go-fintrack/internal/payload/response"
 	"go-fintrack/internal/service"
 	"go-fintrack/middleware"
 	"net/http"
 	"strings"
+	"time"
 
 	_ "go-fintrack/docs"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"gorm.io/gorm"
 )
 
 func InitRoutes(r *gin.Engine, db *gorm.DB) {
+	// init login brute-force protection
+	loginAttemptStore, err := service.NewLoginAttemptStore(config.LoginAttemptStoreDriver, db)
+	if err != nil {
+		logrus.Fatalf("Invalid LOGIN_ATTEMPT_STORE: %v", err)
+	}
+	loginGuard := service.NewLoginGuard(loginAttemptStore, db)
+
 	// init user service dan controller
-	userService := &service.UserService{DB: db}
+	userService := &service.UserService{
+		DB:          db,
+		EmailSender: config.EmailSender,
+		AppURL:      config.AppURL,
+		LoginGuard:  loginGuard,
+		UploadDir:   config.UploadDir,
+	}
 	userController := &controller.UserController{UserService: userService}
+	chatController := &controller.ChatController{UserService: userService}
+
+	// init oauth provider registry
+	oauthService := service.NewOAuthService(service.NewOAuthStateStore(config.OAuthStateStoreDriver, db))
+	for _, providerConfig := range config.OAuthProviders {
+		provider, err := service.NewOAuthProvider(context.Background(), providerConfig)
+		if err != nil {
+			logrus.Fatalf("Failed to initialize OAuth provider: %v", err)
+		}
+		oauthService.Register(provider)
+	}
+	oauthController := &controller.OAuthController{
+		UserService:     userService,
+		OAuthService:    oauthService,
+		IdentityService: service.NewIdentityService(db),
+	}
+
+	// init session
+	sessionService := service.NewSessionService(db)
+	sessionController := &controller.SessionController{SessionService: sessionService}
+
+	// init personal access token
+	apiTokenService := service.NewAPITokenService(db)
+	apiTokenController := &controller.APITokenController{APITokenService: apiTokenService}
+	authMiddleware := middleware.Authentication(sessionService, apiTokenService, userService)
+
+	// init workspace, semua data keuangan discope ke workspace aktif
+	workspaceService := service.NewWorkspaceService(db, config.EmailSender, config.AppURL)
+	workspaceController := &controller.WorkspaceController{WorkspaceService: workspaceService}
+	workspaceMiddleware := middleware.WorkspaceContext(workspaceService)
+
+	// perubahan transaksi dan kategori dikabarkan per workspace, dipakai untuk invalidasi cache dashboard
+	workspaceEvents := service.NewWorkspaceEvents()
 
 	// init dashboard
-	dashboardService := service.NewDashboardService(db)
+	var dashboardCache service.DashboardCache
+	if config.DashboardCacheSize > 0 {
+		dashboardCache = service.NewLRUDashboardCache(config.DashboardCacheSize, config.DashboardCacheTTL)
+	}
+	dashboardService := service.NewCachedDashboardService(service.NewDashboardService(db), dashboardCache, workspaceEvents)
 	dashboardController := controller.NewDashboardController(dashboardService)
 
 	// init category
-	categoryService := &service.CategoryService{DB: db}
+	categoryService := &service.CategoryService{DB: db, Events: workspaceEvents}
 	categoryController := &controller.CategoryController{CategoryService: categoryService}
 
 	// init transaction
-	transactionService := &service.TransactionService{DB: db}
+	transactionService := service.NewTransactionService(db)
+	transactionService.Events = workspaceEvents
 	transactionController := &controller.TransactionController{TransactionService: transactionService}
 
+	// init admin
+	rbacService := service.NewRBACService(db)
+	adminController := &controller.AdminController{
+		LoginGuard:   loginGuard,
+		AdminService: service.NewAdminService(db, userService),
+		StatsService: service.NewAdminStatsService(db),
+		RBACService:  rbacService,
+	}
+
+	// init export data dan penghapusan akun, job diproses worker di background
+	accountService := service.NewAccountService(db, config.EmailSender, config.AppURL, config.UploadDir, config.ExportDir, config.AccountDeletionGracePeriod)
+	accountController := &controller.AccountController{AccountService: accountService}
+	go accountService.RunWorker(context.Background(), time.Minute)
+
+	// init deteksi pengeluaran tidak biasa, dianalisis worker setiap kali transaksi workspace berubah
+	insightService := service.NewInsightService(db, workspaceEvents)
+	insightController := &controller.InsightController{InsightService: insightService}
+	go insightService.RunWorker(context.Background(), time.Minute)
+
+	// init snapshot net worth, dibangun worker dari riwayat transaksi
+	netWorthService := service.NewNetWorthService(db, workspaceEvents)
+	netWorthController := &controller.NetWorthController{NetWorthService: netWorthService}
+	go netWorthService.RunWorker(context.Background(), time.Minute)
+
+	// init activity tracking untuk statistik admin
+	activityService := service.NewActivityService(db)
+
 	// swagger enpoint
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// API routes group
 	api := r.Group("/api")
+	api.Use(middleware.TrackActivity(activityService))
 	{
 		api.GET("/health-check", func(ctx *gin.Context) {
 			ctx.JSON(http.StatusOK, response.SuccessResponse{
@@ -47,11 +130,30 @@ func InitRoutes(r *gin.Engine, db *gorm.DB) {
 			})
 		})
 
-		// admin endpoint
+		// admin endpoint, setiap route dibatasi permission dari role user
 		adminRouter := api.Group("/admin")
-		adminRouter.Use(middleware.Authentication(), middleware.AdminOnly())
+		adminRouter.Use(authMiddleware)
 		{
-			//	router admin
+			canReadUsers := middleware.RequirePermission(rbacService, service.PermissionUsersRead)
+			canManageUsers := middleware.RequirePermission(rbacService, service.PermissionUsersManage)
+			canAssignRoles := middleware.RequirePermission(rbacService, service.PermissionRolesAssign)
+			canReadStats := middleware.RequirePermission(rbacService, service.PermissionStatsRead)
+
+			adminRouter.GET("/roles", canReadUsers, adminController.GetRolesHandler)
+			adminRouter.GET("/users", canReadUsers, adminController.GetUsersHandler)
+			adminRouter.GET("/users/:id", canReadUsers, adminController.GetUserDetailHandler)
+			adminRouter.DELETE("/users/:id", canManageUsers, adminController.DeleteUserHandler)
+			adminRouter.POST("/users/:id/suspend", canManageUsers, adminController.SuspendUserHandler)
+			adminRouter.POST("/users/:id/reactivate", canManageUsers, adminController.ReactivateUserHandler)
+			adminRouter.POST("/users/:id/force-password-reset", canManageUsers, adminController.ForcePasswordResetHandler)
+			adminRouter.POST("/users/:id/unlock", canManageUsers, adminController.UnlockUserHandler)
+			adminRouter.PUT("/users/:id/role", canAssignRoles, adminController.UpdateUserRoleHandler)
+
+			adminRouter.GET("/stats/overview", canReadStats, adminController.GetPlatformOverviewHandler)
+			adminRouter.GET("/stats/signups", canReadStats, adminController.GetSignupStatsHandler)
+			adminRouter.GET("/stats/active-users", canReadStats, adminController.GetActiveUserStatsHandler)
+			adminRouter.GET("/stats/transactions", canReadStats, adminController.GetTransactionVolumeStatsHandler)
+			adminRouter.GET("/stats/chat", canReadStats, adminController.GetChatUsageStatsHandler)
 		}
 
 		// auth endpoint
@@ -59,37 +161,114 @@ func InitRoutes(r *gin.Engine, db *gorm.DB) {
 		{
 			userRouter.POST("/register", userController.RegisterHandler)
 			userRouter.POST("/login", userController.LoginHandler)
+			userRouter.POST("/login/2fa", userController.TwoFactorLoginHandler)
+			userRouter.POST("/forgot-password", userController.ForgotPasswordHandler)
+			userRouter.POST("/reset-password", userController.ResetPasswordHandler)
+			userRouter.POST("/verify-email", userController.VerifyEmailHandler)
+
+			// oauth / oidc login
+			oauthRouter := userRouter.Group("/oauth")
+			{
+				oauthRouter.GET("/providers", oauthController.GetOAuthProvidersHandler)
+				oauthRouter.GET("/:provider/login", oauthController.OAuthLoginHandler)
+				// callback link identity harus membawa session yang memulai link
+				oauthRouter.GET("/:provider/callback", middleware.OptionalAuthentication(authMiddleware), oauthController.OAuthCallbackHandler)
+			}
 
-			// google auth
+			// google auth, route lama tetap didukung
 			googleAuth := userRouter.Group("/google")
 			{
-				googleAuth.GET("/login", userController.GoogleLogin)
-				googleAuth.GET("/callback", userController.GoogleCallback)
+				googleAuth.GET("/login", controller.WithProvider("google", oauthController.OAuthLoginHandler))
+				googleAuth.GET("/callback", middleware.OptionalAuthentication(authMiddleware), controller.WithProvider("google", oauthController.OAuthCallbackHandler))
 			}
+
+			// endpoint auth yang membutuhkan login
+			authedRouter := userRouter.Group("")
+			authedRouter.Use(authMiddleware)
+			{
+				authedRouter.POST("/logout", sessionController.LogoutHandler)
+				authedRouter.GET("/sessions", sessionController.GetSessionsHandler)
+				authedRouter.DELETE("/sessions/:id", sessionController.RevokeSessionHandler)
+				authedRouter.POST("/verify-email/resend", userController.ResendVerificationHandler)
+				authedRouter.POST("/2fa/setup", userController.SetupTwoFactorHandler)
+				authedRouter.POST("/2fa/confirm", userController.ConfirmTwoFactorHandler)
+				authedRouter.POST("/2fa/disable", userController.DisableTwoFactorHandler)
+				authedRouter.GET("/tokens", apiTokenController.GetAPITokensHandler)
+				authedRouter.POST("/tokens", apiTokenController.CreateAPITokenHandler)
+				authedRouter.DELETE("/tokens/:id", apiTokenController.RevokeAPITokenHandler)
+				authedRouter.GET("/identities", oauthController.GetIdentitiesHandler)
+				authedRouter.POST("/identities/link/:provider", oauthController.LinkIdentityHandler)
+				authedRouter.DELETE("/identities/:id", oauthController.UnlinkIdentityHandler)
+			}
+		}
+
+		// profile dan preferensi user yang sedang login
+		meRouter := api.Group("/me")
+		meRouter.Use(authMiddleware)
+		{
+			meRouter.GET("", userController.GetProfileHandler)
+			meRouter.PUT("", userController.UpdateProfileHandler)
+			meRouter.PUT("/password", userController.ChangePasswordHandler)
+			meRouter.POST("/avatar", userController.UploadProfilePictureHandler)
+			meRouter.GET("/preferences", userController.GetPreferencesHandler)
+			meRouter.PUT("/preferences", userController.UpdatePreferencesHandler)
+			meRouter.GET("/exports", accountController.GetDataExportsHandler)
+			meRouter.POST("/exports", middleware.RequireVerifiedEmail(userService, middleware.FeatureExport), accountController.RequestDataExportHandler)
+			meRouter.GET("/exports/:id/download", middleware.RequireVerifiedEmail(userService, middleware.FeatureExport), accountController.DownloadDataExportHandler)
+			meRouter.POST("/deletion", accountController.RequestAccountDeletionHandler)
+			meRouter.DELETE("/deletion", accountController.CancelAccountDeletionHandler)
+		}
+
+		// workspace endpoint
+		workspaceRouter := api.Group("/workspaces")
+		workspaceRouter.Use(authMiddleware)
+		{
+			workspaceRouter.GET("", workspaceController.GetWorkspacesHandler)
+			workspaceRouter.POST("", workspaceController.CreateWorkspaceHandler)
+			workspaceRouter.POST("/invitations/accept", workspaceController.AcceptWorkspaceInvitationHandler)
+			workspaceRouter.DELETE("/:id", workspaceController.DeleteWorkspaceHandler)
+			workspaceRouter.POST("/:id/switch", workspaceController.SwitchWorkspaceHandler)
+			workspaceRouter.GET("/:id/members", workspaceController.GetWorkspaceMembersHandler)
+			workspaceRouter.PUT("/:id/members/:userId", workspaceController.UpdateWorkspaceMemberHandler)
+			workspaceRouter.DELETE("/:id/members/:userId", workspaceController.RemoveWorkspaceMemberHandler)
+			workspaceRouter.POST("/:id/invitations", workspaceController.InviteWorkspaceMemberHandler)
 		}
 
 		// dashboard endpoint
 		dashboardRouter := api.Group("/dashboard")
-		dashboardRouter.Use(middleware.Authentication())
+		dashboardRouter.Use(middleware.APITokenScopes(service.ScopeDashboardRead, ""), authMiddleware, workspaceMiddleware)
 		{
 			dashboardRouter.GET("/overview", dashboardController.GetFinancialOverviewHandler)
 			dashboardRouter.GET("/charts", dashboardController.GetDashboardChartsHandler)
+			dashboardRouter.GET("/forecast", dashboardController.GetCashFlowForecastHandler)
+			dashboardRouter.GET("/net-worth", netWorthController.GetNetWorthChartHandler)
+			dashboardRouter.GET("/compare", dashboardController.GetPeriodComparisonHandler)
+			dashboardRouter.GET("/category-trend", dashboardController.GetCategoryTrendHandler)
+			dashboardRouter.GET("/heatmap", dashboardController.GetSpendingHeatmapHandler)
+		}
+
+		// insights endpoint
+		insightRouter := api.Group("/insights")
+		insightRouter.Use(middleware.APITokenScopes(service.ScopeDashboardRead, ""), authMiddleware, workspaceMiddleware)
+		{
+			insightRouter.GET("", insightController.GetInsightsHandler)
 		}
 
 		// transaction endpoint
 		transactionRouter := api.Group("/transaction")
-		transactionRouter.Use(middleware.Authentication())
+		transactionRouter.Use(middleware.APITokenScopes(service.ScopeTransactionsRead, service.ScopeTransactionsWrite), authMiddleware, workspaceMiddleware)
 		{
 			transactionRouter.GET("", transactionController.GetTransactionHandler)
 			transactionRouter.POST("", transactionController.CreateTransactionHandler)
 			transactionRouter.PUT("/:id", transactionController.UpdateTransactionHandler)
 			transactionRouter.DELETE("/:id", transactionController.DeleteTransactionHandler)
-			transactionRouter.GET("/export", transactionController.ExportTransactionsExcelHandler)
+			transactionRouter.POST("/:id/restore", transactionController.RestoreTransactionHandler)
+			transactionRouter.GET("/export", middleware.RequireScope(service.ScopeExport), middleware.RequireVerifiedEmail(userService, middleware.FeatureExport), transactionController.ExportTransactionsExcelHandler)
 		}
 
 		// category endpoint
 		categoryRouter := api.Group("/category")
-		categoryRouter.Use(middleware.Authentication())
+		categoryRouter.Use(middleware.APITokenScopes(service.ScopeCategoriesRead, service.ScopeCategoriesWrite), authMiddleware, workspaceMiddleware)
 		{
 			categoryRouter.GET("", categoryController.GetAllCategoriesHandler)
 			categoryRouter.GET("/:id", categoryController.GetCategoryIdHandler)
@@ -99,9 +278,9 @@ func InitRoutes(r *gin.Engine, db *gorm.DB) {
 		}
 
 		chatRouter := api.Group("/chat")
-		chatRouter.Use(middleware.Authentication())
+		chatRouter.Use(authMiddleware, middleware.RequireVerifiedEmail(userService, middleware.FeatureChat))
 		{
-			chatRouter.POST("/stream", controller.StreamChat)
+			chatRouter.POST("/stream", middleware.CountChatUsage(activityService), chatController.StreamChat)
 		}
 	}
 
@@ -110,6 +289,9 @@ func InitRoutes(r *gin.Engine, db *gorm.DB) {
 	r.Static("/css", "./web/dist/css")
 	r.Static("/assets", "./web/dist/assets")
 	r.Static("/vector", "./web/dist/vector")
+	if config.UploadDir != "" {
+		r.Static("/uploads", config.UploadDir)
+	}
 	r.StaticFile("/favicon.ico", "./web/dist/favicon.ico")
 
 	// handle SPA routing
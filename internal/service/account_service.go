@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDeletionPasswordInvalid = errors.New("password is incorrect")
+	ErrDeletionNotScheduled    = errors.New("account deletion is not scheduled")
+)
+
+// AccountService menangani hak user atas datanya: export seluruh data dan penghapusan akun dengan masa tenggang.
+// Keduanya diproses oleh worker di background (RunWorker).
+type AccountService struct {
+	DB          *gorm.DB
+	EmailSender utility.EmailSender
+	AppURL      string
+	UploadDir   string // lokasi foto profil yang ikut dihapus
+	ExportDir   string
+	GracePeriod time.Duration
+	wake        chan struct{}
+}
+
+func NewAccountService(db *gorm.DB, emailSender utility.EmailSender, appURL, uploadDir, exportDir string, gracePeriod time.Duration) *AccountService {
+	return &AccountService{
+		DB:          db,
+		EmailSender: emailSender,
+		AppURL:      appURL,
+		UploadDir:   uploadDir,
+		ExportDir:   exportDir,
+		GracePeriod: gracePeriod,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// RunWorker memproses job export dan penghapusan akun yang jatuh tempo setiap interval sampai ctx selesai.
+// Job diklaim lewat update status sehingga aman dijalankan di beberapa instance.
+func (s *AccountService) RunWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunPendingJobs()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// RunPendingJobs menjalankan satu putaran worker
+func (s *AccountService) RunPendingJobs() {
+	if err := s.processPendingExports(); err != nil {
+		logrus.Errorf("Failed to process data exports: %v", err)
+	}
+	if err := s.removeExpiredExports(); err != nil {
+		logrus.Errorf("Failed to remove expired data exports: %v", err)
+	}
+	if err := s.purgeDueAccounts(); err != nil {
+		logrus.Errorf("Failed to purge deleted accounts: %v", err)
+	}
+}
+
+// notifyWorker membangunkan worker tanpa menunggu interval berikutnya
+func (s *AccountService) notifyWorker() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// ScheduleDeletion menjadwalkan penghapusan akun setelah masa tenggang, password wajib untuk akun yang memilikinya
+func (s *AccountService) ScheduleDeletion(userID uint, password string) (*response.AccountDeletionResponse, error) {
+	var user entity.User
+	if err := s.DB.First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("error getting user: %v", err)
+	}
+
+	if user.Password != "" {
+		if err := utility.CompareHashAndPassword(user.Password, password); err != nil {
+			return nil, ErrDeletionPasswordInvalid
+		}
+	}
+
+	// permintaan ulang tidak memperpanjang jadwal yang sudah ada
+	if user.DeletionScheduledFor != nil {
+		return &response.AccountDeletionResponse{ScheduledFor: *user.DeletionScheduledFor}, nil
+	}
+
+	scheduledFor := time.Now().Add(s.GracePeriod)
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("deletion_scheduled_for", scheduledFor).Error; err != nil {
+			return fmt.Errorf("error scheduling account deletion: %v", err)
+		}
+
+		recordSecurityEvent(tx, entity.SecurityEvent{
+			UserID:  &userID,
+			Type:    entity.SecurityEventDeletionScheduled,
+			Details: fmt.Sprintf("scheduled_for=%s", scheduledFor.Format(time.RFC3339)),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendEmail(user.Email, "Your account is scheduled for deletion", fmt.Sprintf("Hi %s,\n\n"+
+		"Your FinTrack account and all of its data will be permanently deleted on %s.\n\n"+
+		"If you change your mind, log in before then and cancel the deletion from your account settings:\n\n"+
+		"%s/settings/account\n",
+		user.Name, scheduledFor.Format("2006-01-02 15:04 MST"), s.AppURL))
+
+	return &response.AccountDeletionResponse{ScheduledFor: scheduledFor}, nil
+}
+
+func (s *AccountService) CancelDeletion(userID uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.User{}).
+			Where("id = ? AND deletion_scheduled_for IS NOT NULL", userID).
+			Update("deletion_scheduled_for", nil)
+		if result.Error != nil {
+			return fmt.Errorf("error canceling account deletion: %v", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrDeletionNotScheduled
+		}
+
+		recordSecurityEvent(tx, entity.SecurityEvent{
+			UserID: &userID,
+			Type:   entity.SecurityEventDeletionCanceled,
+		})
+		return nil
+	})
+}
+
+func (s *AccountService) purgeDueAccounts() error {
+	var userIDs []uint
+	if err := s.DB.Unscoped().Model(&entity.User{}).
+		Where("deletion_scheduled_for IS NOT NULL AND deletion_scheduled_for <= ?", time.Now()).
+		Pluck("id", &userIDs).Error; err != nil {
+		return fmt.Errorf("error getting due account deletions: %v", err)
+	}
+
+	for _, userID := range userIDs {
+		if err := s.PurgeUser(userID); err != nil {
+			logrus.Errorf("Failed to purge user %d: %v", userID, err)
+			continue
+		}
+		logrus.Infof("Purged account %d after deletion grace period", userID)
+	}
+	return nil
+}
+
+// PurgeUser menghapus permanen semua baris milik user. Data di workspace bersama tidak ikut hilang:
+// kepemilikan workspace dipindah ke member lain dan baris buatan user dialihkan ke owner workspace.
+// Security event dianonimkan agar jejak audit tetap ada tanpa data pribadi.
+func (s *AccountService) PurgeUser(userID uint) error {
+	var user entity.User
+	if err := s.DB.Unscoped().First(&user, userID).Error; err != nil {
+		return fmt.Errorf("error getting user: %v", err)
+	}
+
+	var exportFiles []string
+	if err := s.DB.Unscoped().Model(&entity.DataExport{}).
+		Where("user_id = ? AND file_path <> ''", userID).
+		Pluck("file_path", &exportFiles).Error; err != nil {
+		return fmt.Errorf("error getting data exports: %v", err)
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := s.purgeWorkspaceData(tx, &user); err != nil {
+			return err
+		}
+
+		// tabel yang seluruh barisnya milik user
+		ownedModels := []interface{}{
+			&entity.Session{},
+			&entity.PasswordReset{},
+			&entity.EmailVerification{},
+			&entity.RecoveryCode{},
+			&entity.APIToken{},
+			&entity.UserActivity{},
+			&entity.ChatUsage{},
+			&entity.UserIdentity{},
+			&entity.UserPreference{},
+			&entity.DataExport{},
+		}
+		for _, model := range ownedModels {
+			if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return fmt.Errorf("error deleting %T: %v", model, err)
+			}
+		}
+
+		if err := tx.Where("link_user_id = ?", userID).Delete(&entity.OAuthState{}).Error; err != nil {
+			return fmt.Errorf("error deleting oauth states: %v", err)
+		}
+
+		attemptKeys := []string{
+			accountAttemptKey(userID),
+			identifierAttemptKey(user.Email),
+			identifierAttemptKey(user.Username),
+		}
+		if err := tx.Where("attempt_key IN ?", attemptKeys).Delete(&entity.LoginAttempt{}).Error; err != nil {
+			return fmt.Errorf("error deleting login attempts: %v", err)
+		}
+
+		if err := tx.Unscoped().Model(&entity.SecurityEvent{}).Where("user_id = ?", userID).
+			Updates(map[string]interface{}{
+				"user_id":    nil,
+				"ip_address": "",
+				"user_agent": "",
+				"details":    "",
+			}).Error; err != nil {
+			return fmt.Errorf("error anonymizing security events: %v", err)
+		}
+
+		if err := tx.Unscoped().Delete(&user).Error; err != nil {
+			return fmt.Errorf("error deleting user: %v", err)
+		}
+
+		recordSecurityEvent(tx, entity.SecurityEvent{
+			Type:    entity.SecurityEventAccountDeleted,
+			Details: "self-requested deletion, grace period ended",
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// file dihapus setelah commit agar rollback tidak meninggalkan baris yang menunjuk file yang sudah hilang
+	for _, path := range exportFiles {
+		removeFile(path)
+	}
+	if fileName, found := strings.CutPrefix(user.ProfilePic, profilePicURLPrefix); found {
+		removeFile(filepath.Join(s.UploadDir, "avatars", filepath.Base(fileName)))
+	}
+
+	return nil
+}
+
+// purgeWorkspaceData menghapus workspace yang hanya dipakai user, memindahkan kepemilikan workspace bersama
+// ke member berikutnya, lalu mengalihkan kategori/transaksi buatan user ke owner workspace tersebut
+func (s *AccountService) purgeWorkspaceData(tx *gorm.DB, user *entity.User) error {
+	// agregat bulanan workspace yang memuat transaksi user dibangun ulang setelah transaksinya dialihkan atau dihapus
+	var aggregateWorkspaceIDs []uint
+	if err := tx.Model(&entity.MonthlyAggregate{}).Where("user_id = ?", user.ID).
+		Distinct().Pluck("workspace_id", &aggregateWorkspaceIDs).Error; err != nil {
+		return fmt.Errorf("error getting monthly aggregates: %v", err)
+	}
+
+	var memberships []entity.WorkspaceMember
+	if err := tx.Unscoped().Where("user_id = ?", user.ID).Find(&memberships).Error; err != nil {
+		return fmt.Errorf("error getting workspace memberships: %v", err)
+	}
+
+	var deleteWorkspaceIDs []uint
+	for _, membership := range memberships {
+		var workspace entity.Workspace
+		if err := tx.Unscoped().First(&workspace, membership.WorkspaceID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return fmt.Errorf("error getting workspace: %v", err)
+		}
+
+		var successor entity.WorkspaceMember
+		err := tx.Where("workspace_id = ? AND user_id <> ?", workspace.ID, user.ID).
+			Order(fmt.Sprintf("CASE role WHEN '%s' THEN 0 WHEN '%s' THEN 1 ELSE 2 END, created_at ASC",
+				entity.WorkspaceRoleOwner, entity.WorkspaceRoleEditor)).
+			First(&successor).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) || (workspace.IsPersonal && workspace.OwnerID == user.ID) {
+			deleteWorkspaceIDs = append(deleteWorkspaceIDs, workspace.ID)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error getting workspace members: %v", err)
+		}
+
+		if workspace.OwnerID == user.ID {
+			if err := tx.Model(&workspace).Update("owner_id", successor.UserID).Error; err != nil {
+				return fmt.Errorf("error transferring workspace ownership: %v", err)
+			}
+			if err := tx.Model(&successor).Update("role", entity.WorkspaceRoleOwner).Error; err != nil {
+				return fmt.Errorf("error promoting workspace member: %v", err)
+			}
+		}
+	}
+
+	// workspace yang tidak punya member lain dihapus beserta isinya
+	if len(deleteWorkspaceIDs) > 0 {
+		for _, model := range []interface{}{
+			&entity.Transaction{},
+			&entity.MonthlyAggregate{},
+			&entity.SpendingAnomaly{},
+			&entity.NetWorthSnapshot{},
+			&entity.Category{},
+			&entity.WorkspaceInvitation{},
+			&entity.WorkspaceMember{},
+		} {
+			if err := tx.Unscoped().Where("workspace_id IN ?", deleteWorkspaceIDs).Delete(model).Error; err != nil {
+				return fmt.Errorf("error deleting workspace data %T: %v", model, err)
+			}
+		}
+		if err := tx.Unscoped().Where("id IN ?", deleteWorkspaceIDs).Delete(&entity.Workspace{}).Error; err != nil {
+			return fmt.Errorf("error deleting workspaces: %v", err)
+		}
+	}
+
+	// baris buatan user di workspace bersama dialihkan ke owner workspace, sisanya (workspace sudah tidak ada) dihapus
+	ownerOfWorkspace := "(SELECT owner_id FROM workspaces WHERE workspaces.id = %s.workspace_id)"
+	sharedWorkspace := "workspace_id IN (SELECT id FROM workspaces WHERE owner_id <> ?)"
+	for _, table := range []string{"transactions", "categories"} {
+		if err := tx.Exec(fmt.Sprintf("UPDATE %s SET user_id = "+ownerOfWorkspace+" WHERE user_id = ? AND "+sharedWorkspace, table, table),
+			user.ID, user.ID).Error; err != nil {
+			return fmt.Errorf("error reassigning %s: %v", table, err)
+		}
+	}
+	if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(&entity.Transaction{}).Error; err != nil {
+		return fmt.Errorf("error deleting transactions: %v", err)
+	}
+	if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(&entity.Category{}).Error; err != nil {
+		return fmt.Errorf("error deleting categories: %v", err)
+	}
+	if len(aggregateWorkspaceIDs) > 0 {
+		if err := utility.RebuildMonthlyAggregates(tx, aggregateWorkspaceIDs...); err != nil {
+			return fmt.Errorf("error rebuilding monthly aggregates: %v", err)
+		}
+	}
+
+	if err := tx.Exec("UPDATE workspace_invitations SET invited_by_id = "+fmt.Sprintf(ownerOfWorkspace, "workspace_invitations")+
+		" WHERE invited_by_id = ?", user.ID).Error; err != nil {
+		return fmt.Errorf("error reassigning workspace invitations: %v", err)
+	}
+	if err := tx.Unscoped().Where("LOWER(email) = LOWER(?)", user.Email).Delete(&entity.WorkspaceInvitation{}).Error; err != nil {
+		return fmt.Errorf("error deleting workspace invitations: %v", err)
+	}
+
+	if err := tx.Unscoped().Where("user_id = ?", user.ID).Delete(&entity.WorkspaceMember{}).Error; err != nil {
+		return fmt.Errorf("error deleting workspace memberships: %v", err)
+	}
+	if err := tx.Unscoped().Where("owner_id = ?", user.ID).Delete(&entity.Workspace{}).Error; err != nil {
+		return fmt.Errorf("error deleting workspaces: %v", err)
+	}
+	return nil
+}
+
+func (s *AccountService) sendEmail(to, subject, body string) {
+	if s.EmailSender == nil {
+		return
+	}
+	if err := s.EmailSender.Send(utility.EmailMessage{To: to, Subject: subject, Body: body}); err != nil {
+		logrus.Errorf("Failed to send email %q: %v", subject, err)
+	}
+}
+
+func removeFile(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		logrus.Warnf("Failed to remove file %s: %v", path, err)
+	}
+}
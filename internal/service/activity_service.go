@@ -0,0 +1,66 @@
+package service
+
+import (
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ActivityService mencatat aktivitas user untuk statistik admin
+type ActivityService struct {
+	DB *gorm.DB
+
+	mu       sync.Mutex
+	lastSeen map[uint]string // user ID -> tanggal terakhir yang sudah dicatat, menghindari insert di setiap request
+}
+
+func NewActivityService(db *gorm.DB) *ActivityService {
+	return &ActivityService{
+		DB:       db,
+		lastSeen: make(map[uint]string),
+	}
+}
+
+// TrackActive menandai user aktif pada hari ini
+func (s *ActivityService) TrackActive(userID uint) error {
+	now := time.Now()
+	today := now.Format("2006-01-02")
+
+	s.mu.Lock()
+	if s.lastSeen[userID] == today {
+		s.mu.Unlock()
+		return nil
+	}
+	s.mu.Unlock()
+
+	activity := entity.UserActivity{
+		UserID:       userID,
+		ActivityDate: time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+	}
+	if err := s.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&activity).Error; err != nil {
+		return fmt.Errorf("error tracking user activity: %v", err)
+	}
+
+	s.mu.Lock()
+	// entry hari sebelumnya tidak dibutuhkan lagi
+	for id, date := range s.lastSeen {
+		if date != today {
+			delete(s.lastSeen, id)
+		}
+	}
+	s.lastSeen[userID] = today
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *ActivityService) RecordChatMessage(userID uint) error {
+	if err := s.DB.Create(&entity.ChatUsage{UserID: userID}).Error; err != nil {
+		return fmt.Errorf("error recording chat usage: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrCannotModifySelf = errors.New("admins cannot perform this action on their own account")
+)
+
+// AdminService berisi aksi admin untuk mengelola akun user, setiap aksi dicatat sebagai security event
+type AdminService struct {
+	DB          *gorm.DB
+	UserService *UserService // dipakai untuk mengirim email reset password
+}
+
+func NewAdminService(db *gorm.DB, userService *UserService) *AdminService {
+	return &AdminService{DB: db, UserService: userService}
+}
+
+func (s *AdminService) ListUsers(filter request.AdminUserFilter) (*response.AdminUserListResponse, error) {
+	query := s.DB.Model(&entity.User{})
+
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		pattern := "%" + strings.ToLower(search) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ? OR LOWER(username) LIKE ?", pattern, pattern, pattern)
+	}
+
+	switch filter.Status {
+	case "active":
+		query = query.Where("suspended_at IS NULL")
+	case "suspended":
+		query = query.Where("suspended_at IS NOT NULL")
+	}
+
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("error counting users: %v", err)
+	}
+
+	var users []entity.User
+	offset := (filter.Page - 1) * filter.Limit
+	if err := query.Order("created_at DESC").
+		Offset(offset).
+		Limit(filter.Limit).
+		Find(&users).Error; err != nil {
+		return nil, fmt.Errorf("error getting users: %v", err)
+	}
+
+	userResponses := make([]response.AdminUserResponse, len(users))
+	for i, user := range users {
+		userResponses[i] = toAdminUserResponse(user)
+	}
+
+	return &response.AdminUserListResponse{
+		Users: userResponses,
+		Pagination: response.Pagination{
+			CurrentPage: filter.Page,
+			TotalPage:   int(math.Ceil(float64(total) / float64(filter.Limit))),
+			TotalItems:  total,
+			ItemPerPage: filter.Limit,
+		},
+	}, nil
+}
+
+func (s *AdminService) GetUserDetail(userID uint) (*response.AdminUserDetailResponse, error) {
+	user, err := s.findUser(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats response.AdminUserStats
+	if err := s.DB.Model(&entity.Transaction{}).Where("user_id = ?", userID).Count(&stats.TransactionCount).Error; err != nil {
+		return nil, fmt.Errorf("error counting transactions: %v", err)
+	}
+	if err := s.DB.Model(&entity.Category{}).Where("user_id = ?", userID).Count(&stats.CategoryCount).Error; err != nil {
+		return nil, fmt.Errorf("error counting categories: %v", err)
+	}
+	if err := s.DB.Model(&entity.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Count(&stats.ActiveSessions).Error; err != nil {
+		return nil, fmt.Errorf("error counting sessions: %v", err)
+	}
+	if err := s.DB.Model(&entity.APIToken{}).Where("user_id = ?", userID).Count(&stats.APITokens).Error; err != nil {
+		return nil, fmt.Errorf("error counting API tokens: %v", err)
+	}
+
+	return &response.AdminUserDetailResponse{
+		AdminUserResponse: toAdminUserResponse(*user),
+		Stats:             stats,
+	}, nil
+}
+
+// SuspendUser menonaktifkan akun dan mencabut semua session, JWT yang masih berlaku ditolak oleh middleware
+func (s *AdminService) SuspendUser(adminID, userID uint, reason string) error {
+	if adminID == userID {
+		return ErrCannotModifySelf
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := s.findUser(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.ensureCanManage(tx, adminID, user); err != nil {
+			return err
+		}
+
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"suspended_at":     time.Now(),
+			"suspended_reason": strings.TrimSpace(reason),
+		}).Error; err != nil {
+			return fmt.Errorf("error suspending user: %v", err)
+		}
+
+		if err := NewSessionService(tx).RevokeAllSessions(tx, userID, ""); err != nil {
+			return err
+		}
+
+		recordSecurityEvent(tx, entity.SecurityEvent{
+			UserID:  &userID,
+			Type:    entity.SecurityEventAccountSuspended,
+			Details: fmt.Sprintf("suspended_by=%d reason=%q", adminID, reason),
+		})
+		return nil
+	})
+}
+
+// ReactivateUser membuka kembali akun yang di-suspend, akun staff hanya bisa dibuka oleh role yang boleh assign role
+func (s *AdminService) ReactivateUser(adminID, userID uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := s.findUser(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.ensureCanManage(tx, adminID, user); err != nil {
+			return err
+		}
+
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"suspended_at":     nil,
+			"suspended_reason": "",
+		}).Error; err != nil {
+			return fmt.Errorf("error reactivating user: %v", err)
+		}
+
+		recordSecurityEvent(tx, entity.SecurityEvent{
+			UserID:  &userID,
+			Type:    entity.SecurityEventAccountReactivated,
+			Details: fmt.Sprintf("reactivated_by=%d", adminID),
+		})
+		return nil
+	})
+}
+
+// ForcePasswordReset memaksa user mengganti password: login diblokir, session dan API token dicabut,
+// lalu link reset dikirim ke email
+func (s *AdminService) ForcePasswordReset(adminID, userID uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := s.findUser(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.ensureCanManage(tx, adminID, user); err != nil {
+			return err
+		}
+
+		if err := tx.Model(user).Update("password_reset_required", true).Error; err != nil {
+			return fmt.Errorf("error updating user: %v", err)
+		}
+
+		if err := NewSessionService(tx).RevokeAllSessions(tx, userID, ""); err != nil {
+			return err
+		}
+
+		// API token tidak melewati pengecekan password, jadi ikut dicabut
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.APIToken{}).Error; err != nil {
+			return fmt.Errorf("error revoking API tokens: %v", err)
+		}
+
+		if err := s.UserService.sendPasswordReset(tx, user); err != nil {
+			return err
+		}
+
+		recordSecurityEvent(tx, entity.SecurityEvent{
+			UserID:  &userID,
+			Type:    entity.SecurityEventPasswordResetForce,
+			Details: fmt.Sprintf("forced_by=%d", adminID),
+		})
+		return nil
+	})
+}
+
+// DeleteUser melakukan soft delete akun, mencabut semua session dan API token
+func (s *AdminService) DeleteUser(adminID, userID uint) error {
+	if adminID == userID {
+		return ErrCannotModifySelf
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := s.findUser(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.ensureCanManage(tx, adminID, user); err != nil {
+			return err
+		}
+
+		if err := NewSessionService(tx).RevokeAllSessions(tx, userID, ""); err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.APIToken{}).Error; err != nil {
+			return fmt.Errorf("error revoking API tokens: %v", err)
+		}
+
+		if err := tx.Delete(user).Error; err != nil {
+			return fmt.Errorf("error deleting user: %v", err)
+		}
+
+		recordSecurityEvent(tx, entity.SecurityEvent{
+			UserID:  &userID,
+			Type:    entity.SecurityEventAccountDeleted,
+			Details: fmt.Sprintf("deleted_by=%d", adminID),
+		})
+		return nil
+	})
+}
+
+// ensureCanManage mencegah admin biasa mengubah akun staff lain (support, admin, superadmin)
+func (s *AdminService) ensureCanManage(tx *gorm.DB, adminID uint, target *entity.User) error {
+	if !IsStaffRole(target.Role) {
+		return nil
+	}
+
+	actor, err := s.findUser(tx, adminID)
+	if err != nil {
+		return err
+	}
+	if !RoleHasPermission(actor.Role, PermissionRolesAssign) {
+		return ErrInsufficientRole
+	}
+	return nil
+}
+
+func (s *AdminService) findUser(tx *gorm.DB, userID uint) (*entity.User, error) {
+	var user entity.User
+	if err := tx.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("error getting user: %v", err)
+	}
+	return &user, nil
+}
+
+func toAdminUserResponse(user entity.User) response.AdminUserResponse {
+	return response.AdminUserResponse{
+		ID:                    user.ID,
+		Name:                  user.Name,
+		Email:                 user.Email,
+		Username:              user.Username,
+		Role:                  user.Role,
+		Provider:              user.Provider,
+		EmailVerified:         user.EmailVerified,
+		TwoFactorEnabled:      user.TwoFactorEnabled,
+		Suspended:             user.IsSuspended(),
+		SuspendedAt:           user.SuspendedAt,
+		SuspendedReason:       user.SuspendedReason,
+		PasswordResetRequired: user.PasswordResetRequired,
+		LastLoginAt:           user.LastLoginAt,
+		CreatedAt:             user.CreatedAt,
+	}
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	statsGranularityDay   = "day"
+	statsGranularityMonth = "month"
+
+	maxStatsDays   = 366
+	maxStatsMonths = 60
+)
+
+var ErrInvalidStatsRange = errors.New("invalid stats range, use start_date <= end_date in format YYYY-MM-DD (max 366 days or 60 months)")
+
+// AdminStatsService menghitung statistik platform untuk dashboard admin.
+// Semua query hanya menghasilkan jumlah (COUNT), nominal dan deskripsi transaksi tidak pernah dibaca.
+type AdminStatsService struct {
+	DB *gorm.DB
+}
+
+func NewAdminStatsService(db *gorm.DB) *AdminStatsService {
+	return &AdminStatsService{DB: db}
+}
+
+// statsRange adalah rentang waktu [start, end) beserta daftar periode untuk mengisi periode yang kosong
+type statsRange struct {
+	start       time.Time
+	end         time.Time
+	granularity string
+	periods     []string
+}
+
+func parseStatsRange(filter request.AdminStatsFilter, now time.Time) (*statsRange, error) {
+	granularity := filter.Granularity
+	if granularity == "" {
+		granularity = statsGranularityDay
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	endDay := today
+	if filter.EndDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", filter.EndDate, now.Location())
+		if err != nil {
+			return nil, ErrInvalidStatsRange
+		}
+		endDay = parsed
+	}
+
+	var startDay time.Time
+	if filter.StartDate != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", filter.StartDate, now.Location())
+		if err != nil {
+			return nil, ErrInvalidStatsRange
+		}
+		startDay = parsed
+	} else if granularity == statsGranularityMonth {
+		startDay = time.Date(endDay.Year(), endDay.Month()-11, 1, 0, 0, 0, 0, now.Location())
+	} else {
+		startDay = endDay.AddDate(0, 0, -29)
+	}
+
+	if startDay.After(endDay) {
+		return nil, ErrInvalidStatsRange
+	}
+
+	r := &statsRange{granularity: granularity}
+	if granularity == statsGranularityMonth {
+		r.start = time.Date(startDay.Year(), startDay.Month(), 1, 0, 0, 0, 0, now.Location())
+		r.end = time.Date(endDay.Year(), endDay.Month()+1, 1, 0, 0, 0, 0, now.Location())
+		for t := r.start; t.Before(r.end); t = t.AddDate(0, 1, 0) {
+			r.periods = append(r.periods, t.Format("2006-01"))
+		}
+		if len(r.periods) > maxStatsMonths {
+			return nil, ErrInvalidStatsRange
+		}
+	} else {
+		r.start = startDay
+		r.end = endDay.AddDate(0, 0, 1)
+		for t := r.start; t.Before(r.end); t = t.AddDate(0, 0, 1) {
+			r.periods = append(r.periods, t.Format("2006-01-02"))
+		}
+		if len(r.periods) > maxStatsDays {
+			return nil, ErrInvalidStatsRange
+		}
+	}
+
+	return r, nil
+}
+
+// periodExpr mengubah kolom waktu menjadi label periode, granularity sudah divalidasi sehingga aman disisipkan ke query
+func (r *statsRange) periodExpr(column string) string {
+	if r.granularity == statsGranularityMonth {
+		return fmt.Sprintf("TO_CHAR(DATE_TRUNC('month', %s), 'YYYY-MM')", column)
+	}
+	return fmt.Sprintf("TO_CHAR(DATE_TRUNC('day', %s), 'YYYY-MM-DD')", column)
+}
+
+func (r *statsRange) toResponse() response.StatsRange {
+	return response.StatsRange{
+		StartDate:   r.start.Format("2006-01-02"),
+		EndDate:     r.end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Granularity: r.granularity,
+	}
+}
+
+func (s *AdminStatsService) GetOverview() (*response.PlatformOverviewResponse, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	overview := response.PlatformOverviewResponse{
+		UsersByProvider: map[string]int64{},
+	}
+
+	var providers []struct {
+		Provider string
+		Total    int64
+	}
+	if err := s.DB.Model(&entity.User{}).
+		Select("provider, COUNT(*) AS total").
+		Group("provider").
+		Scan(&providers).Error; err != nil {
+		return nil, fmt.Errorf("error counting users: %v", err)
+	}
+	for _, p := range providers {
+		overview.UsersByProvider[p.Provider] = p.Total
+		overview.TotalUsers += p.Total
+	}
+
+	counts := []struct {
+		query *gorm.DB
+		dest  *int64
+		name  string
+	}{
+		{s.DB.Model(&entity.User{}).Where("email_verified = ?", true), &overview.VerifiedUsers, "verified users"},
+		{s.DB.Model(&entity.User{}).Where("suspended_at IS NOT NULL"), &overview.SuspendedUsers, "suspended users"},
+		{s.DB.Model(&entity.UserActivity{}).Where("activity_date = ?", today), &overview.DailyActiveUsers, "daily active users"},
+		{s.DB.Model(&entity.UserActivity{}).Distinct("user_id").Where("activity_date > ?", today.AddDate(0, 0, -30)), &overview.MonthlyActiveUsers, "monthly active users"},
+		{s.DB.Model(&entity.Transaction{}), &overview.TotalTransactions, "transactions"},
+		{s.DB.Model(&entity.ChatUsage{}).Where("created_at >= ?", now.AddDate(0, 0, -30)), &overview.ChatMessages30d, "chat messages"},
+	}
+	for _, c := range counts {
+		if err := c.query.Count(c.dest).Error; err != nil {
+			return nil, fmt.Errorf("error counting %s: %v", c.name, err)
+		}
+	}
+
+	return &overview, nil
+}
+
+// GetSignupStats menghitung pendaftaran user per periode dan provider (email, google).
+// Akun yang sudah dihapus tetap dihitung karena pendaftarannya tetap terjadi.
+func (s *AdminStatsService) GetSignupStats(filter request.AdminStatsFilter) (*response.SignupStatsResponse, error) {
+	r, err := parseStatsRange(filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Period   string
+		Provider string
+		Total    int64
+	}
+	if err := s.DB.Unscoped().Model(&entity.User{}).
+		Select(r.periodExpr("created_at")+" AS period, provider, COUNT(*) AS total").
+		Where("created_at >= ? AND created_at < ?", r.start, r.end).
+		Group("period, provider").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error getting signup stats: %v", err)
+	}
+
+	points := make([]response.SignupStatsPoint, len(r.periods))
+	index := make(map[string]int, len(r.periods))
+	for i, period := range r.periods {
+		points[i] = response.SignupStatsPoint{Period: period, ByProvider: map[string]int64{}}
+		index[period] = i
+	}
+	for _, row := range rows {
+		if i, ok := index[row.Period]; ok {
+			points[i].ByProvider[row.Provider] += row.Total
+			points[i].Total += row.Total
+		}
+	}
+
+	return &response.SignupStatsResponse{StatsRange: r.toResponse(), Points: points}, nil
+}
+
+// GetActiveUserStats menghitung user unik yang aktif per hari (DAU) atau per bulan (MAU)
+func (s *AdminStatsService) GetActiveUserStats(filter request.AdminStatsFilter) (*response.ActiveUserStatsResponse, error) {
+	r, err := parseStatsRange(filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Period string
+		Total  int64
+	}
+	if err := s.DB.Model(&entity.UserActivity{}).
+		Select(r.periodExpr("activity_date")+" AS period, COUNT(DISTINCT user_id) AS total").
+		Where("activity_date >= ? AND activity_date < ?", r.start, r.end).
+		Group("period").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error getting active user stats: %v", err)
+	}
+
+	totals := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		totals[row.Period] = row.Total
+	}
+
+	points := make([]response.ActiveUserStatsPoint, len(r.periods))
+	for i, period := range r.periods {
+		points[i] = response.ActiveUserStatsPoint{Period: period, ActiveUsers: totals[period]}
+	}
+
+	return &response.ActiveUserStatsResponse{StatsRange: r.toResponse(), Points: points}, nil
+}
+
+// GetTransactionVolumeStats menghitung jumlah transaksi yang dicatat per periode, tanpa nominal
+func (s *AdminStatsService) GetTransactionVolumeStats(filter request.AdminStatsFilter) (*response.TransactionVolumeResponse, error) {
+	r, err := parseStatsRange(filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Period  string
+		Total   int64
+		Income  int64
+		Expense int64
+	}
+	if err := s.DB.Model(&entity.Transaction{}).
+		Select(r.periodExpr("created_at")+" AS period, COUNT(*) AS total, "+
+			"SUM(CASE WHEN type = 'income' THEN 1 ELSE 0 END) AS income, "+
+			"SUM(CASE WHEN type = 'expense' THEN 1 ELSE 0 END) AS expense").
+		Where("created_at >= ? AND created_at < ?", r.start, r.end).
+		Group("period").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error getting transaction volume stats: %v", err)
+	}
+
+	points := make([]response.TransactionVolumePoint, len(r.periods))
+	index := make(map[string]int, len(r.periods))
+	for i, period := range r.periods {
+		points[i] = response.TransactionVolumePoint{Period: period}
+		index[period] = i
+	}
+	for _, row := range rows {
+		if i, ok := index[row.Period]; ok {
+			points[i].Total = row.Total
+			points[i].Income = row.Income
+			points[i].Expense = row.Expense
+		}
+	}
+
+	return &response.TransactionVolumeResponse{StatsRange: r.toResponse(), Points: points}, nil
+}
+
+// GetChatUsageStats menghitung jumlah pesan chat dan user unik yang memakai chat per periode
+func (s *AdminStatsService) GetChatUsageStats(filter request.AdminStatsFilter) (*response.ChatUsageResponse, error) {
+	r, err := parseStatsRange(filter, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Period   string
+		Messages int64
+		Users    int64
+	}
+	if err := s.DB.Model(&entity.ChatUsage{}).
+		Select(r.periodExpr("created_at")+" AS period, COUNT(*) AS messages, COUNT(DISTINCT user_id) AS users").
+		Where("created_at >= ? AND created_at < ?", r.start, r.end).
+		Group("period").
+		Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error getting chat usage stats: %v", err)
+	}
+
+	points := make([]response.ChatUsagePoint, len(r.periods))
+	index := make(map[string]int, len(r.periods))
+	for i, period := range r.periods {
+		points[i] = response.ChatUsagePoint{Period: period}
+		index[period] = i
+	}
+	for _, row := range rows {
+		if i, ok := index[row.Period]; ok {
+			points[i].Messages = row.Messages
+			points[i].Users = row.Users
+		}
+	}
+
+	return &response.ChatUsageResponse{StatsRange: r.toResponse(), Points: points}, nil
+}
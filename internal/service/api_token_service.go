@@ -0,0 +1,155 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// prefix personal access token, dipakai middleware untuk membedakan dengan JWT
+const APITokenPrefix = "ftp_"
+
+// scope personal access token
+const (
+	ScopeTransactionsRead  = "transactions:read"
+	ScopeTransactionsWrite = "transactions:write"
+	ScopeCategoriesRead    = "categories:read"
+	ScopeCategoriesWrite   = "categories:write"
+	ScopeDashboardRead     = "dashboard:read"
+	ScopeExport            = "export"
+)
+
+var validAPITokenScopes = map[string]bool{
+	ScopeTransactionsRead:  true,
+	ScopeTransactionsWrite: true,
+	ScopeCategoriesRead:    true,
+	ScopeCategoriesWrite:   true,
+	ScopeDashboardRead:     true,
+	ScopeExport:            true,
+}
+
+var (
+	ErrInvalidAPITokenScope = errors.New("invalid API token scope")
+	ErrAPITokenNotFound     = errors.New("API token not found")
+	ErrInvalidAPIToken      = errors.New("API token is invalid, revoked or expired")
+)
+
+type APITokenService struct {
+	DB *gorm.DB
+}
+
+func NewAPITokenService(db *gorm.DB) *APITokenService {
+	return &APITokenService{DB: db}
+}
+
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, APITokenPrefix)
+}
+
+// CreateToken membuat token baru, token asli hanya dikembalikan sekali dan yang disimpan hanya hash-nya
+func (s *APITokenService) CreateToken(userID uint, req request.CreateAPITokenRequest) (*response.APITokenCreatedResponse, error) {
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := map[string]bool{}
+	for _, scope := range req.Scopes {
+		scope = strings.TrimSpace(scope)
+		if !validAPITokenScopes[scope] {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAPITokenScope, scope)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	token := APITokenPrefix + utility.GenerateSecureToken()
+	apiToken := entity.APIToken{
+		UserID:      userID,
+		Name:        strings.TrimSpace(req.Name),
+		TokenHash:   utility.HashToken(token),
+		TokenPrefix: token[:len(APITokenPrefix)+6],
+		Scopes:      strings.Join(scopes, ","),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, req.ExpiresInDays)
+		apiToken.ExpiresAt = &expiresAt
+	}
+
+	if err := s.DB.Create(&apiToken).Error; err != nil {
+		return nil, fmt.Errorf("error creating API token: %v", err)
+	}
+
+	return &response.APITokenCreatedResponse{
+		APITokenResponse: toAPITokenResponse(apiToken),
+		Token:            token,
+	}, nil
+}
+
+func (s *APITokenService) ListTokens(userID uint) ([]response.APITokenResponse, error) {
+	var tokens []entity.APIToken
+	if err := s.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error; err != nil {
+		return nil, errors.New("failed to get API tokens")
+	}
+
+	tokenResponses := make([]response.APITokenResponse, len(tokens))
+	for i, token := range tokens {
+		tokenResponses[i] = toAPITokenResponse(token)
+	}
+
+	return tokenResponses, nil
+}
+
+func (s *APITokenService) RevokeToken(userID uint, tokenID uint) error {
+	result := s.DB.Where("id = ? AND user_id = ?", tokenID, userID).Delete(&entity.APIToken{})
+	if result.Error != nil {
+		return errors.New("failed to revoke API token")
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrAPITokenNotFound
+	}
+
+	return nil
+}
+
+// Authenticate mencari token berdasarkan hash dan memperbarui waktu terakhir dipakai
+func (s *APITokenService) Authenticate(token string) (*entity.APIToken, error) {
+	var apiToken entity.APIToken
+	if err := s.DB.Where("token_hash = ?", utility.HashToken(token)).First(&apiToken).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidAPIToken
+		}
+		return nil, fmt.Errorf("error getting API token: %v", err)
+	}
+
+	now := time.Now()
+	if apiToken.IsExpired(now) {
+		return nil, ErrInvalidAPIToken
+	}
+
+	if apiToken.LastUsedAt == nil || now.Sub(*apiToken.LastUsedAt) >= sessionTouchInterval {
+		if err := s.DB.Model(&apiToken).UpdateColumn("last_used_at", now).Error; err != nil {
+			return nil, fmt.Errorf("error updating API token: %v", err)
+		}
+	}
+
+	return &apiToken, nil
+}
+
+func toAPITokenResponse(token entity.APIToken) response.APITokenResponse {
+	return response.APITokenResponse{
+		ID:          token.ID,
+		Name:        token.Name,
+		TokenPrefix: token.TokenPrefix,
+		Scopes:      token.ScopeList(),
+		ExpiresAt:   token.ExpiresAt,
+		LastUsedAt:  token.LastUsedAt,
+		CreatedAt:   token.CreatedAt,
+	}
+}
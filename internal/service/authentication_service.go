@@ -1,27 +1,33 @@
 package service
 
 import (
-	"context"
 	"errors"
 	"fmt"
 	"go-fintrack/internal/payload/entity"
 	"go-fintrack/internal/payload/request"
 	"go-fintrack/internal/utility"
 	"regexp"
-	"strings"
-	"unicode"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
 
 type UserService struct {
-	DB *gorm.DB
+	DB          *gorm.DB
+	EmailSender utility.EmailSender
+	AppURL      string // url frontend untuk link di email
+	LoginGuard  *LoginGuard
+	UploadDir   string // direktori file upload user, mis. foto profil
 }
 
 var (
-	ErrUserExists         = errors.New("user with this email or username already exists")
-	ErrInvalidCredentials = errors.New("invalid email/username or password")
-	ErrWeakPassword       = errors.New("password must contain at least one uppercase letter, one lowercase letter, one number")
+	ErrUserExists            = errors.New("user with this email or username already exists")
+	ErrInvalidCredentials    = errors.New("invalid email/username or password")
+	ErrWeakPassword          = errors.New("password must contain at least one uppercase letter, one lowercase letter, one number")
+	ErrAccountSuspended      = errors.New("account is suspended")
+	ErrAccountInactive       = errors.New("account is suspended or no longer exists")
+	ErrPasswordResetRequired = errors.New("password reset is required, check your email for the reset link")
 )
 
 func (s *UserService) validatePassword(password string) error {
@@ -50,6 +56,7 @@ func (s *UserService) RegisterUser(name, email, username, password string) error
 		return fmt.Errorf("username must be at least 3 characters long")
 	}
 
+	var newUser entity.User
 	err := s.DB.Transaction(func(tx *gorm.DB) error {
 		// cek email or username exists
 		var existingUser entity.User
@@ -66,11 +73,12 @@ func (s *UserService) RegisterUser(name, email, username, password string) error
 		}
 
 		// create user
-		newUser := entity.User{
+		newUser = entity.User{
 			Name:     name,
 			Email:    email,
 			Username: username,
 			Password: hashedPassword,
+			Role:     entity.RoleUser,
 			Provider: "email",
 		}
 
@@ -81,28 +89,72 @@ func (s *UserService) RegisterUser(name, email, username, password string) error
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	// gagal kirim email tidak menggagalkan registrasi, user bisa minta kirim ulang
+	if err := s.SendVerificationEmail(&newUser); err != nil {
+		logrus.Errorf("Failed to send verification email: %v", err)
+	}
+
+	return nil
 }
 
-func (s *UserService) Login(emailOrUsername, password string) (string, *entity.User, error) {
+func (s *UserService) Login(emailOrUsername, password string, client request.ClientInfo) (string, *entity.User, error) {
 	var user entity.User
 
+	// tolak lebih awal jika IP sedang dikunci
+	if err := s.checkLoginGuard(ipAttemptKey(client.IPAddress)); err != nil {
+		return "", nil, err
+	}
+
 	// find user
 	if err := s.DB.Where("email = ? OR username = ?", emailOrUsername, emailOrUsername).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
+			if err := s.checkLoginGuard(identifierAttemptKey(emailOrUsername)); err != nil {
+				return "", nil, err
+			}
+			if err := s.registerLoginFailure(identifierAttemptKey(emailOrUsername), nil, client); err != nil {
+				return "", nil, err
+			}
 			return "", nil, ErrInvalidCredentials
 		}
 		return "", nil, fmt.Errorf("internal server error during login: %v", err)
 	}
 
+	// akun yang sedang dikunci tidak dicek password-nya
+	if err := s.checkLoginGuard(accountAttemptKey(user.ID)); err != nil {
+		return "", nil, err
+	}
+
 	// verify password
 	if err := utility.CompareHashAndPassword(user.Password, password); err != nil {
+		if err := s.registerLoginFailure(accountAttemptKey(user.ID), &user.ID, client); err != nil {
+			return "", nil, err
+		}
 		return "", nil, ErrInvalidCredentials
 	}
 
-	// generate jwt
-	token, err := utility.GenerateJWT(user.ID, user.Username, user.IsAdmin)
+	if err := s.CheckAccountStatus(&user); err != nil {
+		return "", nil, err
+	}
+
+	// jika 2FA aktif, kembalikan challenge token dan JWT baru diberikan setelah kode 2FA diverifikasi
+	if user.TwoFactorEnabled {
+		challenge, err := utility.GenerateChallengeJWT(user.ID)
+		if err != nil {
+			return "", nil, fmt.Errorf("internal server error during login: %v", err)
+		}
+		return challenge, &user, nil
+	}
+
+	if err := s.registerLoginSuccess(accountAttemptKey(user.ID)); err != nil {
+		return "", nil, err
+	}
+
+	// buat session dan generate jwt
+	token, err := s.IssueToken(&user, client)
 	if err != nil {
 		return "", nil, fmt.Errorf("internal server error during login: %v", err)
 	}
@@ -110,45 +162,69 @@ func (s *UserService) Login(emailOrUsername, password string) (string, *entity.U
 	return token, &user, nil
 }
 
-func (s *UserService) UpsertGoogleUser(ctx context.Context, googleUser *request.GoogleUser) (*entity.User, error) {
-	var user entity.User
-
-	err := s.DB.Transaction(func(tx *gorm.DB) error {
-		result := tx.Where("email = ?", googleUser.Email).First(&user)
-
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			newUser := entity.User{
-				Name:       googleUser.Name,
-				Username:   strings.ToLower(strings.Join(strings.FieldsFunc(googleUser.Name, func(r rune) bool { return unicode.IsSpace(r) }), "")),
-				Email:      googleUser.Email,
-				IsAdmin:    false,
-				Provider:   "google",
-				ProfilePic: googleUser.Picture,
-			}
+func (s *UserService) checkLoginGuard(keys ...string) error {
+	if s.LoginGuard == nil {
+		return nil
+	}
+	return s.LoginGuard.Check(keys...)
+}
 
-			if err := tx.Create(&newUser).Error; err != nil {
-				return fmt.Errorf("error creating user: %v", err)
-			}
-		} else if result.Error != nil {
-			return fmt.Errorf("error checking user existence: %v", result.Error)
-		} else {
-			// update user jika ada
-			user.Name = googleUser.Name
-			user.Username = strings.ToLower(strings.Join(strings.FieldsFunc(googleUser.Name, func(r rune) bool { return unicode.IsSpace(r) }), ""))
-			user.ProfilePic = googleUser.Picture
-			user.Provider = "google"
-
-			if err := tx.Save(&user).Error; err != nil {
-				return fmt.Errorf("error updating user: %v", err)
-			}
-		}
+func (s *UserService) registerLoginFailure(accountKey string, userID *uint, client request.ClientInfo) error {
+	if s.LoginGuard == nil {
+		return nil
+	}
+	return s.LoginGuard.RegisterFailure(accountKey, userID, client)
+}
 
+func (s *UserService) registerLoginSuccess(accountKey string) error {
+	if s.LoginGuard == nil {
 		return nil
-	})
+	}
+	return s.LoginGuard.RegisterSuccess(accountKey)
+}
+
+// CheckAccountStatus menolak login untuk akun yang di-suspend atau wajib reset password
+func (s *UserService) CheckAccountStatus(user *entity.User) error {
+	if user.IsSuspended() {
+		return ErrAccountSuspended
+	}
+	if user.PasswordResetRequired {
+		return ErrPasswordResetRequired
+	}
+	return nil
+}
 
+// CheckAccountActive dipakai middleware agar JWT atau API token milik akun yang di-suspend/dihapus langsung ditolak
+func (s *UserService) CheckAccountActive(userID uint) error {
+	var count int64
+	if err := s.DB.Model(&entity.User{}).
+		Where("id = ? AND suspended_at IS NULL", userID).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("error checking account status: %v", err)
+	}
+
+	if count == 0 {
+		return ErrAccountInactive
+	}
+	return nil
+}
+
+// IssueToken membuat session baru untuk device dan menandatangani JWT yang terikat ke session tersebut
+func (s *UserService) IssueToken(user *entity.User, client request.ClientInfo) (string, error) {
+	if err := s.CheckAccountStatus(user); err != nil {
+		return "", err
+	}
+
+	session, err := NewSessionService(s.DB).CreateSession(nil, user.ID, client)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+
+	now := time.Now()
+	if err := s.DB.Model(&entity.User{}).Where("id = ?", user.ID).UpdateColumn("last_login_at", now).Error; err != nil {
+		return "", fmt.Errorf("error updating last login: %v", err)
 	}
+	user.LastLoginAt = &now
 
-	return &user, nil
+	return utility.GenerateJWT(user.ID, user.Username, session.TokenID, 0)
 }
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrResourceNotFound  = errors.New("resource not found")
+	ErrResourceForbidden = errors.New("resource access forbidden")
+)
+
+// nama resource yang dipakai pada pesan AccessError
+const (
+	ResourceCategory    = "category"
+	ResourceTransaction = "transaction"
+)
+
+// AccessError dikembalikan jika resource yang direferensikan bukan milik workspace caller.
+// Resource milik workspace lain dilaporkan sebagai not found agar keberadaannya tidak bocor.
+type AccessError struct {
+	Resource string
+	Err      error // ErrResourceNotFound atau ErrResourceForbidden
+}
+
+func (e *AccessError) Error() string {
+	if e.Err == ErrResourceForbidden {
+		return fmt.Sprintf("you do not have permission to modify this %s", e.Resource)
+	}
+	return fmt.Sprintf("%s not found", e.Resource)
+}
+
+func (e *AccessError) Unwrap() error {
+	return e.Err
+}
+
+func resourceNotFound(resource string) error {
+	return &AccessError{Resource: resource, Err: ErrResourceNotFound}
+}
+
+// Authorizer memusatkan pengecekan kepemilikan resource, dipakai semua service yang
+// menerima ID resource dari request (path parameter maupun field body seperti category_id)
+type Authorizer struct {
+	DB *gorm.DB
+}
+
+func NewAuthorizer(db *gorm.DB) *Authorizer {
+	return &Authorizer{DB: db}
+}
+
+// FindOwned mengisi dest dengan record id yang berada di workspace scope
+func (a *Authorizer) FindOwned(scope request.WorkspaceScope, resource string, dest interface{}, id uint) error {
+	if id == 0 {
+		return resourceNotFound(resource)
+	}
+
+	if err := a.DB.Where("id = ? AND workspace_id = ?", id, scope.WorkspaceID).First(dest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return resourceNotFound(resource)
+		}
+		return fmt.Errorf("error getting %s: %v", resource, err)
+	}
+	return nil
+}
+
+// CanWrite menolak perubahan data oleh viewer, lapisan kedua setelah middleware WorkspaceContext
+func (a *Authorizer) CanWrite(scope request.WorkspaceScope, resource string) error {
+	if scope.Role == entity.WorkspaceRoleViewer {
+		return &AccessError{Resource: resource, Err: ErrResourceForbidden}
+	}
+	return nil
+}
+
+// FindOwnedForWrite menggabungkan CanWrite dan FindOwned untuk update/delete
+func (a *Authorizer) FindOwnedForWrite(scope request.WorkspaceScope, resource string, dest interface{}, id uint) error {
+	if err := a.CanWrite(scope, resource); err != nil {
+		return err
+	}
+	return a.FindOwned(scope, resource, dest, id)
+}
@@ -12,16 +12,17 @@ import (
 )
 
 type CategoryService struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Events *WorkspaceEvents // opsional, dikabari setiap kali kategori workspace berubah
 }
 
-func (s *CategoryService) GetCategories(userID uint) ([]response.CategoryResponse, error) {
+func (s *CategoryService) GetCategories(scope request.WorkspaceScope) ([]response.CategoryResponse, error) {
 	var categories []entity.Category
 
 	var totalTransactions int64
-	s.DB.Model(&entity.Transaction{}).Where("user_id = ?", userID).Count(&totalTransactions)
+	s.DB.Model(&entity.Transaction{}).Where("workspace_id = ?", scope.WorkspaceID).Count(&totalTransactions)
 
-	if err := s.DB.Where("user_id = ?", userID).Find(&categories).Error; err != nil {
+	if err := s.DB.Where("workspace_id = ?", scope.WorkspaceID).Find(&categories).Error; err != nil {
 		return nil, errors.New("failed to get all category")
 	}
 
@@ -62,13 +63,10 @@ func (s *CategoryService) GetCategories(userID uint) ([]response.CategoryRespons
 	return categoryResponse, nil
 }
 
-func (s *CategoryService) GetCategoryByID(categoryID uint, userID uint) (*response.CategoryResponse, error) {
+func (s *CategoryService) GetCategoryByID(categoryID uint, scope request.WorkspaceScope) (*response.CategoryResponse, error) {
 	var category entity.Category
-	if err := s.DB.Where("id = ? AND user_id = ?", categoryID, userID).First(&category).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category not found")
-		}
-		return nil, errors.New("failed to get category")
+	if err := NewAuthorizer(s.DB).FindOwned(scope, ResourceCategory, &category, categoryID); err != nil {
+		return nil, err
 	}
 
 	deletedAt := category.DeletedAt.Time
@@ -87,26 +85,32 @@ func (s *CategoryService) GetCategoryByID(categoryID uint, userID uint) (*respon
 	}, nil
 }
 
-func (s *CategoryService) CreateCategory(req *request.CategoryRequest, userID uint) (*response.CategoryResponse, error) {
+func (s *CategoryService) CreateCategory(req *request.CategoryRequest, scope request.WorkspaceScope) (*response.CategoryResponse, error) {
+	if err := NewAuthorizer(s.DB).CanWrite(scope, ResourceCategory); err != nil {
+		return nil, err
+	}
+
 	nameToLower := strings.ToLower(strings.TrimSpace(req.Name))
 
 	// check existing
 	var existingCategory entity.Category
-	if err := s.DB.Where("LOWER(name) = ? AND user_id = ?", nameToLower, userID).First(&existingCategory).Error; err == nil {
+	if err := s.DB.Where("LOWER(name) = ? AND workspace_id = ?", nameToLower, scope.WorkspaceID).First(&existingCategory).Error; err == nil {
 		return nil, errors.New("category name already exists")
 	}
 
 	// create category
 	newCategory := entity.Category{
-		UserID:    userID,
-		Name:      nameToLower,
-		Color:     req.Color,
-		IconColor: req.IconColor,
+		WorkspaceID: scope.WorkspaceID,
+		UserID:      scope.UserID,
+		Name:        nameToLower,
+		Color:       req.Color,
+		IconColor:   req.IconColor,
 	}
 
 	if err := s.DB.Create(&newCategory).Error; err != nil {
 		return nil, errors.New("failed to create user")
 	}
+	s.Events.Publish(scope.WorkspaceID)
 
 	return &response.CategoryResponse{
 		ID:        newCategory.ID,
@@ -119,21 +123,18 @@ func (s *CategoryService) CreateCategory(req *request.CategoryRequest, userID ui
 	}, nil
 }
 
-func (s *CategoryService) UpdateCategory(categoryID uint, userID uint, req *request.UpdateCategoryRequest) (*response.CategoryResponse, error) {
+func (s *CategoryService) UpdateCategory(categoryID uint, scope request.WorkspaceScope, req *request.UpdateCategoryRequest) (*response.CategoryResponse, error) {
 	nameToLower := strings.ToLower(strings.TrimSpace(req.Name))
 
 	// check category
 	var category entity.Category
-	if err := s.DB.Where("id = ? AND user_id = ?", categoryID, userID).First(&category).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category not found")
-		}
-		return nil, errors.New("failed to get category")
+	if err := NewAuthorizer(s.DB).FindOwnedForWrite(scope, ResourceCategory, &category, categoryID); err != nil {
+		return nil, err
 	}
 
 	// check nama baru setelah update already exists
 	var existingCategory entity.Category
-	if err := s.DB.Where("LOWER(name) = ? AND user_id = ? AND id != ?", nameToLower, userID, categoryID).First(&existingCategory).Error; err == nil {
+	if err := s.DB.Where("LOWER(name) = ? AND workspace_id = ? AND id != ?", nameToLower, scope.WorkspaceID, categoryID).First(&existingCategory).Error; err == nil {
 		return nil, errors.New("category name already exists")
 	}
 
@@ -150,10 +151,11 @@ func (s *CategoryService) UpdateCategory(categoryID uint, userID uint, req *requ
 	if err := s.DB.Save(&category).Error; err != nil {
 		return nil, errors.New("failed to update category")
 	}
+	s.Events.Publish(scope.WorkspaceID)
 
 	// Hitung usage count dan percentage untuk response
 	var totalTransactions int64
-	s.DB.Model(&entity.Transaction{}).Where("user_id = ?", userID).Count(&totalTransactions)
+	s.DB.Model(&entity.Transaction{}).Where("workspace_id = ?", scope.WorkspaceID).Count(&totalTransactions)
 
 	var usageCount int64
 	s.DB.Model(&entity.Transaction{}).Where("category_id = ?", category.ID).Count(&usageCount)
@@ -176,15 +178,20 @@ func (s *CategoryService) UpdateCategory(categoryID uint, userID uint, req *requ
 	}, nil
 }
 
-func (s *CategoryService) DeleteCategory(categoryID uint, userID uint) error {
-	result := s.DB.Where("id = ? AND user_id = ?", categoryID, userID).Delete(&entity.Category{})
+func (s *CategoryService) DeleteCategory(categoryID uint, scope request.WorkspaceScope) error {
+	if err := NewAuthorizer(s.DB).CanWrite(scope, ResourceCategory); err != nil {
+		return err
+	}
+
+	result := s.DB.Where("id = ? AND workspace_id = ?", categoryID, scope.WorkspaceID).Delete(&entity.Category{})
 	if result.Error != nil {
 		return errors.New("failed to delete category")
 	}
 
 	if result.RowsAffected == 0 {
-		return errors.New("category not found")
+		return resourceNotFound(ResourceCategory)
 	}
+	s.Events.Publish(scope.WorkspaceID)
 
 	return nil
 }
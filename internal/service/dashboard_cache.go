@@ -0,0 +1,292 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/utility"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DashboardCache menyimpan response dashboard yang sudah di-serialize. Implementasi default adalah LRU
+// in-memory, cache bersama (mis. Redis) cukup mengimplementasikan interface ini agar bisa dipakai semua instance.
+type DashboardCache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+	// DeletePrefix menghapus semua entry dengan prefix key, dipakai untuk invalidasi per workspace
+	DeletePrefix(prefix string)
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// LRUDashboardCache adalah DashboardCache in-memory dengan batas jumlah entry dan TTL
+type LRUDashboardCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	entries  *list.List // depan = paling baru dipakai
+	items    map[string]*list.Element
+	now      func() time.Time
+}
+
+func NewLRUDashboardCache(capacity int, ttl time.Duration) *LRUDashboardCache {
+	return &LRUDashboardCache{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  list.New(),
+		items:    make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+func (c *LRUDashboardCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if c.ttl > 0 && c.now().After(entry.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+
+	c.entries.MoveToFront(element)
+	return entry.value, true
+}
+
+func (c *LRUDashboardCache) Set(key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	if element, ok := c.items[key]; ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.entries.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.entries.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+	for c.capacity > 0 && c.entries.Len() > c.capacity {
+		c.remove(c.entries.Back())
+	}
+}
+
+func (c *LRUDashboardCache) DeletePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, element := range c.items {
+		if strings.HasPrefix(key, prefix) {
+			c.remove(element)
+		}
+	}
+}
+
+func (c *LRUDashboardCache) remove(element *list.Element) {
+	c.entries.Remove(element)
+	delete(c.items, element.Value.(*lruEntry).key)
+}
+
+// CachedDashboard adalah data dashboard dalam bentuk JSON beserta ETag-nya
+type CachedDashboard struct {
+	Body []byte
+	ETag string
+}
+
+// CachedDashboardService berada di depan DashboardService. Key cache memuat workspace, user, rentang yang sudah
+// di-resolve dan versi preferensi user, sehingga pergantian hari atau preferensi otomatis memakai key baru.
+// Entry workspace dihapus lewat WorkspaceEvents saat transaksi atau kategorinya berubah.
+type CachedDashboardService struct {
+	Dashboard *DashboardService
+	Cache     DashboardCache // nil berarti tanpa cache
+
+	// generasi cache per workspace, naik setiap Invalidate. Hasil yang dihitung sebelum invalidasi tidak disimpan.
+	mu          sync.Mutex
+	generations map[uint]uint64
+}
+
+func NewCachedDashboardService(dashboard *DashboardService, cache DashboardCache, events *WorkspaceEvents) *CachedDashboardService {
+	s := &CachedDashboardService{Dashboard: dashboard, Cache: cache, generations: map[uint]uint64{}}
+	if cache != nil && events != nil {
+		events.Subscribe(s.Invalidate)
+	}
+	return s
+}
+
+func dashboardCachePrefix(workspaceID uint) string {
+	return fmt.Sprintf("dashboard:%d:", workspaceID)
+}
+
+// Invalidate menghapus semua cache dashboard workspace, untuk semua member
+func (s *CachedDashboardService) Invalidate(workspaceID uint) {
+	if s.Cache == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.generations == nil {
+		s.generations = map[uint]uint64{}
+	}
+	s.generations[workspaceID]++
+	s.Cache.DeletePrefix(dashboardCachePrefix(workspaceID))
+}
+
+func (s *CachedDashboardService) generation(workspaceID uint) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.generations[workspaceID]
+}
+
+func (s *CachedDashboardService) GetFinancialOverview(ctx context.Context, scope request.WorkspaceScope, filter request.DashboardFilter) (*CachedDashboard, error) {
+	preference, r, err := s.resolveRange(ctx, scope, filter, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cached(scope.WorkspaceID, rangeCacheKey(scope, "overview", r, preference), func() (interface{}, error) {
+		return s.Dashboard.financialOverview(ctx, scope, preference, r)
+	})
+}
+
+func (s *CachedDashboardService) GetDashboardCharts(ctx context.Context, scope request.WorkspaceScope, filter request.DashboardFilter) (*CachedDashboard, error) {
+	preference, r, err := s.resolveRange(ctx, scope, filter, defaultChartPeriods)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cached(scope.WorkspaceID, rangeCacheKey(scope, "charts", r, preference), func() (interface{}, error) {
+		return s.Dashboard.dashboardCharts(ctx, scope, preference, r)
+	})
+}
+
+// GetCashFlowForecast di-cache per hari ini di timezone user sehingga proyeksi bergeser otomatis setiap hari
+func (s *CachedDashboardService) GetCashFlowForecast(ctx context.Context, scope request.WorkspaceScope, filter request.ForecastFilter) (*CachedDashboard, error) {
+	preference, err := loadUserPreference(s.Dashboard.DB.WithContext(ctx), scope.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	today, days := forecastToday(preference, time.Now()), forecastDays(filter)
+	key := fmt.Sprintf("%s%d:forecast:%s:%d:%d", dashboardCachePrefix(scope.WorkspaceID), scope.UserID,
+		today.Format("2006-01-02"), days, preference.UpdatedAt.UnixNano())
+	return s.cached(scope.WorkspaceID, key, func() (interface{}, error) {
+		return s.Dashboard.cashFlowForecast(ctx, scope, preference, today, days)
+	})
+}
+
+// GetPeriodComparison di-cache per pasangan rentang yang sudah di-resolve, tipe dan jumlah top mover
+func (s *CachedDashboardService) GetPeriodComparison(ctx context.Context, scope request.WorkspaceScope, filter request.ComparisonFilter) (*CachedDashboard, error) {
+	preference, err := loadUserPreference(s.Dashboard.DB.WithContext(ctx), scope.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, previous, err := resolveComparison(filter, preference, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s%d:compare:%s:%s:%s:%s:%s:%d:%d", dashboardCachePrefix(scope.WorkspaceID), scope.UserID,
+		current.start.Format("2006-01-02"), current.end.Format("2006-01-02"),
+		previous.start.Format("2006-01-02"), previous.end.Format("2006-01-02"),
+		filter.Type, filter.Top, preference.UpdatedAt.UnixNano())
+	return s.cached(scope.WorkspaceID, key, func() (interface{}, error) {
+		return s.Dashboard.periodComparison(ctx, scope, filter, current, previous)
+	})
+}
+
+// GetCategoryTrend di-cache per rentang bulanan, tipe dan daftar kategori (tanpa kategori = top kategori)
+func (s *CachedDashboardService) GetCategoryTrend(ctx context.Context, scope request.WorkspaceScope, filter request.CategoryTrendFilter) (*CachedDashboard, error) {
+	preference, r, err := s.resolveRange(ctx, scope, trendRangeFilter(filter), defaultChartPeriods)
+	if err != nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%s:%s:%v", rangeCacheKey(scope, "trend", r, preference), filter.Type, filter.CategoryIDs)
+	return s.cached(scope.WorkspaceID, key, func() (interface{}, error) {
+		return s.Dashboard.categoryTrend(ctx, scope, filter, r)
+	})
+}
+
+// GetSpendingHeatmap di-cache per tahun dan hari ini karena rata-rata hanya menghitung hari yang sudah lewat
+func (s *CachedDashboardService) GetSpendingHeatmap(ctx context.Context, scope request.WorkspaceScope, filter request.HeatmapFilter) (*CachedDashboard, error) {
+	preference, err := loadUserPreference(s.Dashboard.DB.WithContext(ctx), scope.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := utility.CalendarDate(time.Now().In(preference.Location()))
+	year := heatmapYear(filter, today)
+	key := fmt.Sprintf("%s%d:heatmap:%d:%s:%d", dashboardCachePrefix(scope.WorkspaceID), scope.UserID,
+		year, today.Format("2006-01-02"), preference.UpdatedAt.UnixNano())
+	return s.cached(scope.WorkspaceID, key, func() (interface{}, error) {
+		return s.Dashboard.spendingHeatmap(ctx, scope, preference, year, today)
+	})
+}
+
+func (s *CachedDashboardService) resolveRange(ctx context.Context, scope request.WorkspaceScope, filter request.DashboardFilter, defaultPeriods int) (entity.UserPreference, *dashboardRange, error) {
+	preference, err := loadUserPreference(s.Dashboard.DB.WithContext(ctx), scope.UserID)
+	if err != nil {
+		return preference, nil, err
+	}
+
+	r, err := parseDashboardRange(filter, preference, time.Now(), defaultPeriods)
+	return preference, r, err
+}
+
+func rangeCacheKey(scope request.WorkspaceScope, kind string, r *dashboardRange, preference entity.UserPreference) string {
+	return fmt.Sprintf("%s%d:%s:%s:%s:%s:%d", dashboardCachePrefix(scope.WorkspaceID), scope.UserID, kind,
+		r.start.Format("2006-01-02"), r.end.Format("2006-01-02"), r.granularity, preference.UpdatedAt.UnixNano())
+}
+
+// cached membaca key dari cache atau menghitung ulang. Jika workspace di-invalidate selagi compute berjalan,
+// hasilnya tetap dikembalikan tetapi tidak disimpan karena mungkin dihitung dari data sebelum perubahan.
+func (s *CachedDashboardService) cached(workspaceID uint, key string, compute func() (interface{}, error)) (*CachedDashboard, error) {
+	var generation uint64
+	if s.Cache != nil {
+		generation = s.generation(workspaceID)
+		if body, ok := s.Cache.Get(key); ok {
+			return newCachedDashboard(body), nil
+		}
+	}
+
+	data, err := compute()
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding dashboard: %v", err)
+	}
+
+	if s.Cache != nil {
+		s.mu.Lock()
+		if s.generations[workspaceID] == generation {
+			s.Cache.Set(key, body)
+		}
+		s.mu.Unlock()
+	}
+	return newCachedDashboard(body), nil
+}
+
+func newCachedDashboard(body []byte) *CachedDashboard {
+	sum := sha256.Sum256(body)
+	return &CachedDashboard{Body: body, ETag: `"` + hex.EncodeToString(sum[:16]) + `"`}
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
+)
+
+// status perbandingan kategori
+const (
+	ComparisonNew         = "new"
+	ComparisonDisappeared = "disappeared"
+	ComparisonChanged     = "changed"
+	ComparisonUnchanged   = "unchanged"
+)
+
+var ErrInvalidComparison = errors.New("invalid comparison, each period needs start_date <= end_date (YYYY-MM-DD) or month (YYYY-MM)")
+
+// comparisonPeriod adalah rentang [start, end) satu sisi perbandingan
+type comparisonPeriod struct {
+	start time.Time
+	end   time.Time
+	month string
+}
+
+func (p comparisonPeriod) toResponse(total float64) response.ComparisonPeriod {
+	return response.ComparisonPeriod{
+		StartDate: p.start.Format("2006-01-02"),
+		EndDate:   p.end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Month:     p.month,
+		Total:     total,
+	}
+}
+
+// GetPeriodComparison membandingkan total per kategori di dua periode
+func (s *DashboardService) GetPeriodComparison(ctx context.Context, scope request.WorkspaceScope, filter request.ComparisonFilter) (*response.RespPeriodComparison, error) {
+	preference, err := loadUserPreference(s.DB.WithContext(ctx), scope.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, previous, err := resolveComparison(filter, preference, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return s.periodComparison(ctx, scope, filter, current, previous)
+}
+
+// resolveComparison menentukan periode current dan previous dari filter
+func resolveComparison(filter request.ComparisonFilter, preference entity.UserPreference, now time.Time) (comparisonPeriod, comparisonPeriod, error) {
+	var current, previous comparisonPeriod
+
+	hasCurrent := filter.CurrentMonth != "" || filter.CurrentStartDate != "" || filter.CurrentEndDate != ""
+	hasPrevious := filter.PreviousMonth != "" || filter.PreviousStartDate != "" || filter.PreviousEndDate != ""
+	if !hasCurrent && hasPrevious {
+		return current, previous, ErrInvalidComparison
+	}
+
+	if !hasCurrent {
+		granularity := filter.Preset
+		if granularity == "" {
+			granularity = utility.GranularityMonth
+		}
+		config := periodConfig(preference)
+		today := utility.CalendarDate(now.In(preference.Location()))
+
+		start := utility.PeriodStart(today, granularity, config)
+		current = comparisonPeriod{start: start, end: utility.AddPeriods(start, granularity, 1, config)}
+		previous = comparisonPeriod{start: utility.AddPeriods(start, granularity, -1, config), end: start}
+		if granularity == utility.GranularityMonth {
+			current.month = utility.PeriodKey(current.start, granularity)
+			previous.month = utility.PeriodKey(previous.start, granularity)
+		}
+		return current, previous, nil
+	}
+
+	current, err := parseComparisonPeriod(filter.CurrentStartDate, filter.CurrentEndDate, filter.CurrentMonth, preference)
+	if err != nil {
+		return current, previous, err
+	}
+
+	switch {
+	case hasPrevious:
+		previous, err = parseComparisonPeriod(filter.PreviousStartDate, filter.PreviousEndDate, filter.PreviousMonth, preference)
+	case current.month != "":
+		// siklus bulanan sebelumnya, panjangnya bisa berbeda dengan siklus current
+		month, _ := time.Parse("2006-01", current.month)
+		previous, err = parseComparisonPeriod("", "", month.AddDate(0, -1, 0).Format("2006-01"), preference)
+	default:
+		// rentang dengan jumlah hari yang sama tepat sebelum current
+		days := int(current.end.Sub(current.start).Hours() / 24)
+		previous = comparisonPeriod{start: current.start.AddDate(0, 0, -days), end: current.start}
+	}
+	return current, previous, err
+}
+
+// parseComparisonPeriod mengikuti TransactionFilter: month menggantikan start_date dan end_date
+func parseComparisonPeriod(startDate, endDate, month string, preference entity.UserPreference) (comparisonPeriod, error) {
+	filter := request.TransactionFilter{StartDate: startDate, EndDate: endDate, Month: month}
+	if month != "" {
+		if err := applyCycleMonth(&filter, preference); err != nil {
+			return comparisonPeriod{}, ErrInvalidComparison
+		}
+	}
+
+	start, err := time.Parse("2006-01-02", filter.StartDate)
+	if err != nil {
+		return comparisonPeriod{}, ErrInvalidComparison
+	}
+	end, err := time.Parse("2006-01-02", filter.EndDate)
+	if err != nil || end.Before(start) {
+		return comparisonPeriod{}, ErrInvalidComparison
+	}
+	return comparisonPeriod{start: start, end: end.AddDate(0, 0, 1), month: month}, nil
+}
+
+func (s *DashboardService) periodComparison(ctx context.Context, scope request.WorkspaceScope, filter request.ComparisonFilter, current, previous comparisonPeriod) (*response.RespPeriodComparison, error) {
+	logrus.Info("Getting period comparison for workspace: ", scope.WorkspaceID)
+
+	txType := filter.Type
+	if txType == "" {
+		txType = "expense"
+	}
+	top := filter.Top
+	if top <= 0 {
+		top = 5
+	}
+
+	var currentTotals, previousTotals []utility.CategoryAmount
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.Go(func() error {
+		var err error
+		currentTotals, err = s.dashboardUtil.GetCategoryTotals(groupCtx, scope.WorkspaceID, txType, current.start, current.end)
+		return err
+	})
+	group.Go(func() error {
+		var err error
+		previousTotals, err = s.dashboardUtil.GetCategoryTotals(groupCtx, scope.WorkspaceID, txType, previous.start, previous.end)
+		return err
+	})
+	if err := group.Wait(); err != nil {
+		logrus.Errorf("Failed to get period comparison: %v", err)
+		return nil, fmt.Errorf("failed to get period comparison: %w", err)
+	}
+
+	categories := compareCategoryTotals(currentTotals, previousTotals)
+
+	var currentTotal, previousTotal float64
+	comparison := response.RespPeriodComparison{
+		Type:                  txType,
+		Categories:            categories,
+		NewCategories:         []response.CategoryComparison{},
+		DisappearedCategories: []response.CategoryComparison{},
+	}
+	for _, category := range categories {
+		currentTotal += category.Current
+		previousTotal += category.Previous
+		switch category.Status {
+		case ComparisonNew:
+			comparison.NewCategories = append(comparison.NewCategories, category)
+		case ComparisonDisappeared:
+			comparison.DisappearedCategories = append(comparison.DisappearedCategories, category)
+		}
+	}
+	comparison.Current = current.toResponse(currentTotal)
+	comparison.Previous = previous.toResponse(previousTotal)
+	comparison.Delta = currentTotal - previousTotal
+	comparison.DeltaPercent = deltaPercent(currentTotal, previousTotal)
+	comparison.BiggestMovers = biggestMovers(categories, top)
+
+	logrus.Info("Successfully retrieved period comparison")
+	return &comparison, nil
+}
+
+// compareCategoryTotals menggabungkan total kedua periode per kategori, urut dari total current terbesar
+func compareCategoryTotals(current, previous []utility.CategoryAmount) []response.CategoryComparison {
+	byCategory := map[uint]*response.CategoryComparison{}
+	get := func(total utility.CategoryAmount) *response.CategoryComparison {
+		if byCategory[total.CategoryID] == nil {
+			byCategory[total.CategoryID] = &response.CategoryComparison{CategoryID: total.CategoryID, Category: total.Category}
+		}
+		return byCategory[total.CategoryID]
+	}
+	for _, total := range current {
+		get(total).Current += total.Total
+	}
+	for _, total := range previous {
+		get(total).Previous += total.Total
+	}
+
+	categories := make([]response.CategoryComparison, 0, len(byCategory))
+	for _, category := range byCategory {
+		category.Delta = category.Current - category.Previous
+		category.DeltaPercent = deltaPercent(category.Current, category.Previous)
+		switch {
+		case category.Previous == 0 && category.Current != 0:
+			category.Status = ComparisonNew
+		case category.Current == 0 && category.Previous != 0:
+			category.Status = ComparisonDisappeared
+		case category.Delta == 0:
+			category.Status = ComparisonUnchanged
+		default:
+			category.Status = ComparisonChanged
+		}
+		categories = append(categories, *category)
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Current != categories[j].Current {
+			return categories[i].Current > categories[j].Current
+		}
+		if categories[i].Previous != categories[j].Previous {
+			return categories[i].Previous > categories[j].Previous
+		}
+		return categories[i].CategoryID < categories[j].CategoryID
+	})
+	return categories
+}
+
+// biggestMovers mengambil top kategori dengan perubahan absolut terbesar, kategori tanpa perubahan diabaikan
+func biggestMovers(categories []response.CategoryComparison, top int) []response.CategoryComparison {
+	movers := make([]response.CategoryComparison, 0, len(categories))
+	for _, category := range categories {
+		if category.Delta != 0 {
+			movers = append(movers, category)
+		}
+	}
+
+	sort.SliceStable(movers, func(i, j int) bool { return math.Abs(movers[i].Delta) > math.Abs(movers[j].Delta) })
+	if len(movers) > top {
+		movers = movers[:top]
+	}
+	return movers
+}
+
+// deltaPercent mengembalikan perubahan dalam persen dengan dua desimal, nil jika previous 0
+func deltaPercent(current, previous float64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	percent := math.Round((current-previous)/previous*10000) / 100
+	return &percent
+}
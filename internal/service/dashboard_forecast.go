@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// GetCashFlowForecast memproyeksikan saldo harian untuk filter.Days hari ke depan dari saldo hari ini,
+// transaksi bertanggal di masa depan, transaksi rutin bulanan yang terdeteksi dari riwayat dan rata-rata
+// pengeluaran variabel per kategori
+func (s *DashboardService) GetCashFlowForecast(ctx context.Context, scope request.WorkspaceScope, filter request.ForecastFilter) (*response.RespCashFlowForecast, error) {
+	preference, err := loadUserPreference(s.DB.WithContext(ctx), scope.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cashFlowForecast(ctx, scope, preference, forecastToday(preference, time.Now()), forecastDays(filter))
+}
+
+func forecastToday(preference entity.UserPreference, now time.Time) time.Time {
+	return utility.CalendarDate(now.In(preference.Location()))
+}
+
+func forecastDays(filter request.ForecastFilter) int {
+	if filter.Days <= 0 {
+		return 30
+	}
+	return filter.Days
+}
+
+func (s *DashboardService) cashFlowForecast(ctx context.Context, scope request.WorkspaceScope, preference entity.UserPreference, today time.Time, days int) (*response.RespCashFlowForecast, error) {
+	logrus.Info("Getting cash-flow forecast for workspace: ", scope.WorkspaceID)
+
+	tomorrow := today.AddDate(0, 0, 1)
+	totals, err := s.dashboardUtil.CalculateTotals(ctx, scope.WorkspaceID, today, tomorrow)
+	if err != nil {
+		logrus.Errorf("Failed to calculate current balance: %v", err)
+		return nil, fmt.Errorf("failed to get cash-flow forecast: %w", err)
+	}
+
+	// riwayat harus mencakup bulan penuh untuk deteksi transaksi rutin dan jendela rata-rata pengeluaran
+	historyStart := utility.MonthStart(today).AddDate(0, -utility.RecurringLookbackMonths, 0)
+	if windowStart := today.AddDate(0, 0, 1-utility.VariableSpendWindowDays); windowStart.Before(historyStart) {
+		historyStart = windowStart
+	}
+	transactions, err := s.dashboardUtil.GetForecastTransactions(ctx, scope.WorkspaceID, historyStart, tomorrow.AddDate(0, 0, days))
+	if err != nil {
+		logrus.Errorf("Failed to get forecast transactions: %v", err)
+		return nil, fmt.Errorf("failed to get cash-flow forecast: %w", err)
+	}
+
+	var scheduled []utility.ForecastTransaction
+	for _, tx := range transactions {
+		if tx.Date.After(today) {
+			scheduled = append(scheduled, tx)
+		}
+	}
+	recurring := utility.DetectRecurring(transactions, today)
+	variable := utility.VariableSpend(transactions, recurring, today)
+
+	var dailyVariable float64
+	forecast := response.RespCashFlowForecast{
+		StartDate:      tomorrow.Format("2006-01-02"),
+		EndDate:        today.AddDate(0, 0, days).Format("2006-01-02"),
+		CurrentBalance: totals.Balance,
+		RecurringItems: make([]response.ForecastRecurringItem, len(recurring)),
+		VariableSpend:  make([]response.ForecastCategorySpend, len(variable)),
+		Preferences:    toPreferenceResponse(preference),
+	}
+	for i, spend := range variable {
+		dailyVariable += spend.DailyAverage
+		forecast.VariableSpend[i] = response.ForecastCategorySpend{
+			CategoryID:   spend.CategoryID,
+			Category:     spend.Category,
+			DailyAverage: spend.DailyAverage,
+		}
+	}
+	for i, item := range recurring {
+		forecast.RecurringItems[i] = response.ForecastRecurringItem{
+			CategoryID: item.CategoryID,
+			Category:   item.Category,
+			Type:       item.Type,
+			Amount:     item.Amount,
+			DayOfMonth: item.DayOfMonth,
+		}
+	}
+	forecast.DailyVariableSpend = dailyVariable
+
+	projection := utility.ProjectCashFlow(today, days, totals.Balance, scheduled, recurring, dailyVariable)
+	forecast.Days = make([]response.ForecastDay, len(projection))
+	for i, day := range projection {
+		date := day.Date.Format("2006-01-02")
+		forecast.Days[i] = response.ForecastDay{Date: date, Income: day.Income, Expense: day.Expense, Balance: day.Balance}
+
+		if i == 0 || day.Balance < forecast.LowestBalance {
+			forecast.LowestBalance = day.Balance
+			forecast.LowestBalanceDate = date
+		}
+		if day.Balance < 0 && forecast.FirstNegativeDate == nil {
+			forecast.FirstNegativeDate = &date
+		}
+	}
+
+	logrus.Info("Successfully retrieved cash-flow forecast")
+	return &forecast, nil
+}
@@ -1,17 +1,29 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
 	"go-fintrack/internal/payload/response"
 	"go-fintrack/internal/utility"
-	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 )
 
+const (
+	// jumlah periode chart jika start_date tidak dikirim, default 6 bulan terakhir
+	defaultChartPeriods  = 6
+	maxDashboardPeriods  = 366
+	topExpenseCategories = 5
+)
+
+var ErrInvalidDashboardRange = errors.New("invalid dashboard range, use start_date <= end_date in format YYYY-MM-DD (max 366 periods)")
+
 type DashboardService struct {
 	DB            *gorm.DB
 	dashboardUtil *utility.DashboardUtil
@@ -24,114 +36,178 @@ func NewDashboardService(db *gorm.DB) *DashboardService {
 	}
 }
 
-func (s *DashboardService) GetFinancialOverview(userID uint) (*response.RespFinancialOverview, error) {
-	logrus.Info("Getting financial overview for user: ", userID)
+// dashboardRange adalah rentang tanggal kalender [start, end) beserta periode chart di dalamnya
+type dashboardRange struct {
+	start       time.Time
+	end         time.Time
+	granularity string
+	timezone    string
+	periods     []utility.Period
+}
 
-	var overview response.RespFinancialOverview
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	errChan := make(chan error, 4)
+// parseDashboardRange menentukan rentang dari filter. Tanpa tanggal, rentang berakhir di periode berjalan
+// (dihitung dari "hari ini" di timezone user) dan mencakup defaultPeriods periode.
+func parseDashboardRange(filter request.DashboardFilter, preference entity.UserPreference, now time.Time, defaultPeriods int) (*dashboardRange, error) {
+	granularity := filter.Granularity
+	if granularity == "" {
+		granularity = utility.GranularityMonth
+	}
 
-	// get current balance
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		balance, err := s.dashboardUtil.CalculateCurrentBalance(userID)
-		if err != nil {
-			logrus.Errorf("Failed to calculate current balance: %v", err)
-			errChan <- err
-			return
-		}
-		mu.Lock()
-		overview.CurrentBalance = balance
-		mu.Unlock()
-	}()
-
-	// get monthly income
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		startOfMonth := time.Now().UTC().Format("2006-01-01")
-		income, err := s.dashboardUtil.CalculateMonthlyIncome(userID, startOfMonth)
-		if err != nil {
-			logrus.Errorf("Failed to calculate monthly income: %v", err)
-			errChan <- err
-			return
-		}
-		mu.Lock()
-		overview.MonthlyIncome = income
-		mu.Unlock()
-	}()
-
-	// get monthly expense
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		startOfMonth := time.Now().UTC().Format("2006-01-01")
-		expense, err := s.dashboardUtil.CalculateMonthlyExpense(userID, startOfMonth)
+	today := utility.CalendarDate(now.In(preference.Location()))
+	config := periodConfig(preference)
+
+	var end time.Time
+	if filter.EndDate != "" {
+		parsed, err := time.Parse("2006-01-02", filter.EndDate)
 		if err != nil {
-			errChan <- err
-			return
+			return nil, ErrInvalidDashboardRange
 		}
-		mu.Lock()
-		overview.MonthlyExpense = expense
-		mu.Unlock()
-	}()
-
-	// get total savings
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		savings, err := s.dashboardUtil.CalculateTotalSavings(userID)
+		end = parsed.AddDate(0, 0, 1)
+	} else {
+		currentPeriod := utility.PeriodStart(today, granularity, config)
+		end = utility.AddPeriods(currentPeriod, granularity, 1, config)
+	}
+
+	var start time.Time
+	if filter.StartDate != "" {
+		parsed, err := time.Parse("2006-01-02", filter.StartDate)
 		if err != nil {
-			logrus.Errorf("Failed to calculate total savings: %v", err)
-			errChan <- err
-			return
+			return nil, ErrInvalidDashboardRange
 		}
-		mu.Lock()
-		overview.TotalSavings = savings
-		mu.Unlock()
-	}()
+		start = parsed
+	} else {
+		lastPeriod := utility.PeriodStart(end.AddDate(0, 0, -1), granularity, config)
+		start = utility.AddPeriods(lastPeriod, granularity, 1-defaultPeriods, config)
+	}
 
-	go func() {
-		wg.Wait()
-		close(errChan)
-	}()
+	if !start.Before(end) {
+		return nil, ErrInvalidDashboardRange
+	}
 
-	for err := range errChan {
-		if err != nil {
-			return nil, errors.New("failed to get financial overview")
-		}
+	periods := utility.BuildPeriods(start, end, granularity, config)
+	if len(periods) > maxDashboardPeriods {
+		return nil, ErrInvalidDashboardRange
+	}
+
+	return &dashboardRange{
+		start:       start,
+		end:         end,
+		granularity: granularity,
+		timezone:    preference.Timezone,
+		periods:     periods,
+	}, nil
+}
+
+// periodConfig mengambil preferensi yang menentukan batas minggu dan siklus bulanan
+func periodConfig(preference entity.UserPreference) utility.PeriodConfig {
+	return utility.PeriodConfig{
+		FirstDayOfWeek: preference.FirstDayOfWeek,
+		CycleStartDay:  preference.CycleStartDay,
+		Locale:         preference.Locale,
+	}
+}
+
+func (r *dashboardRange) toResponse() response.DashboardRange {
+	return response.DashboardRange{
+		StartDate:   r.start.Format("2006-01-02"),
+		EndDate:     r.end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Granularity: r.granularity,
+		Timezone:    r.timezone,
+	}
+}
+
+// GetFinancialOverview menghitung saldo, income dan expense dengan satu query agregasi
+func (s *DashboardService) GetFinancialOverview(ctx context.Context, scope request.WorkspaceScope, filter request.DashboardFilter) (*response.RespFinancialOverview, error) {
+	logrus.Info("Getting financial overview for workspace: ", scope.WorkspaceID)
+
+	preference, err := loadUserPreference(s.DB.WithContext(ctx), scope.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// overview default hanya periode berjalan, mis. bulan ini atau minggu ini
+	r, err := parseDashboardRange(filter, preference, time.Now(), 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.financialOverview(ctx, scope, preference, r)
+}
+
+func (s *DashboardService) financialOverview(ctx context.Context, scope request.WorkspaceScope, preference entity.UserPreference, r *dashboardRange) (*response.RespFinancialOverview, error) {
+	totals, err := s.dashboardUtil.CalculateTotals(ctx, scope.WorkspaceID, r.start, r.end)
+	if err != nil {
+		logrus.Errorf("Failed to calculate financial overview: %v", err)
+		return nil, fmt.Errorf("failed to get financial overview: %w", err)
 	}
 
 	logrus.Info("Successfully retrieved financial overview")
-	return &overview, nil
+	return &response.RespFinancialOverview{
+		CurrentBalance: totals.Balance,
+		MonthlyIncome:  totals.Income,
+		MonthlyExpense: totals.Expense,
+		// savings adalah selisih income dan expense di rentang yang sama
+		TotalSavings: totals.Income - totals.Expense,
+		Range:        r.toResponse(),
+		Preferences:  toPreferenceResponse(preference),
+	}, nil
 }
 
-func (s *DashboardService) GetDashboardCharts(userID uint) (*response.RespDashboardCharts, error) {
-	logrus.Info("Getting dashboard charts for user: ", userID)
+// GetDashboardCharts menjalankan query series dan query kategori secara paralel. Error pertama membatalkan
+// query lainnya lewat context, begitu juga jika request dibatalkan client.
+func (s *DashboardService) GetDashboardCharts(ctx context.Context, scope request.WorkspaceScope, filter request.DashboardFilter) (*response.RespDashboardCharts, error) {
+	logrus.Info("Getting dashboard charts for workspace: ", scope.WorkspaceID)
+
+	preference, err := loadUserPreference(s.DB.WithContext(ctx), scope.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := parseDashboardRange(filter, preference, time.Now(), defaultChartPeriods)
+	if err != nil {
+		return nil, err
+	}
 
-	var charts response.RespDashboardCharts
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	errChan := make(chan error, 3)
+	return s.dashboardCharts(ctx, scope, preference, r)
+}
 
-	defer close(errChan)
+func (s *DashboardService) dashboardCharts(ctx context.Context, scope request.WorkspaceScope, preference entity.UserPreference, r *dashboardRange) (*response.RespDashboardCharts, error) {
+	var incomeData, expenseData []float64
+	var categoryTotals []utility.CategoryTotal
 
-	// get income vs expense
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		labels, incomeData, expenseData, err := s.dashboardUtil.GetLastSixMonthsData(userID)
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.Go(func() error {
+		var err error
+		incomeData, expenseData, err = s.dashboardUtil.GetIncomeExpenseSeries(groupCtx, scope.WorkspaceID, r.periods)
+		if err != nil {
+			return fmt.Errorf("income vs expense: %w", err)
+		}
+		return nil
+	})
+	group.Go(func() error {
+		var err error
+		categoryTotals, err = s.dashboardUtil.GetExpenseByCategory(groupCtx, scope.WorkspaceID, r.start, r.end)
 		if err != nil {
-			logrus.Errorf("Failed to get income vs expense data: %v", err)
-			errChan <- err
-			return
+			return fmt.Errorf("expense by category: %w", err)
 		}
+		return nil
+	})
+	if err := group.Wait(); err != nil {
+		logrus.Errorf("Failed to get dashboard charts: %v", err)
+		return nil, fmt.Errorf("failed to get dashboard charts: %w", err)
+	}
+
+	labels := make([]string, len(r.periods))
+	keys := make([]string, len(r.periods))
+	for i, period := range r.periods {
+		labels[i] = period.Label
+		keys[i] = period.Key
+	}
 
-		mu.Lock()
-		charts.IncomeVsExpense = response.RespIncomeVsExpense{
+	charts := response.RespDashboardCharts{
+		IncomeVsExpense: response.RespIncomeVsExpense{
 			Labels: labels,
+			Keys:   keys,
 			Datasets: []response.ChartDataset{
 				{
 					Label:           "Income",
@@ -146,75 +222,39 @@ func (s *DashboardService) GetDashboardCharts(userID uint) (*response.RespDashbo
 					BackgroundColor: "rgba(239, 68, 68, 0.1)",
 				},
 			},
-		}
-		mu.Unlock()
-	}()
-
-	// get category distribution
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		labels, data, err := s.dashboardUtil.GetCategoryDistribution(userID)
-		if err != nil {
-			logrus.Errorf("Failed to get category distribution data: %v", err)
-			errChan <- err
-			return
-		}
+		},
+		Range:       r.toResponse(),
+		Preferences: toPreferenceResponse(preference),
+	}
 
-		mu.Lock()
-		charts.CategoryDistribution = response.CategoryDistribution{
-			Labels: labels,
-			Datasets: []struct {
-				Data            []float64 `json:"data"`
-				BackgroundColor []string  `json:"background_color"`
-			}{
-				{
-					Data: data,
-					BackgroundColor: []string{
-						"#10B981", "#3B82F6", "#F59E0B",
-						"#6366F1", "#EC4899", "#8B5CF6",
-					},
-				},
+	// distribusi dan top expense memakai hasil query kategori yang sama
+	// warna mengikuti Color setiap kategori
+	distributionLabels, distributionData, distributionColors := utility.SplitCategoryTotals(categoryTotals, 0)
+	charts.CategoryDistribution = response.CategoryDistribution{
+		Labels: distributionLabels,
+		Datasets: []struct {
+			Data            []float64 `json:"data"`
+			BackgroundColor []string  `json:"background_color"`
+		}{
+			{
+				Data:            distributionData,
+				BackgroundColor: distributionColors,
 			},
-		}
-		mu.Unlock()
-	}()
-
-	// get top expenses
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		labels, data, err := s.dashboardUtil.GetTopExpenseCategories(userID, 5)
-		if err != nil {
-			logrus.Errorf("Failed to get top expenses data: %v", err)
-			errChan <- err
-			return
-		}
+		},
+	}
 
-		mu.Lock()
-		charts.TopExpenses = response.TopExpenses{
-			Labels: labels,
-			Datasets: []struct {
-				Data            []float64 `json:"data"`
-				BackgroundColor string    `json:"background_color"`
-			}{
-				{
-					Data:            data,
-					BackgroundColor: "#3B82F6",
-				},
+	topLabels, topData, topColors := utility.SplitCategoryTotals(categoryTotals, topExpenseCategories)
+	charts.TopExpenses = response.TopExpenses{
+		Labels: topLabels,
+		Datasets: []struct {
+			Data            []float64 `json:"data"`
+			BackgroundColor []string  `json:"background_color"`
+		}{
+			{
+				Data:            topData,
+				BackgroundColor: topColors,
 			},
-		}
-		mu.Unlock()
-	}()
-
-	wg.Wait()
-
-	select {
-	case err := <-errChan:
-		if err != nil {
-			return nil, fmt.Errorf("failed to get dashboard charts: %v", err)
-		}
-	default:
+		},
 	}
 
 	logrus.Info("Successfully retrieved dashboard charts")
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// jumlah kategori tren jika category_ids tidak dikirim
+const defaultTrendCategories = 5
+
+var ErrInvalidTrendCategories = errors.New("invalid category_ids, every category must belong to the workspace")
+
+// GetCategoryTrend menghitung tren bulanan per kategori, satu dataset per kategori dengan warna kategori
+func (s *DashboardService) GetCategoryTrend(ctx context.Context, scope request.WorkspaceScope, filter request.CategoryTrendFilter) (*response.RespCategoryTrend, error) {
+	preference, err := loadUserPreference(s.DB.WithContext(ctx), scope.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := parseDashboardRange(trendRangeFilter(filter), preference, time.Now(), defaultChartPeriods)
+	if err != nil {
+		return nil, err
+	}
+	return s.categoryTrend(ctx, scope, filter, r)
+}
+
+// trendRangeFilter memakai rentang dashboard dengan granularity bulanan (siklus bulanan user)
+func trendRangeFilter(filter request.CategoryTrendFilter) request.DashboardFilter {
+	return request.DashboardFilter{
+		StartDate:   filter.StartDate,
+		EndDate:     filter.EndDate,
+		Granularity: utility.GranularityMonth,
+	}
+}
+
+func (s *DashboardService) categoryTrend(ctx context.Context, scope request.WorkspaceScope, filter request.CategoryTrendFilter, r *dashboardRange) (*response.RespCategoryTrend, error) {
+	txType := filter.Type
+	if txType == "" {
+		txType = "expense"
+	}
+
+	categoryIDs, err := s.trendCategoryIDs(ctx, scope.WorkspaceID, txType, filter.CategoryIDs, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []entity.Category
+	if len(categoryIDs) > 0 {
+		if err := s.DB.WithContext(ctx).Where("workspace_id = ? AND id IN ?", scope.WorkspaceID, categoryIDs).Find(&categories).Error; err != nil {
+			return nil, fmt.Errorf("failed to load trend categories: %w", err)
+		}
+	}
+	if len(filter.CategoryIDs) > 0 && len(categories) != len(categoryIDs) {
+		return nil, ErrInvalidTrendCategories
+	}
+
+	series, err := s.dashboardUtil.GetCategorySeries(ctx, scope.WorkspaceID, txType, categoryIDs, r.periods)
+	if err != nil {
+		logrus.Errorf("Failed to get category trend: %v", err)
+		return nil, fmt.Errorf("failed to get category trend: %w", err)
+	}
+
+	byID := make(map[uint]entity.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+
+	trend := &response.RespCategoryTrend{
+		Type:     txType,
+		Labels:   make([]string, len(r.periods)),
+		Keys:     make([]string, len(r.periods)),
+		Datasets: []response.CategoryTrendDataset{},
+		Range:    r.toResponse(),
+	}
+	for i, period := range r.periods {
+		trend.Labels[i] = period.Label
+		trend.Keys[i] = period.Key
+	}
+
+	// urutan dataset mengikuti category_ids, atau total terbesar jika dipilih otomatis
+	for _, categoryID := range categoryIDs {
+		category, ok := byID[categoryID]
+		if !ok {
+			continue
+		}
+		color := utility.CategoryChartColor(category.Color, len(trend.Datasets))
+		trend.Datasets = append(trend.Datasets, response.CategoryTrendDataset{
+			CategoryID: category.ID,
+			ChartDataset: response.ChartDataset{
+				Label:           category.Name,
+				Data:            series[categoryID],
+				BorderColor:     color,
+				BackgroundColor: color,
+			},
+		})
+	}
+	return trend, nil
+}
+
+// trendCategoryIDs membuang duplikat category_ids, tanpa category_ids diambil kategori dengan total terbesar
+func (s *DashboardService) trendCategoryIDs(ctx context.Context, workspaceID uint, txType string, requested []uint, r *dashboardRange) ([]uint, error) {
+	if len(requested) > 0 {
+		seen := make(map[uint]bool, len(requested))
+		ids := make([]uint, 0, len(requested))
+		for _, id := range requested {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	totals, err := s.dashboardUtil.GetCategoryTotals(ctx, workspaceID, txType, r.start, r.end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trend categories: %w", err)
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Total != totals[j].Total {
+			return totals[i].Total > totals[j].Total
+		}
+		return totals[i].CategoryID < totals[j].CategoryID
+	})
+
+	ids := make([]uint, 0, defaultTrendCategories)
+	for _, total := range totals {
+		// kategori terhapus tidak punya nama dan warna untuk ditampilkan
+		if total.Category == "" {
+			continue
+		}
+		ids = append(ids, total.CategoryID)
+		if len(ids) == defaultTrendCategories {
+			break
+		}
+	}
+	return ids, nil
+}
+
+// GetSpendingHeatmap menghitung expense harian satu tahun beserta pola per hari dalam seminggu
+func (s *DashboardService) GetSpendingHeatmap(ctx context.Context, scope request.WorkspaceScope, filter request.HeatmapFilter) (*response.RespSpendingHeatmap, error) {
+	preference, err := loadUserPreference(s.DB.WithContext(ctx), scope.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	today := utility.CalendarDate(time.Now().In(preference.Location()))
+	return s.spendingHeatmap(ctx, scope, preference, heatmapYear(filter, today), today)
+}
+
+func heatmapYear(filter request.HeatmapFilter, today time.Time) int {
+	if filter.Year == 0 {
+		return today.Year()
+	}
+	return filter.Year
+}
+
+// spendingHeatmap membagi rata-rata dengan jumlah hari yang sudah lewat sampai today, hari mendatang tetap
+// muncul di days dengan total 0 agar kalender lengkap
+func (s *DashboardService) spendingHeatmap(ctx context.Context, scope request.WorkspaceScope, preference entity.UserPreference, year int, today time.Time) (*response.RespSpendingHeatmap, error) {
+	start := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(1, 0, 0)
+
+	totals, err := s.dashboardUtil.GetDailyExpenses(ctx, scope.WorkspaceID, start, end)
+	if err != nil {
+		logrus.Errorf("Failed to get spending heatmap: %v", err)
+		return nil, fmt.Errorf("failed to get spending heatmap: %w", err)
+	}
+
+	byDate := make(map[string]utility.DailyTotal, len(totals))
+	for _, total := range totals {
+		byDate[utility.CalendarDate(total.Date).Format("2006-01-02")] = total
+	}
+
+	heatmap := &response.RespSpendingHeatmap{
+		Year:        year,
+		Days:        make([]response.HeatmapDay, 0, 366),
+		Preferences: toPreferenceResponse(preference),
+	}
+
+	var weekdayTotals [7]float64
+	var weekdayDays [7]int
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		key := day.Format("2006-01-02")
+		total := byDate[key]
+		heatmap.Days = append(heatmap.Days, response.HeatmapDay{Date: key, Total: total.Total, Count: total.Count})
+
+		heatmap.Total += total.Total
+		if total.Total > heatmap.MaxDaily {
+			heatmap.MaxDaily = total.Total
+		}
+
+		weekdayTotals[day.Weekday()] += total.Total
+		if !day.After(today) {
+			weekdayDays[day.Weekday()]++
+		}
+	}
+
+	firstDay := preference.FirstDayOfWeek
+	heatmap.DayOfWeek = make([]response.DayOfWeekSpend, 7)
+	for i := range heatmap.DayOfWeek {
+		weekday := time.Weekday((firstDay + i) % 7)
+		heatmap.DayOfWeek[i] = response.DayOfWeekSpend{
+			Day:     int(weekday),
+			Label:   utility.WeekdayLabel(weekday, preference.Locale),
+			Total:   weekdayTotals[weekday],
+			Average: dailyAverage(weekdayTotals[weekday], weekdayDays[weekday]),
+		}
+
+		breakdown := &heatmap.Weekday
+		if weekday == time.Saturday || weekday == time.Sunday {
+			breakdown = &heatmap.Weekend
+		}
+		breakdown.Total += weekdayTotals[weekday]
+		breakdown.Days += weekdayDays[weekday]
+	}
+	heatmap.Weekday.DailyAverage = dailyAverage(heatmap.Weekday.Total, heatmap.Weekday.Days)
+	heatmap.Weekend.DailyAverage = dailyAverage(heatmap.Weekend.Total, heatmap.Weekend.Days)
+
+	return heatmap, nil
+}
+
+func dailyAverage(total float64, days int) float64 {
+	if days == 0 {
+		return 0
+	}
+	return total / float64(days)
+}
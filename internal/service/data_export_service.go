@@ -0,0 +1,377 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	dataExportTTL = 7 * 24 * time.Hour
+	// job processing yang tidak selesai dalam waktu ini dianggap gagal di tengah jalan (mis. proses mati) dan diulang
+	dataExportStaleAfter = 30 * time.Minute
+)
+
+var (
+	ErrDataExportInProgress  = errors.New("a data export is already in progress")
+	ErrDataExportNotFound    = errors.New("data export not found")
+	ErrDataExportUnavailable = errors.New("data export is not ready or has expired")
+)
+
+// exportDataset adalah satu jenis data di dalam ZIP, ditulis sebagai <name>.json dan <name>.csv
+type exportDataset struct {
+	Name    string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// RequestExport membuat job export baru, hanya satu job aktif per user
+func (s *AccountService) RequestExport(userID uint) (*response.DataExportResponse, error) {
+	var active int64
+	if err := s.DB.Model(&entity.DataExport{}).
+		Where("user_id = ? AND status IN ?", userID, []string{entity.DataExportPending, entity.DataExportProcessing}).
+		Count(&active).Error; err != nil {
+		return nil, fmt.Errorf("error checking data exports: %v", err)
+	}
+	if active > 0 {
+		return nil, ErrDataExportInProgress
+	}
+
+	export := entity.DataExport{UserID: userID, Status: entity.DataExportPending}
+	if err := s.DB.Create(&export).Error; err != nil {
+		return nil, fmt.Errorf("error creating data export: %v", err)
+	}
+
+	s.notifyWorker()
+
+	result := toDataExportResponse(export)
+	return &result, nil
+}
+
+func (s *AccountService) ListExports(userID uint) ([]response.DataExportResponse, error) {
+	var exports []entity.DataExport
+	if err := s.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&exports).Error; err != nil {
+		return nil, fmt.Errorf("error getting data exports: %v", err)
+	}
+
+	result := make([]response.DataExportResponse, len(exports))
+	for i, export := range exports {
+		result[i] = toDataExportResponse(export)
+	}
+	return result, nil
+}
+
+// GetExportFile mengembalikan path ZIP milik user yang siap diunduh
+func (s *AccountService) GetExportFile(userID, exportID uint) (string, error) {
+	var export entity.DataExport
+	if err := s.DB.Where("id = ? AND user_id = ?", exportID, userID).First(&export).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrDataExportNotFound
+		}
+		return "", fmt.Errorf("error getting data export: %v", err)
+	}
+
+	if !export.IsDownloadable(time.Now()) {
+		return "", ErrDataExportUnavailable
+	}
+	return export.FilePath, nil
+}
+
+func (s *AccountService) processPendingExports() error {
+	claimable := "status = ? OR (status = ? AND updated_at < ?)"
+	staleBefore := time.Now().Add(-dataExportStaleAfter)
+
+	var exports []entity.DataExport
+	if err := s.DB.Where(claimable, entity.DataExportPending, entity.DataExportProcessing, staleBefore).
+		Order("created_at ASC").
+		Find(&exports).Error; err != nil {
+		return fmt.Errorf("error getting pending data exports: %v", err)
+	}
+
+	for _, export := range exports {
+		// klaim job, instance lain yang sudah mengklaim membuat RowsAffected = 0
+		claim := s.DB.Model(&entity.DataExport{}).
+			Where("id = ?", export.ID).
+			Where(claimable, entity.DataExportPending, entity.DataExportProcessing, staleBefore).
+			Update("status", entity.DataExportProcessing)
+		if claim.Error != nil {
+			return fmt.Errorf("error claiming data export: %v", claim.Error)
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+
+		s.completeExport(&export)
+	}
+	return nil
+}
+
+func (s *AccountService) completeExport(export *entity.DataExport) {
+	path, size, err := s.buildExport(export)
+	if err != nil {
+		logrus.Errorf("Failed to build data export %d: %v", export.ID, err)
+		if err := s.DB.Model(export).Updates(map[string]interface{}{
+			"status": entity.DataExportFailed,
+			"error":  "failed to build export, please request a new one",
+		}).Error; err != nil {
+			logrus.Errorf("Failed to mark data export %d as failed: %v", export.ID, err)
+		}
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(dataExportTTL)
+	if err := s.DB.Model(export).Updates(map[string]interface{}{
+		"status":       entity.DataExportCompleted,
+		"file_path":    path,
+		"file_size":    size,
+		"completed_at": now,
+		"expires_at":   expiresAt,
+	}).Error; err != nil {
+		logrus.Errorf("Failed to complete data export %d: %v", export.ID, err)
+		removeFile(path)
+		return
+	}
+
+	var user entity.User
+	if err := s.DB.Select("id", "name", "email").First(&user, export.UserID).Error; err == nil {
+		s.sendEmail(user.Email, "Your data export is ready", fmt.Sprintf("Hi %s,\n\n"+
+			"The export of your FinTrack data is ready. Download it from your account settings before %s:\n\n"+
+			"%s/settings/account\n",
+			user.Name, expiresAt.Format("2006-01-02 15:04 MST"), s.AppURL))
+	}
+}
+
+// buildExport menulis ZIP berisi JSON dan CSV untuk setiap dataset milik user
+func (s *AccountService) buildExport(export *entity.DataExport) (string, int64, error) {
+	datasets, err := s.collectExportData(export.UserID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	buffer := new(bytes.Buffer)
+	zipWriter := zip.NewWriter(buffer)
+	for _, dataset := range datasets {
+		if err := writeExportDataset(zipWriter, dataset); err != nil {
+			return "", 0, err
+		}
+	}
+	if err := zipWriter.Close(); err != nil {
+		return "", 0, fmt.Errorf("error closing zip: %v", err)
+	}
+
+	if err := os.MkdirAll(s.ExportDir, 0o700); err != nil {
+		return "", 0, fmt.Errorf("error creating export directory: %v", err)
+	}
+	path := filepath.Join(s.ExportDir, fmt.Sprintf("fintrack-export-%d-%d-%s.zip", export.UserID, export.ID, utility.GenerateRandomString(12)))
+	if err := os.WriteFile(path, buffer.Bytes(), 0o600); err != nil {
+		return "", 0, fmt.Errorf("error writing export file: %v", err)
+	}
+
+	return path, int64(buffer.Len()), nil
+}
+
+func (s *AccountService) collectExportData(userID uint) ([]exportDataset, error) {
+	var user entity.User
+	if err := s.DB.First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("error getting user: %v", err)
+	}
+
+	preference, err := loadUserPreference(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		identities     []entity.UserIdentity
+		memberships    []entity.WorkspaceMember
+		categories     []entity.Category
+		transactions   []entity.Transaction
+		sessions       []entity.Session
+		apiTokens      []entity.APIToken
+		securityEvents []entity.SecurityEvent
+		activities     []entity.UserActivity
+		chatUsages     []entity.ChatUsage
+	)
+	queries := []struct {
+		name  string
+		query *gorm.DB
+		dest  interface{}
+	}{
+		{"identities", s.DB.Where("user_id = ?", userID), &identities},
+		{"workspaces", s.DB.Preload("Workspace").Where("user_id = ?", userID), &memberships},
+		{"categories", s.DB.Where("user_id = ?", userID), &categories},
+		{"transactions", s.DB.Preload("Category").Where("user_id = ?", userID).Order("date ASC"), &transactions},
+		{"sessions", s.DB.Where("user_id = ?", userID), &sessions},
+		{"api tokens", s.DB.Where("user_id = ?", userID), &apiTokens},
+		{"security events", s.DB.Where("user_id = ?", userID), &securityEvents},
+		{"activities", s.DB.Where("user_id = ?", userID).Order("activity_date ASC"), &activities},
+		{"chat usages", s.DB.Where("user_id = ?", userID), &chatUsages},
+	}
+	for _, q := range queries {
+		if err := q.query.Find(q.dest).Error; err != nil {
+			return nil, fmt.Errorf("error getting %s: %v", q.name, err)
+		}
+	}
+
+	datasets := []exportDataset{
+		{
+			Name:    "profile",
+			Columns: []string{"id", "name", "username", "email", "email_verified", "provider", "profile_pic", "role", "two_factor_enabled", "created_at", "last_login_at"},
+			Rows: [][]interface{}{{
+				user.ID, user.Name, user.Username, user.Email, user.EmailVerified, user.Provider, user.ProfilePic,
+				user.Role, user.TwoFactorEnabled, user.CreatedAt, user.LastLoginAt,
+			}},
+		},
+		{
+			Name:    "preferences",
+			Columns: []string{"base_currency", "timezone", "locale", "first_day_of_week", "number_format", "cycle_start_day"},
+			Rows: [][]interface{}{{
+				preference.BaseCurrency, preference.Timezone, preference.Locale, preference.FirstDayOfWeek, preference.NumberFormat, preference.CycleStartDay,
+			}},
+		},
+		{Name: "identities", Columns: []string{"id", "provider", "email", "linked_at", "last_login_at"}},
+		{Name: "workspaces", Columns: []string{"workspace_id", "name", "role", "is_personal", "joined_at"}},
+		{Name: "categories", Columns: []string{"id", "workspace_id", "name", "color", "icon_color", "created_at"}},
+		{Name: "transactions", Columns: []string{"id", "workspace_id", "date", "type", "category", "amount", "description", "created_at"}},
+		{Name: "sessions", Columns: []string{"id", "user_agent", "ip_address", "created_at", "last_used_at", "expires_at", "revoked_at"}},
+		{Name: "api_tokens", Columns: []string{"id", "name", "token_prefix", "scopes", "created_at", "last_used_at", "expires_at"}},
+		{Name: "security_events", Columns: []string{"id", "type", "ip_address", "user_agent", "details", "created_at"}},
+		{Name: "activity", Columns: []string{"activity_date"}},
+		{Name: "chat_usage", Columns: []string{"created_at"}},
+	}
+
+	for _, identity := range identities {
+		datasets[2].Rows = append(datasets[2].Rows, []interface{}{identity.ID, identity.Provider, identity.Email, identity.CreatedAt, identity.LastLoginAt})
+	}
+	for _, membership := range memberships {
+		datasets[3].Rows = append(datasets[3].Rows, []interface{}{membership.WorkspaceID, membership.Workspace.Name, membership.Role, membership.Workspace.IsPersonal, membership.CreatedAt})
+	}
+	for _, category := range categories {
+		datasets[4].Rows = append(datasets[4].Rows, []interface{}{category.ID, category.WorkspaceID, category.Name, category.Color, category.IconColor, category.CreatedAt})
+	}
+	for _, transaction := range transactions {
+		datasets[5].Rows = append(datasets[5].Rows, []interface{}{
+			transaction.ID, transaction.WorkspaceID, transaction.Date.Format("2006-01-02"), transaction.Type,
+			transaction.Category.Name, transaction.Amount, transaction.Description, transaction.CreatedAt,
+		})
+	}
+	for _, session := range sessions {
+		datasets[6].Rows = append(datasets[6].Rows, []interface{}{session.ID, session.UserAgent, session.IPAddress, session.CreatedAt, session.LastUsedAt, session.ExpiresAt, session.RevokedAt})
+	}
+	for _, token := range apiTokens {
+		datasets[7].Rows = append(datasets[7].Rows, []interface{}{token.ID, token.Name, token.TokenPrefix, token.Scopes, token.CreatedAt, token.LastUsedAt, token.ExpiresAt})
+	}
+	for _, event := range securityEvents {
+		datasets[8].Rows = append(datasets[8].Rows, []interface{}{event.ID, event.Type, event.IPAddress, event.UserAgent, event.Details, event.CreatedAt})
+	}
+	for _, activity := range activities {
+		datasets[9].Rows = append(datasets[9].Rows, []interface{}{activity.ActivityDate.Format("2006-01-02")})
+	}
+	for _, usage := range chatUsages {
+		datasets[10].Rows = append(datasets[10].Rows, []interface{}{usage.CreatedAt})
+	}
+
+	return datasets, nil
+}
+
+func writeExportDataset(zipWriter *zip.Writer, dataset exportDataset) error {
+	records := make([]map[string]interface{}, len(dataset.Rows))
+	for i, row := range dataset.Rows {
+		record := make(map[string]interface{}, len(dataset.Columns))
+		for j, column := range dataset.Columns {
+			record[column] = row[j]
+		}
+		records[i] = record
+	}
+
+	jsonFile, err := zipWriter.Create(dataset.Name + ".json")
+	if err != nil {
+		return fmt.Errorf("error creating %s.json: %v", dataset.Name, err)
+	}
+	encoder := json.NewEncoder(jsonFile)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(records); err != nil {
+		return fmt.Errorf("error writing %s.json: %v", dataset.Name, err)
+	}
+
+	csvFile, err := zipWriter.Create(dataset.Name + ".csv")
+	if err != nil {
+		return fmt.Errorf("error creating %s.csv: %v", dataset.Name, err)
+	}
+	csvWriter := csv.NewWriter(csvFile)
+	if err := csvWriter.Write(dataset.Columns); err != nil {
+		return fmt.Errorf("error writing %s.csv: %v", dataset.Name, err)
+	}
+	for _, row := range dataset.Rows {
+		values := make([]string, len(row))
+		for i, value := range row {
+			values[i] = formatCSVValue(value)
+		}
+		if err := csvWriter.Write(values); err != nil {
+			return fmt.Errorf("error writing %s.csv: %v", dataset.Name, err)
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+func formatCSVValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// removeExpiredExports menghapus file ZIP yang sudah melewati masa berlaku, baris job tetap disimpan sebagai riwayat
+func (s *AccountService) removeExpiredExports() error {
+	var exports []entity.DataExport
+	if err := s.DB.Where("status = ? AND expires_at < ? AND file_path <> ''", entity.DataExportCompleted, time.Now()).
+		Find(&exports).Error; err != nil {
+		return fmt.Errorf("error getting expired data exports: %v", err)
+	}
+
+	for _, export := range exports {
+		removeFile(export.FilePath)
+		if err := s.DB.Model(&export).Update("file_path", "").Error; err != nil {
+			return fmt.Errorf("error updating expired data export: %v", err)
+		}
+	}
+	return nil
+}
+
+func toDataExportResponse(export entity.DataExport) response.DataExportResponse {
+	return response.DataExportResponse{
+		ID:          export.ID,
+		Status:      export.Status,
+		FileSize:    export.FileSize,
+		Error:       export.Error,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/utility"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const emailVerificationTTL = 48 * time.Hour
+
+var (
+	ErrInvalidVerificationToken = errors.New("verification token is invalid or has expired")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrEmailNotVerified         = errors.New("please verify your email address to use this feature")
+)
+
+// SendVerificationEmail membuat token verifikasi baru dan mengirimkannya ke email user
+func (s *UserService) SendVerificationEmail(user *entity.User) error {
+	token := utility.GenerateSecureToken()
+	verification := entity.EmailVerification{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: utility.HashToken(token),
+		ExpiresAt: time.Now().Add(emailVerificationTTL),
+	}
+
+	if err := s.DB.Create(&verification).Error; err != nil {
+		return fmt.Errorf("error creating email verification: %v", err)
+	}
+
+	link := fmt.Sprintf("%s/verify-email?token=%s", s.AppURL, url.QueryEscape(token))
+	body := fmt.Sprintf("Hi %s,\n\n"+
+		"Please confirm your email address by opening the link below:\n\n"+
+		"%s\n\n"+
+		"This link expires in %d hours.\n",
+		user.Name, link, int(emailVerificationTTL.Hours()))
+
+	if err := s.EmailSender.Send(utility.EmailMessage{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body:    body,
+	}); err != nil {
+		return fmt.Errorf("error sending verification email: %v", err)
+	}
+
+	return nil
+}
+
+func (s *UserService) ResendVerificationEmail(userID uint) error {
+	var user entity.User
+	if err := s.DB.First(&user, userID).Error; err != nil {
+		return fmt.Errorf("error getting user: %v", err)
+	}
+
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	return s.SendVerificationEmail(&user)
+}
+
+func (s *UserService) VerifyEmail(token string) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var verification entity.EmailVerification
+		if err := tx.Where("token_hash = ?", utility.HashToken(token)).First(&verification).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidVerificationToken
+			}
+			return fmt.Errorf("error getting email verification: %v", err)
+		}
+
+		now := time.Now()
+		if verification.UsedAt != nil || now.After(verification.ExpiresAt) {
+			return ErrInvalidVerificationToken
+		}
+
+		// email user sudah berubah sejak token dibuat
+		result := tx.Model(&entity.User{}).
+			Where("id = ? AND email = ?", verification.UserID, verification.Email).
+			Updates(map[string]interface{}{"email_verified": true, "email_verified_at": now})
+		if result.Error != nil {
+			return fmt.Errorf("error verifying email: %v", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidVerificationToken
+		}
+
+		if err := tx.Model(&entity.EmailVerification{}).
+			Where("user_id = ? AND used_at IS NULL", verification.UserID).
+			Update("used_at", now).Error; err != nil {
+			return fmt.Errorf("error invalidating verification tokens: %v", err)
+		}
+
+		return nil
+	})
+}
+
+func (s *UserService) IsEmailVerified(userID uint) (bool, error) {
+	var user entity.User
+	if err := s.DB.Select("id", "email_verified").First(&user, userID).Error; err != nil {
+		return false, fmt.Errorf("error getting user: %v", err)
+	}
+
+	return user.EmailVerified, nil
+}
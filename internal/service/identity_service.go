@@ -0,0 +1,312 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOAuthAccountExists    = errors.New("an account with this email already exists, log in with your password and link this provider from your account settings")
+	ErrIdentityAlreadyLinked = errors.New("this identity is already linked to another account")
+	ErrIdentityNotFound      = errors.New("identity not found")
+	ErrLastLoginMethod       = errors.New("cannot unlink the only login method, set a password or link another provider first")
+)
+
+// IdentityService mengelola identitas OAuth yang terhubung ke akun user
+type IdentityService struct {
+	DB *gorm.DB
+}
+
+func NewIdentityService(db *gorm.DB) *IdentityService {
+	return &IdentityService{DB: db}
+}
+
+// ResolveOAuthLogin mencari user dari identitas OAuth, atau membuat user baru jika email belum terdaftar.
+// Akun yang sudah ada tidak pernah dihubungkan otomatis kecuali akun lama yang dibuat oleh provider yang sama
+// sebelum tabel identitas ada; akun lain harus login lalu memakai endpoint link.
+func (s *IdentityService) ResolveOAuthLogin(oauthUser *request.OAuthUser) (*entity.User, error) {
+	var user entity.User
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var identity entity.UserIdentity
+		err := tx.Preload("User").
+			Where("provider = ? AND provider_user_id = ?", oauthUser.Provider, oauthUser.ProviderUserID).
+			First(&identity).Error
+		if err == nil {
+			// user sudah dihapus (soft delete) sehingga tidak ikut ter-preload
+			if identity.User.ID == 0 {
+				return ErrAccountInactive
+			}
+			user = identity.User
+			return s.touchIdentity(tx, &identity, oauthUser)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("error getting identity: %v", err)
+		}
+
+		err = tx.Where("email = ?", oauthUser.Email).First(&user).Error
+		if err == nil {
+			if user.Provider != oauthUser.Provider || !oauthUser.EmailVerified {
+				return ErrOAuthAccountExists
+			}
+			if err := s.ensureNoIdentityForProvider(tx, user.ID, oauthUser.Provider); err != nil {
+				return err
+			}
+			_, err := s.createIdentity(tx, user.ID, oauthUser)
+			return err
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("error checking user existence: %v", err)
+		}
+
+		username, err := generateUsername(tx, oauthUser.Username, oauthUser.Name, oauthUser.Email)
+		if err != nil {
+			return err
+		}
+
+		user = entity.User{
+			Name:       oauthUser.Name,
+			Username:   username,
+			Email:      oauthUser.Email,
+			Role:       entity.RoleUser,
+			Provider:   oauthUser.Provider,
+			ProfilePic: oauthUser.Picture,
+		}
+		if user.Name == "" {
+			user.Name = username
+		}
+		if oauthUser.EmailVerified {
+			now := time.Now()
+			user.EmailVerified = true
+			user.EmailVerifiedAt = &now
+		}
+
+		if err := tx.Create(&user).Error; err != nil {
+			return fmt.Errorf("error creating user: %v", err)
+		}
+		_, err = s.createIdentity(tx, user.ID, oauthUser)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// LinkIdentity menghubungkan identitas OAuth ke user yang memulai alur link
+func (s *IdentityService) LinkIdentity(userID uint, oauthUser *request.OAuthUser) (*response.IdentityResponse, error) {
+	var identity entity.UserIdentity
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("provider = ? AND provider_user_id = ?", oauthUser.Provider, oauthUser.ProviderUserID).
+			First(&identity).Error
+		if err == nil {
+			if identity.UserID != userID {
+				return ErrIdentityAlreadyLinked
+			}
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("error getting identity: %v", err)
+		}
+
+		created, err := s.createIdentity(tx, userID, oauthUser)
+		if err != nil {
+			return err
+		}
+		identity = *created
+
+		recordSecurityEvent(tx, entity.SecurityEvent{
+			UserID:  &userID,
+			Type:    entity.SecurityEventIdentityLinked,
+			Details: fmt.Sprintf("provider=%s email=%s", oauthUser.Provider, oauthUser.Email),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := toIdentityResponse(identity)
+	return &result, nil
+}
+
+func (s *IdentityService) ListIdentities(userID uint) (*response.IdentityListResponse, error) {
+	var user entity.User
+	if err := s.DB.Select("id", "password").First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("error getting user: %v", err)
+	}
+
+	var identities []entity.UserIdentity
+	if err := s.DB.Where("user_id = ?", userID).Order("created_at ASC").Find(&identities).Error; err != nil {
+		return nil, fmt.Errorf("error getting identities: %v", err)
+	}
+
+	identityResponses := make([]response.IdentityResponse, len(identities))
+	for i, identity := range identities {
+		identityResponses[i] = toIdentityResponse(identity)
+	}
+
+	return &response.IdentityListResponse{
+		HasPassword: user.Password != "",
+		Identities:  identityResponses,
+	}, nil
+}
+
+// UnlinkIdentity melepas identitas, ditolak jika identitas tersebut satu-satunya cara login
+func (s *IdentityService) UnlinkIdentity(userID, identityID uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var identity entity.UserIdentity
+		if err := tx.Where("id = ? AND user_id = ?", identityID, userID).First(&identity).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrIdentityNotFound
+			}
+			return fmt.Errorf("error getting identity: %v", err)
+		}
+
+		var user entity.User
+		if err := tx.Select("id", "password").First(&user, userID).Error; err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		if user.Password == "" {
+			var remaining int64
+			if err := tx.Model(&entity.UserIdentity{}).
+				Where("user_id = ? AND id <> ?", userID, identityID).
+				Count(&remaining).Error; err != nil {
+				return fmt.Errorf("error counting identities: %v", err)
+			}
+			if remaining == 0 {
+				return ErrLastLoginMethod
+			}
+		}
+
+		// hard delete agar identitas yang sama bisa dihubungkan lagi (unique index provider + subject)
+		if err := tx.Unscoped().Delete(&identity).Error; err != nil {
+			return fmt.Errorf("error unlinking identity: %v", err)
+		}
+
+		recordSecurityEvent(tx, entity.SecurityEvent{
+			UserID:  &userID,
+			Type:    entity.SecurityEventIdentityUnlinked,
+			Details: fmt.Sprintf("provider=%s email=%s", identity.Provider, identity.Email),
+		})
+		return nil
+	})
+}
+
+func (s *IdentityService) ensureNoIdentityForProvider(tx *gorm.DB, userID uint, provider string) error {
+	var count int64
+	if err := tx.Model(&entity.UserIdentity{}).
+		Where("user_id = ? AND provider = ?", userID, provider).
+		Count(&count).Error; err != nil {
+		return fmt.Errorf("error counting identities: %v", err)
+	}
+	// akun sudah punya identitas lain di provider ini, subject berbeda berarti orang lain dengan email yang sama
+	if count > 0 {
+		return ErrOAuthAccountExists
+	}
+	return nil
+}
+
+func (s *IdentityService) createIdentity(tx *gorm.DB, userID uint, oauthUser *request.OAuthUser) (*entity.UserIdentity, error) {
+	now := time.Now()
+	identity := entity.UserIdentity{
+		UserID:         userID,
+		Provider:       oauthUser.Provider,
+		ProviderUserID: oauthUser.ProviderUserID,
+		Email:          oauthUser.Email,
+		LastLoginAt:    &now,
+	}
+	if err := tx.Omit("User").Create(&identity).Error; err != nil {
+		return nil, fmt.Errorf("error creating identity: %v", err)
+	}
+	return &identity, nil
+}
+
+// touchIdentity mencatat login terakhir tanpa mengubah username atau provider akun
+func (s *IdentityService) touchIdentity(tx *gorm.DB, identity *entity.UserIdentity, oauthUser *request.OAuthUser) error {
+	if err := tx.Model(identity).Omit("User").Updates(map[string]interface{}{
+		"email":         oauthUser.Email,
+		"last_login_at": time.Now(),
+	}).Error; err != nil {
+		return fmt.Errorf("error updating identity: %v", err)
+	}
+	return nil
+}
+
+// generateUsername membuat username unik dari kandidat pertama yang tidak kosong,
+// menambahkan angka jika sudah dipakai (termasuk akun yang sudah di-soft delete)
+func generateUsername(tx *gorm.DB, candidates ...string) (string, error) {
+	base := ""
+	for _, candidate := range candidates {
+		if i := strings.Index(candidate, "@"); i >= 0 {
+			candidate = candidate[:i]
+		}
+		if base = sanitizeUsername(candidate); base != "" {
+			break
+		}
+	}
+	if base == "" {
+		base = "user"
+	}
+	// sisakan tempat untuk suffix angka
+	if len(base) > utility.UsernameMaxLength-4 {
+		base = base[:utility.UsernameMaxLength-4]
+	}
+
+	var taken []string
+	if err := tx.Unscoped().Model(&entity.User{}).
+		Where("username LIKE ?", base+"%").
+		Pluck("username", &taken).Error; err != nil {
+		return "", fmt.Errorf("error checking usernames: %v", err)
+	}
+	takenSet := make(map[string]bool, len(taken))
+	for _, username := range taken {
+		takenSet[strings.ToLower(username)] = true
+	}
+
+	if len(base) >= utility.UsernameMinLength && !takenSet[base] {
+		return base, nil
+	}
+	for i := 1; i < 10000; i++ {
+		candidate := base + strconv.Itoa(i)
+		if len(candidate) >= utility.UsernameMinLength && !takenSet[candidate] {
+			return candidate, nil
+		}
+	}
+
+	// semua suffix terpakai, pakai angka acak yang tidak terikat prefix
+	return fmt.Sprintf("user%d", 100000+rand.Intn(900000)), nil
+}
+
+func sanitizeUsername(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func toIdentityResponse(identity entity.UserIdentity) response.IdentityResponse {
+	return response.IdentityResponse{
+		ID:          identity.ID,
+		Provider:    identity.Provider,
+		Email:       identity.Email,
+		LastLoginAt: identity.LastLoginAt,
+		LinkedAt:    identity.CreatedAt,
+	}
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// expense yang dianalisis ulang setiap putaran, anomali lebih lama tetap disimpan sebagai riwayat
+	anomalyWindowDays = 30
+	// tanpa filter month, insights menampilkan anomali 3 bulan terakhir
+	insightDefaultMonths = 3
+)
+
+// InsightService mendeteksi pengeluaran tidak biasa per workspace: expense yang jauh di atas nominal biasa
+// kategorinya dan kategori yang siklus bulanan ini berjalan di atas laju normal. Analisis dijalankan worker di
+// background (RunWorker) setiap kali transaksi workspace berubah dan sekali sehari untuk semua workspace aktif.
+type InsightService struct {
+	DB *gorm.DB
+
+	queue       *workspaceQueue
+	mu          sync.Mutex
+	lastRun     time.Time
+	lastFullDay time.Time
+}
+
+func NewInsightService(db *gorm.DB, events *WorkspaceEvents) *InsightService {
+	return &InsightService{
+		DB:    db,
+		queue: newWorkspaceQueue(events),
+	}
+}
+
+// RunWorker menjalankan analisis setiap interval atau saat ada perubahan transaksi sampai ctx selesai
+func (s *InsightService) RunWorker(ctx context.Context, interval time.Duration) {
+	s.queue.run(ctx, interval, s.RunPendingAnalysis)
+}
+
+// RunPendingAnalysis menganalisis workspace yang ditandai, workspace yang transaksinya berubah sejak putaran
+// sebelumnya (mis. lewat instance lain) dan, sekali per hari, semua workspace dengan expense di jendela analisis
+func (s *InsightService) RunPendingAnalysis(now time.Time) {
+	today := utility.CalendarDate(now.UTC())
+
+	s.mu.Lock()
+	lastRun, fullRun := s.lastRun, !s.lastFullDay.Equal(today)
+	s.mu.Unlock()
+
+	var changed []uint
+	query := s.DB.Unscoped().Model(&entity.Transaction{}).Distinct("workspace_id")
+	if fullRun {
+		// satu hari lebih awal karena today setiap workspace mengikuti zona waktu pemiliknya
+		query = query.Where("type = 'expense' AND deleted_at IS NULL AND date >= ?", today.AddDate(0, 0, -anomalyWindowDays))
+	} else {
+		query = query.Where("updated_at >= ? OR deleted_at >= ?", lastRun, lastRun)
+	}
+	if err := query.Pluck("workspace_id", &changed).Error; err != nil {
+		logrus.Errorf("Failed to find workspaces for anomaly analysis: %v", err)
+		return
+	}
+
+	for _, workspaceID := range mergeWorkspaceIDs(s.queue.drain(), changed) {
+		if err := s.AnalyzeWorkspace(workspaceID, now); err != nil {
+			logrus.Errorf("Failed to analyze spending of workspace %d: %v", workspaceID, err)
+		}
+	}
+
+	s.mu.Lock()
+	s.lastRun = now
+	if fullRun {
+		s.lastFullDay = today
+	}
+	s.mu.Unlock()
+}
+
+// AnalyzeWorkspace menghitung ulang anomali expense anomalyWindowDays terakhir dan laju kategori siklus bulanan
+// berjalan. Tanggal hari ini dan siklus bulanan mengikuti zona waktu dan cycle_start_day pemilik workspace.
+// Hasil lama di rentang yang sama diganti, waktu deteksi anomali yang masih berlaku dipertahankan.
+func (s *InsightService) AnalyzeWorkspace(workspaceID uint, now time.Time) error {
+	var workspace entity.Workspace
+	if err := s.DB.Select("id", "owner_id").First(&workspace, workspaceID).Error; err != nil {
+		return fmt.Errorf("error getting workspace: %v", err)
+	}
+	preference, err := loadUserPreference(s.DB, workspace.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	config := periodConfig(preference)
+	today := utility.CalendarDate(now.In(preference.Location()))
+	windowStart := today.AddDate(0, 0, 1-anomalyWindowDays)
+	cycleStart := utility.PeriodStart(today, utility.GranularityMonth, config)
+	month := utility.MonthStart(cycleStart)
+	historyStart := utility.AddPeriods(cycleStart, utility.GranularityMonth, -utility.CategoryPaceLookbackMonths, config)
+
+	since := windowStart.AddDate(0, 0, -utility.AnomalyHistoryDays)
+	if historyStart.Before(since) {
+		since = historyStart
+	}
+
+	var expenses []utility.ExpenseRecord
+	if err := s.DB.Model(&entity.Transaction{}).
+		Select("id, category_id, amount, date").
+		Where("workspace_id = ? AND type = 'expense' AND date >= ? AND date <= ?", workspaceID, since, today).
+		Order("date, id").
+		Scan(&expenses).Error; err != nil {
+		return fmt.Errorf("error getting expenses: %v", err)
+	}
+
+	// total siklus sebelumnya dihitung dari transaksi, monthly_aggregates hanya berisi bulan kalender
+	cycleTotals := map[uint]map[time.Time]float64{}
+	monthToDate := map[uint]float64{}
+	for _, expense := range expenses {
+		date := utility.CalendarDate(expense.Date)
+		if !date.Before(cycleStart) {
+			monthToDate[expense.CategoryID] += expense.Amount
+			continue
+		}
+		if date.Before(historyStart) {
+			continue
+		}
+		if cycleTotals[expense.CategoryID] == nil {
+			cycleTotals[expense.CategoryID] = map[time.Time]float64{}
+		}
+		cycleTotals[expense.CategoryID][utility.PeriodStart(date, utility.GranularityMonth, config)] += expense.Amount
+	}
+
+	monthlyHistory := map[uint][]float64{}
+	for categoryID, totals := range cycleTotals {
+		for _, total := range totals {
+			if total > 0 {
+				monthlyHistory[categoryID] = append(monthlyHistory[categoryID], total)
+			}
+		}
+	}
+
+	anomalies := append(utility.DetectLargeTransactions(expenses, windowStart, config),
+		utility.DetectCategoryPace(monthToDate, monthlyHistory, today, config)...)
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		replaced := tx.Where("workspace_id = ?", workspaceID).
+			Where(tx.Where("kind = ? AND date >= ?", entity.AnomalyLargeTransaction, windowStart).
+				Or("kind = ? AND month = ?", entity.AnomalyCategoryPace, month))
+
+		var existing []entity.SpendingAnomaly
+		if err := replaced.Session(&gorm.Session{}).Find(&existing).Error; err != nil {
+			return fmt.Errorf("error getting anomalies: %v", err)
+		}
+		detectedAt := make(map[string]time.Time, len(existing))
+		for _, anomaly := range existing {
+			detectedAt[anomalyKey(anomaly)] = anomaly.DetectedAt
+		}
+
+		if err := replaced.Session(&gorm.Session{}).Delete(&entity.SpendingAnomaly{}).Error; err != nil {
+			return fmt.Errorf("error deleting anomalies: %v", err)
+		}
+		if len(anomalies) == 0 {
+			return nil
+		}
+
+		now := time.Now()
+		for i := range anomalies {
+			anomalies[i].WorkspaceID = workspaceID
+			anomalies[i].DetectedAt = now
+			if detected, ok := detectedAt[anomalyKey(anomalies[i])]; ok {
+				anomalies[i].DetectedAt = detected
+			}
+		}
+		if err := tx.Create(&anomalies).Error; err != nil {
+			return fmt.Errorf("error saving anomalies: %v", err)
+		}
+		return nil
+	})
+}
+
+func anomalyKey(anomaly entity.SpendingAnomaly) string {
+	if anomaly.TransactionID != nil {
+		return fmt.Sprintf("%s:%d", anomaly.Kind, *anomaly.TransactionID)
+	}
+	return fmt.Sprintf("%s:%d:%s", anomaly.Kind, anomaly.CategoryID, anomaly.Month.Format("2006-01"))
+}
+
+type insightRow struct {
+	entity.SpendingAnomaly
+	CategoryName string
+}
+
+// GetInsights mengembalikan anomali workspace aktif, terbaru lebih dulu
+func (s *InsightService) GetInsights(scope request.WorkspaceScope, filter request.InsightFilter) ([]response.InsightResponse, error) {
+	query := s.DB.Table("spending_anomalies").
+		Select("spending_anomalies.*, categories.name AS category_name").
+		Joins("LEFT JOIN categories ON spending_anomalies.category_id = categories.id").
+		Joins("LEFT JOIN transactions ON spending_anomalies.transaction_id = transactions.id").
+		Where("spending_anomalies.workspace_id = ?", scope.WorkspaceID).
+		// anomali transaksi yang sudah dihapus tidak ditampilkan
+		Where("spending_anomalies.transaction_id IS NULL OR transactions.deleted_at IS NULL")
+
+	if filter.Kind != "" {
+		query = query.Where("spending_anomalies.kind = ?", filter.Kind)
+	}
+	if filter.Month != "" {
+		month, err := time.Parse("2006-01", filter.Month)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("spending_anomalies.month = ?", month)
+	} else {
+		since := utility.MonthStart(time.Now()).AddDate(0, 1-insightDefaultMonths, 0)
+		query = query.Where("spending_anomalies.month >= ?", since)
+	}
+
+	var rows []insightRow
+	if err := query.Order("spending_anomalies.date DESC, spending_anomalies.id DESC").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error getting insights: %v", err)
+	}
+
+	insights := make([]response.InsightResponse, len(rows))
+	for i, row := range rows {
+		insights[i] = response.InsightResponse{
+			ID:            row.ID,
+			Kind:          row.Kind,
+			TransactionID: row.TransactionID,
+			CategoryID:    row.CategoryID,
+			Category:      row.CategoryName,
+			Date:          row.Date.Format("2006-01-02"),
+			Month:         row.Month.Format("2006-01"),
+			Amount:        row.Amount,
+			Baseline:      row.Baseline,
+			Ratio:         row.Ratio,
+			Message:       anomalyMessage(row.SpendingAnomaly, row.CategoryName),
+			DetectedAt:    row.DetectedAt,
+		}
+	}
+	return insights, nil
+}
+
+func anomalyMessage(anomaly entity.SpendingAnomaly, category string) string {
+	if anomaly.Kind == entity.AnomalyCategoryPace {
+		return fmt.Sprintf("Spending on %s this month is %.0f%% above its usual pace", category, (anomaly.Ratio-1)*100)
+	}
+	return fmt.Sprintf("This %s expense is %.1fx larger than usual", category, anomaly.Ratio)
+}
+
+// loadTransactionAnomalies mengambil anomali per transaksi untuk ditempelkan pada TransactionResponse
+func loadTransactionAnomalies(db *gorm.DB, transactions []entity.Transaction) (map[uint][]response.TransactionAnomalyResponse, error) {
+	result := map[uint][]response.TransactionAnomalyResponse{}
+	if len(transactions) == 0 {
+		return result, nil
+	}
+
+	ids := make([]uint, len(transactions))
+	categories := make(map[uint]string, len(transactions))
+	for i, transaction := range transactions {
+		ids[i] = transaction.ID
+		categories[transaction.ID] = transaction.Category.Name
+	}
+
+	var anomalies []entity.SpendingAnomaly
+	if err := db.Where("transaction_id IN ?", ids).Find(&anomalies).Error; err != nil {
+		return nil, err
+	}
+	for _, anomaly := range anomalies {
+		transactionID := *anomaly.TransactionID
+		result[transactionID] = append(result[transactionID], response.TransactionAnomalyResponse{
+			Kind:     anomaly.Kind,
+			Baseline: anomaly.Baseline,
+			Ratio:    anomaly.Ratio,
+			Message:  anomalyMessage(anomaly, categories[transactionID]),
+		})
+	}
+	return result, nil
+}
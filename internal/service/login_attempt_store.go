@@ -0,0 +1,139 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginAttemptStore menyimpan state gagal login. Update harus atomic agar aman dipakai paralel.
+type LoginAttemptStore interface {
+	Get(key string) (entity.LoginAttempt, error)
+	Update(key string, fn func(attempt *entity.LoginAttempt)) (entity.LoginAttempt, error)
+	Reset(key string) error
+}
+
+var ErrUnknownLoginAttemptStore = errors.New("unknown login attempt store driver, use \"memory\" or \"db\"")
+
+// NewLoginAttemptStore memilih store berdasarkan driver: "db" untuk deployment multi instance, "memory" atau kosong
+// untuk satu instance. Driver lain ditolak agar salah ketik tidak diam-diam mematikan lockout bersama.
+func NewLoginAttemptStore(driver string, db *gorm.DB) (LoginAttemptStore, error) {
+	switch driver {
+	case "db":
+		return &DBLoginAttemptStore{DB: db}, nil
+	case "memory", "":
+		return NewMemoryLoginAttemptStore(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLoginAttemptStore, driver)
+	}
+}
+
+// MemoryLoginAttemptStore hanya berlaku untuk satu instance aplikasi
+type MemoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]entity.LoginAttempt
+}
+
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{attempts: make(map[string]entity.LoginAttempt)}
+}
+
+func (s *MemoryLoginAttemptStore) Get(key string) (entity.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.attempts[key], nil
+}
+
+func (s *MemoryLoginAttemptStore) Update(key string, fn func(attempt *entity.LoginAttempt)) (entity.LoginAttempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempt := s.attempts[key]
+	attempt.AttemptKey = key
+	fn(&attempt)
+	attempt.UpdatedAt = time.Now()
+	s.attempts[key] = attempt
+
+	s.pruneLocked(attempt.UpdatedAt)
+
+	return attempt, nil
+}
+
+func (s *MemoryLoginAttemptStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
+
+// pruneLocked membuang entry lama agar map tidak terus membesar
+func (s *MemoryLoginAttemptStore) pruneLocked(now time.Time) {
+	if len(s.attempts) < 10000 {
+		return
+	}
+	for key, attempt := range s.attempts {
+		if now.Sub(attempt.LastFailureAt) > loginAttemptWindow && now.After(attempt.LockedUntil) {
+			delete(s.attempts, key)
+		}
+	}
+}
+
+// DBLoginAttemptStore menyimpan state di tabel login_attempts sehingga berlaku lintas instance
+type DBLoginAttemptStore struct {
+	DB *gorm.DB
+}
+
+func (s *DBLoginAttemptStore) Get(key string) (entity.LoginAttempt, error) {
+	var attempt entity.LoginAttempt
+	if err := s.DB.Where("attempt_key = ?", key).First(&attempt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return entity.LoginAttempt{AttemptKey: key}, nil
+		}
+		return attempt, fmt.Errorf("error getting login attempt: %v", err)
+	}
+
+	return attempt, nil
+}
+
+func (s *DBLoginAttemptStore) Update(key string, fn func(attempt *entity.LoginAttempt)) (entity.LoginAttempt, error) {
+	var attempt entity.LoginAttempt
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// pastikan row ada lalu lock untuk read-modify-write
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&entity.LoginAttempt{AttemptKey: key, LastFailureAt: time.Now()}).Error; err != nil {
+			return fmt.Errorf("error creating login attempt: %v", err)
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("attempt_key = ?", key).
+			First(&attempt).Error; err != nil {
+			return fmt.Errorf("error locking login attempt: %v", err)
+		}
+
+		fn(&attempt)
+
+		if err := tx.Save(&attempt).Error; err != nil {
+			return fmt.Errorf("error saving login attempt: %v", err)
+		}
+
+		return nil
+	})
+
+	return attempt, err
+}
+
+func (s *DBLoginAttemptStore) Reset(key string) error {
+	if err := s.DB.Where("attempt_key = ?", key).Delete(&entity.LoginAttempt{}).Error; err != nil {
+		return fmt.Errorf("error resetting login attempt: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// kegagalan yang lebih lama dari window ini tidak dihitung lagi
+const loginAttemptWindow = 24 * time.Hour
+
+type loginLockoutPolicy struct {
+	MaxFailures int           // jumlah gagal sebelum lockout pertama
+	BaseLockout time.Duration // lockout pertama, berlipat dua untuk setiap gagal berikutnya
+	MaxLockout  time.Duration
+}
+
+var (
+	accountLockoutPolicy = loginLockoutPolicy{MaxFailures: 5, BaseLockout: 30 * time.Second, MaxLockout: time.Hour}
+	ipLockoutPolicy      = loginLockoutPolicy{MaxFailures: 20, BaseLockout: time.Minute, MaxLockout: time.Hour}
+)
+
+// LoginLockedError dikembalikan saat akun atau IP sedang dikunci sementara
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, try again in %d seconds", int(math.Ceil(e.RetryAfter.Seconds())))
+}
+
+// LoginGuard melacak gagal login per akun dan per IP dengan exponential backoff
+type LoginGuard struct {
+	Store LoginAttemptStore
+	DB    *gorm.DB // untuk mencatat security event
+}
+
+func NewLoginGuard(store LoginAttemptStore, db *gorm.DB) *LoginGuard {
+	return &LoginGuard{Store: store, DB: db}
+}
+
+func accountAttemptKey(userID uint) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// untuk identifier yang tidak terdaftar, agar perilakunya sama dengan akun yang ada
+func identifierAttemptKey(identifier string) string {
+	return "login:" + strings.ToLower(strings.TrimSpace(identifier))
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check mengembalikan LoginLockedError jika salah satu key sedang terkunci
+func (g *LoginGuard) Check(keys ...string) error {
+	now := time.Now()
+	for _, key := range keys {
+		attempt, err := g.Store.Get(key)
+		if err != nil {
+			return err
+		}
+		if now.Before(attempt.LockedUntil) {
+			return &LoginLockedError{RetryAfter: attempt.LockedUntil.Sub(now)}
+		}
+	}
+
+	return nil
+}
+
+// RegisterFailure mencatat gagal login untuk akun dan IP. Mengembalikan LoginLockedError jika lockout dimulai.
+func (g *LoginGuard) RegisterFailure(accountKey string, userID *uint, client request.ClientInfo) error {
+	accountLock, err := g.registerFailure(accountKey, accountLockoutPolicy)
+	if err != nil {
+		return err
+	}
+	if accountLock > 0 {
+		g.recordEvent(entity.SecurityEvent{
+			UserID:    userID,
+			Type:      entity.SecurityEventAccountLocked,
+			IPAddress: client.IPAddress,
+			UserAgent: client.UserAgent,
+			Details:   fmt.Sprintf("key=%s locked_for=%s", accountKey, accountLock),
+		})
+	}
+
+	var ipLock time.Duration
+	if client.IPAddress != "" {
+		ipLock, err = g.registerFailure(ipAttemptKey(client.IPAddress), ipLockoutPolicy)
+		if err != nil {
+			return err
+		}
+		if ipLock > 0 {
+			g.recordEvent(entity.SecurityEvent{
+				Type:      entity.SecurityEventIPLocked,
+				IPAddress: client.IPAddress,
+				UserAgent: client.UserAgent,
+				Details:   fmt.Sprintf("locked_for=%s", ipLock),
+			})
+		}
+	}
+
+	if lock := max(accountLock, ipLock); lock > 0 {
+		return &LoginLockedError{RetryAfter: lock}
+	}
+
+	return nil
+}
+
+func (g *LoginGuard) registerFailure(key string, policy loginLockoutPolicy) (time.Duration, error) {
+	now := time.Now()
+	var lockout time.Duration
+
+	_, err := g.Store.Update(key, func(attempt *entity.LoginAttempt) {
+		if now.Sub(attempt.LastFailureAt) > loginAttemptWindow {
+			attempt.Failures = 0
+		}
+		attempt.Failures++
+		attempt.LastFailureAt = now
+
+		if attempt.Failures >= policy.MaxFailures {
+			lockout = policy.BaseLockout << min(attempt.Failures-policy.MaxFailures, 16)
+			if lockout > policy.MaxLockout {
+				lockout = policy.MaxLockout
+			}
+			attempt.LockedUntil = now.Add(lockout)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return lockout, nil
+}
+
+// RegisterSuccess mereset counter akun, counter IP tidak direset agar tidak bisa diakali dengan akun sendiri
+func (g *LoginGuard) RegisterSuccess(accountKey string) error {
+	return g.Store.Reset(accountKey)
+}
+
+// Unlock dipakai admin untuk membuka kunci akun
+func (g *LoginGuard) Unlock(userID uint, adminID uint) error {
+	if err := g.Store.Reset(accountAttemptKey(userID)); err != nil {
+		return err
+	}
+
+	g.recordEvent(entity.SecurityEvent{
+		UserID:  &userID,
+		Type:    entity.SecurityEventAccountUnlocked,
+		Details: fmt.Sprintf("unlocked_by=%d", adminID),
+	})
+
+	return nil
+}
+
+func (g *LoginGuard) recordEvent(event entity.SecurityEvent) {
+	recordSecurityEvent(g.DB, event)
+}
+
+// security event tidak boleh menggagalkan request, cukup di-log jika gagal disimpan
+func recordSecurityEvent(db *gorm.DB, event entity.SecurityEvent) {
+	logrus.WithFields(logrus.Fields{
+		"type":       event.Type,
+		"user_id":    event.UserID,
+		"ip_address": event.IPAddress,
+		"details":    event.Details,
+	}).Warn("Security event")
+
+	if db == nil {
+		return
+	}
+	if err := db.Create(&event).Error; err != nil {
+		logrus.Errorf("Failed to save security event: %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidNetWorthRange = errors.New("invalid net worth range, use start_date <= end_date in format YYYY-MM-DD (daily history covers the last 366 days)")
+
+// NetWorthService menyimpan snapshot net worth harian dan bulanan per workspace. Snapshot dibangun ulang dari
+// riwayat transaksi oleh worker di background (RunWorker) setiap kali transaksi workspace berubah dan sekali
+// sehari untuk semua workspace, sehingga snapshot yang belum ada otomatis terisi.
+type NetWorthService struct {
+	DB *gorm.DB
+
+	queue       *workspaceQueue
+	mu          sync.Mutex
+	lastRun     time.Time
+	lastFullDay time.Time
+}
+
+func NewNetWorthService(db *gorm.DB, events *WorkspaceEvents) *NetWorthService {
+	return &NetWorthService{
+		DB:    db,
+		queue: newWorkspaceQueue(events),
+	}
+}
+
+// RunWorker membangun ulang snapshot setiap interval atau saat ada perubahan transaksi sampai ctx selesai
+func (s *NetWorthService) RunWorker(ctx context.Context, interval time.Duration) {
+	s.queue.run(ctx, interval, s.RunPendingSnapshots)
+}
+
+// RunPendingSnapshots membangun ulang snapshot workspace yang ditandai, workspace yang transaksinya berubah
+// sejak putaran sebelumnya dan, sekali per hari, semua workspace yang memiliki transaksi
+func (s *NetWorthService) RunPendingSnapshots(now time.Time) {
+	today := utility.CalendarDate(now.UTC())
+
+	s.mu.Lock()
+	lastRun, fullRun := s.lastRun, !s.lastFullDay.Equal(today)
+	s.mu.Unlock()
+
+	var changed []uint
+	var err error
+	if fullRun {
+		err = s.DB.Model(&entity.MonthlyAggregate{}).Distinct("workspace_id").Pluck("workspace_id", &changed).Error
+	} else {
+		err = s.DB.Unscoped().Model(&entity.Transaction{}).Distinct("workspace_id").
+			Where("updated_at >= ? OR deleted_at >= ?", lastRun, lastRun).
+			Pluck("workspace_id", &changed).Error
+	}
+	if err != nil {
+		logrus.Errorf("Failed to find workspaces for net worth snapshots: %v", err)
+		return
+	}
+
+	for _, workspaceID := range mergeWorkspaceIDs(s.queue.drain(), changed) {
+		if err := s.RebuildSnapshots(workspaceID, today); err != nil {
+			logrus.Errorf("Failed to build net worth snapshots of workspace %d: %v", workspaceID, err)
+		}
+	}
+
+	s.mu.Lock()
+	s.lastRun = now
+	if fullRun {
+		s.lastFullDay = today
+	}
+	s.mu.Unlock()
+}
+
+// RebuildSnapshots mengganti semua snapshot workspace dengan hasil perhitungan dari riwayat transaksi
+func (s *NetWorthService) RebuildSnapshots(workspaceID uint, today time.Time) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		snapshots, err := utility.BuildNetWorthSnapshots(tx, workspaceID, today)
+		if err != nil {
+			return fmt.Errorf("error calculating snapshots: %v", err)
+		}
+
+		if err := tx.Where("workspace_id = ?", workspaceID).Delete(&entity.NetWorthSnapshot{}).Error; err != nil {
+			return fmt.Errorf("error deleting snapshots: %v", err)
+		}
+		if len(snapshots) == 0 {
+			return nil
+		}
+		if err := tx.CreateInBatches(&snapshots, 500).Error; err != nil {
+			return fmt.Errorf("error saving snapshots: %v", err)
+		}
+		return nil
+	})
+}
+
+// GetNetWorthChart mengembalikan net worth di akhir setiap hari atau bulan dalam rentang. Tanpa tanggal,
+// rentang adalah 12 bulan atau 30 hari terakhir sampai hari ini di timezone user.
+func (s *NetWorthService) GetNetWorthChart(scope request.WorkspaceScope, filter request.NetWorthFilter) (*response.RespNetWorthChart, error) {
+	preference, err := loadUserPreference(s.DB, scope.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	granularity := filter.Granularity
+	if granularity == "" {
+		granularity = entity.NetWorthMonthly
+	}
+	today := utility.CalendarDate(time.Now().In(preference.Location()))
+	// snapshot bulanan selalu mengikuti bulan kalender, tidak memakai siklus bulanan user
+	config := utility.PeriodConfig{FirstDayOfWeek: preference.FirstDayOfWeek, CycleStartDay: 1, Locale: preference.Locale}
+
+	start, end, err := netWorthRange(filter, granularity, today)
+	if err != nil {
+		return nil, err
+	}
+	periods := utility.BuildPeriods(start, end, granularity, config)
+	if len(periods) > maxDashboardPeriods {
+		return nil, ErrInvalidNetWorthRange
+	}
+
+	// workspace yang belum pernah diproses worker langsung dibangun agar chart pertama tidak kosong
+	var count int64
+	if err := s.DB.Model(&entity.NetWorthSnapshot{}).Where("workspace_id = ?", scope.WorkspaceID).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("error counting net worth snapshots: %v", err)
+	}
+	if count == 0 {
+		if err := s.RebuildSnapshots(scope.WorkspaceID, utility.CalendarDate(time.Now().UTC())); err != nil {
+			return nil, err
+		}
+	}
+
+	// snapshot terakhir sebelum rentang menjadi nilai awal periode yang belum punya snapshot
+	var snapshots []entity.NetWorthSnapshot
+	if err := s.DB.Where("workspace_id = ? AND granularity = ? AND date < ?", scope.WorkspaceID, granularity, end).
+		Where("date >= ? OR date = (?)", start,
+			s.DB.Model(&entity.NetWorthSnapshot{}).Select("MAX(date)").
+				Where("workspace_id = ? AND granularity = ? AND date < ?", scope.WorkspaceID, granularity, start)).
+		Order("date").
+		Find(&snapshots).Error; err != nil {
+		return nil, fmt.Errorf("error getting net worth snapshots: %v", err)
+	}
+
+	byPeriod := make(map[string]entity.NetWorthSnapshot, len(snapshots))
+	var netWorth float64
+	for _, snapshot := range snapshots {
+		if snapshot.Date.Before(start) {
+			netWorth = snapshot.NetWorth
+			continue
+		}
+		byPeriod[utility.PeriodKey(utility.PeriodStart(snapshot.Date, granularity, config), granularity)] = snapshot
+	}
+
+	labels := make([]string, len(periods))
+	keys := make([]string, len(periods))
+	data := make([]float64, len(periods))
+	for i, period := range periods {
+		labels[i] = period.Label
+		keys[i] = period.Key
+		if snapshot, ok := byPeriod[period.Key]; ok {
+			netWorth = snapshot.NetWorth
+		}
+		data[i] = netWorth
+	}
+
+	return &response.RespNetWorthChart{
+		Labels: labels,
+		Keys:   keys,
+		Datasets: []response.ChartDataset{
+			{
+				Label:           "Net Worth",
+				Data:            data,
+				BorderColor:     "#3B82F6",
+				BackgroundColor: "rgba(59, 130, 246, 0.1)",
+			},
+		},
+		Range: response.DashboardRange{
+			StartDate:   start.Format("2006-01-02"),
+			EndDate:     end.AddDate(0, 0, -1).Format("2006-01-02"),
+			Granularity: granularity,
+			Timezone:    preference.Timezone,
+		},
+	}, nil
+}
+
+// netWorthRange menentukan rentang [start, end) chart net worth
+func netWorthRange(filter request.NetWorthFilter, granularity string, today time.Time) (time.Time, time.Time, error) {
+	end := today.AddDate(0, 0, 1)
+	if filter.EndDate != "" {
+		parsed, err := time.Parse("2006-01-02", filter.EndDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidNetWorthRange
+		}
+		end = parsed.AddDate(0, 0, 1)
+	}
+
+	var start time.Time
+	if filter.StartDate != "" {
+		parsed, err := time.Parse("2006-01-02", filter.StartDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidNetWorthRange
+		}
+		start = parsed
+	} else if granularity == entity.NetWorthDaily {
+		start = end.AddDate(0, 0, -30)
+	} else {
+		start = utility.MonthStart(end.AddDate(0, 0, -1)).AddDate(0, -11, 0)
+	}
+
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, ErrInvalidNetWorthRange
+	}
+	// snapshot harian hanya disimpan NetWorthDailyDays terakhir
+	if granularity == entity.NetWorthDaily && start.Before(today.AddDate(0, 0, 1-utility.NetWorthDailyDays)) {
+		return time.Time{}, time.Time{}, ErrInvalidNetWorthRange
+	}
+	return start, end, nil
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/google"
+)
+
+var (
+	ErrOAuthProviderNotFound = errors.New("oauth provider not found")
+	ErrOAuthEmailMissing     = errors.New("oauth provider did not return a verified email address")
+	ErrOAuthLinkSession      = errors.New("identity linking must be completed in the session that started it")
+)
+
+// tipe provider, menentukan endpoint default dan cara membaca userinfo
+const (
+	OAuthTypeGoogle = "google"
+	OAuthTypeGitHub = "github"
+	OAuthTypeOIDC   = "oidc"
+)
+
+// OAuthProviderConfig berasal dari environment (lihat config.InitOAuthProviders).
+// Endpoint yang diisi menimpa default tipe provider atau hasil discovery OIDC.
+type OAuthProviderConfig struct {
+	Name         string // dipakai di URL, mis. /auth/oauth/github/login
+	DisplayName  string
+	Type         string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	IssuerURL    string // khusus oidc, endpoint diambil dari /.well-known/openid-configuration
+	AuthURL      string
+	TokenURL     string
+	UserInfoURL  string
+}
+
+type OAuthProvider struct {
+	Name        string
+	DisplayName string
+	Type        string
+	oauth       *oauth2.Config
+	userInfoURL string
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+}
+
+// NewOAuthProvider membangun provider dari konfigurasi, provider oidc melakukan discovery ke issuer
+func NewOAuthProvider(ctx context.Context, cfg OAuthProviderConfig) (*OAuthProvider, error) {
+	if cfg.Name == "" || cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, fmt.Errorf("oauth provider %q requires name, client id and client secret", cfg.Name)
+	}
+
+	var endpoint oauth2.Endpoint
+	var userInfoURL string
+	var scopes []string
+
+	switch cfg.Type {
+	case OAuthTypeGoogle:
+		endpoint = google.Endpoint
+		userInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+		scopes = []string{"openid", "email", "profile"}
+	case OAuthTypeGitHub:
+		endpoint = github.Endpoint
+		userInfoURL = "https://api.github.com/user"
+		scopes = []string{"read:user", "user:email"}
+	case OAuthTypeOIDC:
+		if cfg.IssuerURL == "" {
+			return nil, fmt.Errorf("oidc provider %q requires an issuer url", cfg.Name)
+		}
+		discovery, err := discoverOIDC(ctx, cfg.IssuerURL)
+		if err != nil {
+			return nil, fmt.Errorf("oidc provider %q: %v", cfg.Name, err)
+		}
+		endpoint = oauth2.Endpoint{AuthURL: discovery.AuthorizationEndpoint, TokenURL: discovery.TokenEndpoint}
+		userInfoURL = discovery.UserinfoEndpoint
+		scopes = []string{"openid", "email", "profile"}
+	default:
+		return nil, fmt.Errorf("oauth provider %q has unknown type %q", cfg.Name, cfg.Type)
+	}
+
+	if cfg.AuthURL != "" {
+		endpoint.AuthURL = cfg.AuthURL
+	}
+	if cfg.TokenURL != "" {
+		endpoint.TokenURL = cfg.TokenURL
+	}
+	if cfg.UserInfoURL != "" {
+		userInfoURL = cfg.UserInfoURL
+	}
+	if len(cfg.Scopes) > 0 {
+		scopes = cfg.Scopes
+	}
+	if userInfoURL == "" {
+		return nil, fmt.Errorf("oauth provider %q has no userinfo endpoint", cfg.Name)
+	}
+
+	displayName := cfg.DisplayName
+	if displayName == "" {
+		displayName = cfg.Name
+	}
+
+	return &OAuthProvider{
+		Name:        cfg.Name,
+		DisplayName: displayName,
+		Type:        cfg.Type,
+		oauth: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       scopes,
+			Endpoint:     endpoint,
+		},
+		userInfoURL: userInfoURL,
+	}, nil
+}
+
+func discoverOIDC(ctx context.Context, issuerURL string) (*oidcDiscovery, error) {
+	issuer := strings.TrimSuffix(issuerURL, "/")
+
+	var discovery oidcDiscovery
+	if err := getJSON(ctx, http.DefaultClient, issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("error fetching discovery document: %v", err)
+	}
+
+	// issuer pada dokumen harus sama dengan yang dikonfigurasi (OIDC Discovery 4.3)
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("discovery issuer %q does not match %q", discovery.Issuer, issuerURL)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" {
+		return nil, errors.New("discovery document is missing authorization or token endpoint")
+	}
+
+	return &discovery, nil
+}
+
+// AuthCodeURL membuat URL login dengan PKCE S256
+func (p *OAuthProvider) AuthCodeURL(state, verifier string) string {
+	return p.oauth.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))
+}
+
+// Exchange menukar authorization code dengan token lalu mengambil profil user
+func (p *OAuthProvider) Exchange(ctx context.Context, code, verifier string) (*request.OAuthUser, error) {
+	token, err := p.oauth.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("error exchanging code: %v", err)
+	}
+
+	client := p.oauth.Client(ctx, token)
+
+	var user *request.OAuthUser
+	if p.Type == OAuthTypeGitHub {
+		user, err = p.fetchGitHubUser(ctx, client)
+	} else {
+		user, err = p.fetchOIDCUser(ctx, client)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	user.Provider = p.Name
+	if user.ProviderUserID == "" {
+		return nil, errors.New("oauth provider did not return a subject identifier")
+	}
+	if user.Email == "" {
+		return nil, ErrOAuthEmailMissing
+	}
+	return user, nil
+}
+
+// fetchOIDCUser membaca standard claims dari userinfo endpoint (Google dan issuer OIDC)
+func (p *OAuthProvider) fetchOIDCUser(ctx context.Context, client *http.Client) (*request.OAuthUser, error) {
+	var claims struct {
+		Sub               string          `json:"sub"`
+		Email             string          `json:"email"`
+		EmailVerified     json.RawMessage `json:"email_verified"`
+		Name              string          `json:"name"`
+		PreferredUsername string          `json:"preferred_username"`
+		Picture           string          `json:"picture"`
+	}
+	if err := getJSON(ctx, client, p.userInfoURL, &claims); err != nil {
+		return nil, fmt.Errorf("error fetching userinfo: %v", err)
+	}
+
+	// beberapa issuer mengirim email_verified sebagai string "true"
+	verified, _ := strconv.ParseBool(strings.Trim(string(claims.EmailVerified), `"`))
+
+	return &request.OAuthUser{
+		ProviderUserID: claims.Sub,
+		Email:          claims.Email,
+		EmailVerified:  verified,
+		Name:           claims.Name,
+		Username:       claims.PreferredUsername,
+		Picture:        claims.Picture,
+	}, nil
+}
+
+// fetchGitHubUser mengambil profil dan email utama yang sudah diverifikasi dari GitHub API
+func (p *OAuthProvider) fetchGitHubUser(ctx context.Context, client *http.Client) (*request.OAuthUser, error) {
+	var profile struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := getJSON(ctx, client, p.userInfoURL, &profile); err != nil {
+		return nil, fmt.Errorf("error fetching github user: %v", err)
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, client, p.userInfoURL+"/emails", &emails); err != nil {
+		return nil, fmt.Errorf("error fetching github emails: %v", err)
+	}
+
+	user := &request.OAuthUser{
+		ProviderUserID: strconv.FormatInt(profile.ID, 10),
+		Name:           profile.Name,
+		Username:       profile.Login,
+		Picture:        profile.AvatarURL,
+	}
+	if user.Name == "" {
+		user.Name = profile.Login
+	}
+	for _, email := range emails {
+		if email.Primary && email.Verified {
+			user.Email = email.Email
+			user.EmailVerified = true
+		}
+	}
+
+	return user, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
+
+// OAuthService adalah registry provider login OAuth/OIDC beserta store state-nya
+type OAuthService struct {
+	States    OAuthStateStore
+	providers map[string]*OAuthProvider
+	order     []string
+}
+
+func NewOAuthService(states OAuthStateStore) *OAuthService {
+	return &OAuthService{
+		States:    states,
+		providers: make(map[string]*OAuthProvider),
+	}
+}
+
+func (s *OAuthService) Register(provider *OAuthProvider) {
+	if _, exists := s.providers[provider.Name]; !exists {
+		s.order = append(s.order, provider.Name)
+	}
+	s.providers[provider.Name] = provider
+}
+
+func (s *OAuthService) Provider(name string) (*OAuthProvider, error) {
+	provider, ok := s.providers[name]
+	if !ok {
+		return nil, ErrOAuthProviderNotFound
+	}
+	return provider, nil
+}
+
+func (s *OAuthService) ListProviders() []response.OAuthProviderResponse {
+	providers := make([]response.OAuthProviderResponse, 0, len(s.order))
+	for _, name := range s.order {
+		provider := s.providers[name]
+		providers = append(providers, response.OAuthProviderResponse{
+			Name:        provider.Name,
+			DisplayName: provider.DisplayName,
+			Type:        provider.Type,
+		})
+	}
+	return providers
+}
+
+// BeginAuth membuat state, nonce dan PKCE verifier. Verifier hanya disimpan di server, state dikirim ke provider
+// dan nonce disimpan di cookie browser yang memulai login (lihat oauthStateKey)
+func (s *OAuthService) BeginAuth(name string) (*response.OAuthLoginResponse, error) {
+	return s.begin(name, nil, "")
+}
+
+// BeginLink sama dengan BeginAuth tetapi callback-nya menghubungkan identitas ke user yang sedang login.
+// Callback harus dikirim dengan session yang sama (sessionID dari JWT), lihat OAuthResult.LinkSessionID.
+func (s *OAuthService) BeginLink(name string, userID uint, sessionID string) (*response.OAuthLoginResponse, error) {
+	if sessionID == "" {
+		return nil, ErrOAuthLinkSession
+	}
+	return s.begin(name, &userID, sessionID)
+}
+
+func (s *OAuthService) begin(name string, linkUserID *uint, linkSessionID string) (*response.OAuthLoginResponse, error) {
+	provider, err := s.Provider(name)
+	if err != nil {
+		return nil, err
+	}
+
+	state := utility.GenerateRandomString(32)
+	nonce := utility.GenerateRandomString(32)
+	verifier := oauth2.GenerateVerifier()
+
+	if err := s.States.Save(oauthStateKey(state, nonce), entity.OAuthState{
+		Provider:      provider.Name,
+		Verifier:      verifier,
+		LinkUserID:    linkUserID,
+		LinkSessionID: linkSessionID,
+		ExpiresAt:     time.Now().Add(OAuthStateTTL),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &response.OAuthLoginResponse{
+		RedirectURL: provider.AuthCodeURL(state, verifier),
+		State:       state,
+		Nonce:       nonce,
+	}, nil
+}
+
+// oauthStateKey menggabungkan state dengan nonce dari cookie. State yang bocor (mis. URL authorize dikirim ke
+// korban) tidak bisa dipakai atau dihabiskan dari browser lain karena key-nya juga butuh nonce.
+func oauthStateKey(state, nonce string) string {
+	return state + "." + nonce
+}
+
+// OAuthResult adalah hasil callback, LinkUserID dan LinkSessionID terisi jika alur dimulai dari BeginLink
+type OAuthResult struct {
+	User          *request.OAuthUser
+	LinkUserID    *uint
+	LinkSessionID string
+}
+
+// LinkAllowed memastikan callback link datang dari session yang memulai link, bukan dari browser lain
+func (r *OAuthResult) LinkAllowed(userID uint, sessionID string) bool {
+	return r.LinkUserID != nil && *r.LinkUserID == userID && r.LinkSessionID != "" && r.LinkSessionID == sessionID
+}
+
+// CompleteAuth memvalidasi state (sekali pakai, belum kedaluwarsa, provider sama, nonce dari browser yang sama)
+// lalu menukar code
+func (s *OAuthService) CompleteAuth(ctx context.Context, name, state, nonce, code string) (*OAuthResult, error) {
+	provider, err := s.Provider(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if nonce == "" {
+		return nil, ErrInvalidOAuthState
+	}
+	stored, err := s.States.Consume(oauthStateKey(state, nonce))
+	if err != nil {
+		return nil, err
+	}
+	if stored.Provider != provider.Name {
+		return nil, ErrInvalidOAuthState
+	}
+
+	user, err := provider.Exchange(ctx, code, stored.Verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OAuthResult{User: user, LinkUserID: stored.LinkUserID, LinkSessionID: stored.LinkSessionID}, nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/utility"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidOAuthState = errors.New("invalid or expired oauth state")
+
+// OAuthStateTTL adalah umur state dan cookie nonce OAuth
+const OAuthStateTTL = 10 * time.Minute
+
+// OAuthStateStore menyimpan state OAuth yang diterbitkan server. Consume harus atomic
+// sehingga satu state hanya bisa dipakai sekali walaupun callback dikirim paralel.
+type OAuthStateStore interface {
+	Save(state string, data entity.OAuthState) error
+	Consume(state string) (entity.OAuthState, error)
+}
+
+// NewOAuthStateStore memilih store berdasarkan driver: "db" untuk deployment multi instance, default "memory"
+func NewOAuthStateStore(driver string, db *gorm.DB) OAuthStateStore {
+	if driver == "db" {
+		return &DBOAuthStateStore{DB: db}
+	}
+	return NewMemoryOAuthStateStore()
+}
+
+// MemoryOAuthStateStore hanya berlaku untuk satu instance aplikasi
+type MemoryOAuthStateStore struct {
+	mu     sync.Mutex
+	states map[string]entity.OAuthState
+}
+
+func NewMemoryOAuthStateStore() *MemoryOAuthStateStore {
+	return &MemoryOAuthStateStore{states: make(map[string]entity.OAuthState)}
+}
+
+func (s *MemoryOAuthStateStore) Save(state string, data entity.OAuthState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, existing := range s.states {
+		if now.After(existing.ExpiresAt) {
+			delete(s.states, key)
+		}
+	}
+
+	s.states[utility.HashToken(state)] = data
+	return nil
+}
+
+func (s *MemoryOAuthStateStore) Consume(state string) (entity.OAuthState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := utility.HashToken(state)
+	data, ok := s.states[key]
+	delete(s.states, key)
+
+	if !ok || time.Now().After(data.ExpiresAt) {
+		return entity.OAuthState{}, ErrInvalidOAuthState
+	}
+	return data, nil
+}
+
+// DBOAuthStateStore menyimpan state di tabel oauth_states sehingga callback bisa diterima instance lain
+type DBOAuthStateStore struct {
+	DB *gorm.DB
+}
+
+func (s *DBOAuthStateStore) Save(state string, data entity.OAuthState) error {
+	if err := s.DB.Where("expires_at < ?", time.Now()).Delete(&entity.OAuthState{}).Error; err != nil {
+		return fmt.Errorf("error pruning oauth states: %v", err)
+	}
+
+	data.StateHash = utility.HashToken(state)
+	if err := s.DB.Create(&data).Error; err != nil {
+		return fmt.Errorf("error saving oauth state: %v", err)
+	}
+	return nil
+}
+
+func (s *DBOAuthStateStore) Consume(state string) (entity.OAuthState, error) {
+	// DELETE ... RETURNING memastikan hanya satu request yang mendapatkan row ini
+	var deleted []entity.OAuthState
+	if err := s.DB.Clauses(clause.Returning{}).
+		Where("state_hash = ?", utility.HashToken(state)).
+		Delete(&deleted).Error; err != nil {
+		return entity.OAuthState{}, fmt.Errorf("error consuming oauth state: %v", err)
+	}
+
+	if len(deleted) == 0 || time.Now().After(deleted[0].ExpiresAt) {
+		return entity.OAuthState{}, ErrInvalidOAuthState
+	}
+	return deleted[0], nil
+}
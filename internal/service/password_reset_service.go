@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/utility"
+	"net/url"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const passwordResetTTL = time.Hour
+
+var ErrInvalidResetToken = errors.New("reset token is invalid or has expired")
+
+// ForgotPassword mengirim link reset password jika email terdaftar.
+// Tidak mengembalikan error ketika email tidak ditemukan agar tidak membocorkan akun yang terdaftar.
+func (s *UserService) ForgotPassword(email string) error {
+	var user entity.User
+	if err := s.DB.Where("email = ?", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logrus.Infof("Password reset requested for unknown email")
+			return nil
+		}
+		return fmt.Errorf("error getting user: %v", err)
+	}
+
+	return s.sendPasswordReset(s.DB, &user)
+}
+
+// sendPasswordReset membuat token reset baru dan mengirim link-nya ke email user
+func (s *UserService) sendPasswordReset(tx *gorm.DB, user *entity.User) error {
+	token := utility.GenerateSecureToken()
+	reset := entity.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: utility.HashToken(token),
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}
+
+	if err := tx.Create(&reset).Error; err != nil {
+		return fmt.Errorf("error creating password reset: %v", err)
+	}
+
+	link := fmt.Sprintf("%s/reset-password?token=%s", s.AppURL, url.QueryEscape(token))
+	body := fmt.Sprintf("Hi %s,\n\n"+
+		"We received a request to reset your password. Open the link below to choose a new password:\n\n"+
+		"%s\n\n"+
+		"This link expires in %d minutes and can only be used once. "+
+		"If you did not request a password reset, you can ignore this email.\n",
+		user.Name, link, int(passwordResetTTL.Minutes()))
+
+	if err := s.EmailSender.Send(utility.EmailMessage{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body:    body,
+	}); err != nil {
+		return fmt.Errorf("error sending password reset email: %v", err)
+	}
+
+	return nil
+}
+
+// ResetPassword mengganti password memakai token reset yang masih berlaku dan mencabut semua session user
+func (s *UserService) ResetPassword(token, newPassword string) error {
+	if err := s.validatePassword(newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := utility.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %v", err)
+	}
+
+	tokenHash := utility.HashToken(token)
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		// klaim token dengan satu UPDATE bersyarat, dari request bersamaan dengan token yang sama hanya satu yang lolos
+		now := time.Now()
+		claim := tx.Model(&entity.PasswordReset{}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+			Update("used_at", now)
+		if claim.Error != nil {
+			return fmt.Errorf("error claiming reset token: %v", claim.Error)
+		}
+		if claim.RowsAffected == 0 {
+			return ErrInvalidResetToken
+		}
+
+		var reset entity.PasswordReset
+		if err := tx.Where("token_hash = ?", tokenHash).First(&reset).Error; err != nil {
+			return fmt.Errorf("error getting password reset: %v", err)
+		}
+
+		if err := tx.Model(&entity.User{}).Where("id = ?", reset.UserID).
+			Updates(map[string]interface{}{
+				"password":                hashedPassword,
+				"password_reset_required": false,
+			}).Error; err != nil {
+			return fmt.Errorf("error updating password: %v", err)
+		}
+
+		// token lain milik user ikut dinonaktifkan
+		if err := tx.Model(&entity.PasswordReset{}).
+			Where("user_id = ? AND used_at IS NULL", reset.UserID).
+			Update("used_at", now).Error; err != nil {
+			return fmt.Errorf("error invalidating reset tokens: %v", err)
+		}
+
+		return NewSessionService(tx).RevokeAllSessions(tx, reset.UserID, "")
+	})
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	maxProfilePicSize = 2 << 20 // 2 MB
+	// path publik foto profil, dilayani dari UploadDir oleh router
+	profilePicURLPrefix = "/uploads/avatars/"
+)
+
+var (
+	ErrUsernameTaken          = errors.New("username is already taken")
+	ErrInvalidCurrentPassword = errors.New("current password is incorrect")
+	ErrProfilePicTooLarge     = errors.New("profile picture must not exceed 2 MB")
+	ErrProfilePicInvalidType  = errors.New("profile picture must be a JPEG, PNG or WebP image")
+)
+
+// ekstensi file yang diizinkan berdasarkan hasil deteksi isi file, bukan nama file
+var profilePicExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+func (s *UserService) GetProfile(userID uint) (*response.ProfileResponse, error) {
+	var user entity.User
+	if err := s.DB.First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("error getting user: %v", err)
+	}
+
+	preference, err := loadUserPreference(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return toProfileResponse(&user, preference), nil
+}
+
+// UpdateProfile mengubah nama dan/atau username, username dicek unik termasuk akun yang sudah di-soft delete
+func (s *UserService) UpdateProfile(userID uint, req request.UpdateProfileRequest) (*response.ProfileResponse, error) {
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		updates["name"] = strings.TrimSpace(*req.Name)
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if req.Username != nil {
+			var count int64
+			if err := tx.Unscoped().Model(&entity.User{}).
+				Where("LOWER(username) = LOWER(?) AND id <> ?", *req.Username, userID).
+				Count(&count).Error; err != nil {
+				return fmt.Errorf("error checking username: %v", err)
+			}
+			if count > 0 {
+				return ErrUsernameTaken
+			}
+			updates["username"] = *req.Username
+		}
+
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := tx.Model(&entity.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
+			return fmt.Errorf("error updating profile: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetProfile(userID)
+}
+
+// ChangePassword mengganti password dengan verifikasi password lama dan mencabut session lain milik user.
+// Akun OAuth yang belum punya password boleh membuat password tanpa current password.
+func (s *UserService) ChangePassword(userID uint, currentSessionID string, req request.ChangePasswordRequest) error {
+	if err := s.validatePassword(req.NewPassword); err != nil {
+		return err
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		if err := tx.Select("id", "password").First(&user, userID).Error; err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		if user.Password != "" {
+			if err := utility.CompareHashAndPassword(user.Password, req.CurrentPassword); err != nil {
+				return ErrInvalidCurrentPassword
+			}
+		}
+
+		hashedPassword, err := utility.HashPassword(req.NewPassword)
+		if err != nil {
+			return fmt.Errorf("error hashing password: %v", err)
+		}
+
+		if err := tx.Model(&entity.User{}).Where("id = ?", userID).
+			Update("password", hashedPassword).Error; err != nil {
+			return fmt.Errorf("error updating password: %v", err)
+		}
+
+		recordSecurityEvent(tx, entity.SecurityEvent{
+			UserID: &userID,
+			Type:   entity.SecurityEventPasswordChanged,
+		})
+
+		return NewSessionService(tx).RevokeAllSessions(tx, userID, currentSessionID)
+	})
+}
+
+// UploadProfilePicture menyimpan foto profil ke UploadDir dan menghapus foto lama yang disimpan aplikasi
+func (s *UserService) UploadProfilePicture(userID uint, file io.Reader) (*response.ProfileResponse, error) {
+	data, err := io.ReadAll(io.LimitReader(file, maxProfilePicSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("error reading profile picture: %v", err)
+	}
+	if len(data) > maxProfilePicSize {
+		return nil, ErrProfilePicTooLarge
+	}
+
+	ext, ok := profilePicExtensions[http.DetectContentType(data)]
+	if !ok {
+		return nil, ErrProfilePicInvalidType
+	}
+
+	var user entity.User
+	if err := s.DB.Select("id", "profile_pic").First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("error getting user: %v", err)
+	}
+
+	dir := filepath.Join(s.UploadDir, "avatars")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating upload directory: %v", err)
+	}
+
+	fileName := fmt.Sprintf("%d-%s%s", userID, utility.GenerateRandomString(16), ext)
+	if err := os.WriteFile(filepath.Join(dir, fileName), data, 0o644); err != nil {
+		return nil, fmt.Errorf("error saving profile picture: %v", err)
+	}
+
+	if err := s.DB.Model(&entity.User{}).Where("id = ?", userID).
+		Update("profile_pic", profilePicURLPrefix+fileName).Error; err != nil {
+		return nil, fmt.Errorf("error updating profile picture: %v", err)
+	}
+
+	// foto dari provider OAuth (URL eksternal) tidak disentuh
+	if oldFile, found := strings.CutPrefix(user.ProfilePic, profilePicURLPrefix); found {
+		if err := os.Remove(filepath.Join(dir, filepath.Base(oldFile))); err != nil && !os.IsNotExist(err) {
+			logrus.Warnf("Failed to remove old profile picture: %v", err)
+		}
+	}
+
+	return s.GetProfile(userID)
+}
+
+func (s *UserService) GetPreferences(userID uint) (*response.PreferenceResponse, error) {
+	preference, err := loadUserPreference(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := toPreferenceResponse(preference)
+	return &result, nil
+}
+
+// UpdatePreferences menyimpan preferensi, field yang tidak dikirim tetap memakai nilai lama atau default
+func (s *UserService) UpdatePreferences(userID uint, req request.UpdatePreferencesRequest) (*response.PreferenceResponse, error) {
+	preference, err := loadUserPreference(s.DB, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.BaseCurrency != nil {
+		preference.BaseCurrency = *req.BaseCurrency
+	}
+	if req.Timezone != nil {
+		preference.Timezone = *req.Timezone
+	}
+	if req.Locale != nil {
+		preference.Locale = *req.Locale
+	}
+	if req.FirstDayOfWeek != nil {
+		preference.FirstDayOfWeek = *req.FirstDayOfWeek
+	}
+	if req.NumberFormat != nil {
+		preference.NumberFormat = *req.NumberFormat
+	}
+	if req.CycleStartDay != nil {
+		preference.CycleStartDay = *req.CycleStartDay
+	}
+
+	if err := s.DB.Save(&preference).Error; err != nil {
+		return nil, fmt.Errorf("error saving preferences: %v", err)
+	}
+
+	result := toPreferenceResponse(preference)
+	return &result, nil
+}
+
+// loadUserPreference mengambil preferensi user, default jika user belum pernah menyimpan preferensi
+func loadUserPreference(db *gorm.DB, userID uint) (entity.UserPreference, error) {
+	var preference entity.UserPreference
+	err := db.Where("user_id = ?", userID).First(&preference).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.DefaultUserPreference(userID), nil
+	}
+	if err != nil {
+		return preference, fmt.Errorf("error getting preferences: %v", err)
+	}
+	return preference, nil
+}
+
+func toPreferenceResponse(preference entity.UserPreference) response.PreferenceResponse {
+	return response.PreferenceResponse{
+		BaseCurrency:   preference.BaseCurrency,
+		Timezone:       preference.Timezone,
+		Locale:         preference.Locale,
+		FirstDayOfWeek: preference.FirstDayOfWeek,
+		NumberFormat:   preference.NumberFormat,
+		CycleStartDay:  preference.CycleStartDay,
+	}
+}
+
+func toProfileResponse(user *entity.User, preference entity.UserPreference) *response.ProfileResponse {
+	return &response.ProfileResponse{
+		ID:                   user.ID,
+		Name:                 user.Name,
+		Username:             user.Username,
+		Email:                user.Email,
+		EmailVerified:        user.EmailVerified,
+		ProfilePic:           user.ProfilePic,
+		Role:                 user.Role,
+		HasPassword:          user.Password != "",
+		TwoFactorEnabled:     user.TwoFactorEnabled,
+		Preferences:          toPreferenceResponse(preference),
+		CreatedAt:            user.CreatedAt,
+		DeletionScheduledFor: user.DeletionScheduledFor,
+	}
+}
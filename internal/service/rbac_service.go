@@ -0,0 +1,180 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/response"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// permission untuk endpoint admin
+const (
+	PermissionUsersRead   = "users:read"
+	PermissionUsersManage = "users:manage"
+	PermissionRolesAssign = "roles:assign"
+	PermissionStatsRead   = "stats:read"
+)
+
+// RolePermissions memetakan role ke permission yang dimiliki, role user biasa tidak punya permission admin
+var RolePermissions = map[string][]string{
+	entity.RoleUser:            {},
+	entity.RoleSupportReadonly: {PermissionUsersRead, PermissionStatsRead},
+	entity.RoleAdmin:           {PermissionUsersRead, PermissionUsersManage, PermissionStatsRead},
+	entity.RoleSuperadmin:      {PermissionUsersRead, PermissionUsersManage, PermissionStatsRead, PermissionRolesAssign},
+}
+
+// urutan role untuk ditampilkan
+var roleOrder = []string{entity.RoleUser, entity.RoleSupportReadonly, entity.RoleAdmin, entity.RoleSuperadmin}
+
+// role di-cache sebentar agar middleware tidak query setiap request, perubahan role tetap berlaku maksimal setelah TTL ini
+const roleCacheTTL = 30 * time.Second
+
+var (
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrLastSuperadmin     = errors.New("cannot change the role of the last superadmin")
+	ErrInsufficientRole   = errors.New("only a superadmin can manage staff accounts")
+	ErrPermissionRequired = errors.New("access denied: missing required permission")
+)
+
+func RoleHasPermission(role, permission string) bool {
+	for _, p := range RolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStaffRole true jika role memiliki akses ke dashboard admin
+func IsStaffRole(role string) bool {
+	return len(RolePermissions[role]) > 0
+}
+
+type cachedRole struct {
+	role      string
+	expiresAt time.Time
+}
+
+type RBACService struct {
+	DB *gorm.DB
+
+	mu    sync.Mutex
+	cache map[uint]cachedRole
+}
+
+func NewRBACService(db *gorm.DB) *RBACService {
+	return &RBACService{
+		DB:    db,
+		cache: make(map[uint]cachedRole),
+	}
+}
+
+// GetRole mengambil role user dari cache atau database
+func (s *RBACService) GetRole(userID uint) (string, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[userID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.role, nil
+	}
+
+	var user entity.User
+	if err := s.DB.Select("id", "role").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrUserNotFound
+		}
+		return "", fmt.Errorf("error getting user role: %v", err)
+	}
+
+	s.mu.Lock()
+	s.cache[userID] = cachedRole{role: user.Role, expiresAt: now.Add(roleCacheTTL)}
+	s.mu.Unlock()
+
+	return user.Role, nil
+}
+
+func (s *RBACService) HasPermission(userID uint, permission string) (bool, error) {
+	role, err := s.GetRole(userID)
+	if err != nil {
+		return false, err
+	}
+	return RoleHasPermission(role, permission), nil
+}
+
+func (s *RBACService) invalidate(userID uint) {
+	s.mu.Lock()
+	delete(s.cache, userID)
+	s.mu.Unlock()
+}
+
+// AssignRole mengganti role user, langsung berlaku tanpa perlu login ulang
+func (s *RBACService) AssignRole(actorID, userID uint, role string) error {
+	if _, ok := RolePermissions[role]; !ok {
+		return ErrInvalidRole
+	}
+	if actorID == userID {
+		return ErrCannotModifySelf
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		if user.Role == role {
+			return nil
+		}
+
+		// minimal harus ada satu superadmin yang bisa mengatur role. Baris superadmin dikunci (COUNT tidak bisa
+		// FOR UPDATE) agar dua penurunan role bersamaan tidak sama-sama melihat 2 superadmin
+		if user.Role == entity.RoleSuperadmin {
+			var superadmins []uint
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(&entity.User{}).
+				Where("role = ?", entity.RoleSuperadmin).Pluck("id", &superadmins).Error; err != nil {
+				return fmt.Errorf("error counting superadmins: %v", err)
+			}
+			if len(superadmins) <= 1 {
+				return ErrLastSuperadmin
+			}
+		}
+
+		if err := tx.Model(&user).Update("role", role).Error; err != nil {
+			return fmt.Errorf("error updating user role: %v", err)
+		}
+
+		recordSecurityEvent(tx, entity.SecurityEvent{
+			UserID:  &userID,
+			Type:    entity.SecurityEventRoleChanged,
+			Details: fmt.Sprintf("changed_by=%d from=%s to=%s", actorID, user.Role, role),
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidate(userID)
+	return nil
+}
+
+func (s *RBACService) ListRoles() []response.RoleResponse {
+	roles := make([]response.RoleResponse, len(roleOrder))
+	for i, role := range roleOrder {
+		roles[i] = response.RoleResponse{
+			Name:        role,
+			Permissions: RolePermissions[role],
+		}
+	}
+	return roles
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// interval minimum update last_used_at agar tidak menulis ke DB setiap request
+const sessionTouchInterval = time.Minute
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrSessionInvalid  = errors.New("session has been revoked or expired")
+)
+
+type SessionService struct {
+	DB *gorm.DB
+}
+
+func NewSessionService(db *gorm.DB) *SessionService {
+	return &SessionService{DB: db}
+}
+
+// CreateSession membuat session baru untuk device yang login
+func (s *SessionService) CreateSession(tx *gorm.DB, userID uint, client request.ClientInfo) (*entity.Session, error) {
+	if tx == nil {
+		tx = s.DB
+	}
+
+	now := time.Now()
+	session := entity.Session{
+		UserID:     userID,
+		TokenID:    uuid.New().String(),
+		UserAgent:  truncate(client.UserAgent, 512),
+		IPAddress:  truncate(client.IPAddress, 64),
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(utility.TokenTTL),
+	}
+
+	if err := tx.Create(&session).Error; err != nil {
+		return nil, fmt.Errorf("error creating session: %v", err)
+	}
+
+	return &session, nil
+}
+
+// ValidateSession memastikan session dari token masih aktif dan memperbarui waktu terakhir dipakai
+func (s *SessionService) ValidateSession(tokenID string, userID uint) (*entity.Session, error) {
+	var session entity.Session
+	if err := s.DB.Where("token_id = ? AND user_id = ?", tokenID, userID).First(&session).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionInvalid
+		}
+		return nil, fmt.Errorf("error getting session: %v", err)
+	}
+
+	now := time.Now()
+	if !session.IsActive(now) {
+		return nil, ErrSessionInvalid
+	}
+
+	if now.Sub(session.LastUsedAt) >= sessionTouchInterval {
+		if err := s.DB.Model(&session).UpdateColumn("last_used_at", now).Error; err != nil {
+			return nil, fmt.Errorf("error updating session: %v", err)
+		}
+	}
+
+	return &session, nil
+}
+
+func (s *SessionService) ListSessions(userID uint, currentTokenID string) ([]response.SessionResponse, error) {
+	var sessions []entity.Session
+	if err := s.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, errors.New("failed to get sessions")
+	}
+
+	sessionResponses := make([]response.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		sessionResponses[i] = response.SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastUsedAt: session.LastUsedAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.TokenID == currentTokenID,
+		}
+	}
+
+	return sessionResponses, nil
+}
+
+func (s *SessionService) RevokeSession(userID uint, sessionID uint) error {
+	result := s.DB.Model(&entity.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return errors.New("failed to revoke session")
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (s *SessionService) RevokeSessionByTokenID(tokenID string) error {
+	if err := s.DB.Model(&entity.Session{}).
+		Where("token_id = ? AND revoked_at IS NULL", tokenID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return errors.New("failed to revoke session")
+	}
+
+	return nil
+}
+
+// RevokeAllSessions mencabut semua session user, kecuali exceptTokenID jika diisi
+func (s *SessionService) RevokeAllSessions(tx *gorm.DB, userID uint, exceptTokenID string) error {
+	if tx == nil {
+		tx = s.DB
+	}
+
+	query := tx.Model(&entity.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptTokenID != "" {
+		query = query.Where("token_id <> ?", exceptTokenID)
+	}
+
+	if err := query.Update("revoked_at", time.Now()).Error; err != nil {
+		return fmt.Errorf("error revoking sessions: %v", err)
+	}
+
+	return nil
+}
+
+func truncate(value string, max int) string {
+	if len(value) > max {
+		return value[:max]
+	}
+	return value
+}
@@ -14,10 +14,12 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/xuri/excelize/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TransactionService struct {
 	DB              *gorm.DB
+	Events          *WorkspaceEvents // opsional, dikabari setiap kali transaksi workspace berubah
 	transactionUtil *utility.TransactionUtil
 }
 
@@ -28,12 +30,23 @@ func NewTransactionService(db *gorm.DB) *TransactionService {
 	}
 }
 
-func (s *TransactionService) GetTransactionByUser(userID uint, filter request.TransactionFilter) (*response.TransactionListResponse, error) {
+// GetTransactionByUser mengambil transaksi pada workspace aktif, termasuk yang dicatat member lain
+func (s *TransactionService) GetTransactionByUser(scope request.WorkspaceScope, filter request.TransactionFilter) (*response.TransactionListResponse, error) {
 	logrus.Infof("Applying filter: %+v", filter) // debug
 
+	if filter.Month != "" {
+		preference, err := loadUserPreference(s.DB, scope.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if err := applyCycleMonth(&filter, preference); err != nil {
+			return nil, err
+		}
+	}
+
 	var transactions []entity.Transaction
 
-	baseQuery := s.DB.Where("user_id = ?", userID)
+	baseQuery := s.DB.Where("workspace_id = ?", scope.WorkspaceID)
 
 	filteredQuery := s.transactionUtil.BuildFilterQuery(baseQuery, filter)
 
@@ -44,7 +57,7 @@ func (s *TransactionService) GetTransactionByUser(userID uint, filter request.Tr
 		return nil, errors.New("failed to count transaction")
 	}
 
-	summary, err := s.transactionUtil.CalculateTransactionSummary(filteredQuery, filter)
+	summary, err := s.transactionUtil.CalculateTransactionSummary(scope.WorkspaceID, filter)
 	if err != nil {
 		logrus.Errorf("Failed to calculate transaction summary: %v", err)
 		return nil, err
@@ -61,6 +74,12 @@ func (s *TransactionService) GetTransactionByUser(userID uint, filter request.Tr
 		return nil, errors.New("failed to get transactions")
 	}
 
+	anomalies, err := loadTransactionAnomalies(s.DB, transactions)
+	if err != nil {
+		logrus.Errorf("Failed to get transaction anomalies: %v", err)
+		return nil, errors.New("failed to get transactions")
+	}
+
 	// transform ke response format
 	transactionResponses := make([]response.TransactionResponse, len(transactions))
 	for i, tx := range transactions {
@@ -72,8 +91,10 @@ func (s *TransactionService) GetTransactionByUser(userID uint, filter request.Tr
 			Type:        tx.Type,
 			Description: tx.Description,
 			Date:        tx.Date,
+			CreatedBy:   tx.UserID,
 			CreatedAt:   tx.CreatedAt,
 			UpdatedAt:   tx.UpdatedAt,
+			Anomalies:   anomalies[tx.ID],
 		}
 	}
 
@@ -89,12 +110,17 @@ func (s *TransactionService) GetTransactionByUser(userID uint, filter request.Tr
 	}, nil
 }
 
-func (s *TransactionService) CreateTransaction(userID uint, req request.CreateTransactionRequest) (*response.TransactionResponse, error) {
-	// validasi category
+func (s *TransactionService) CreateTransaction(scope request.WorkspaceScope, req request.CreateTransactionRequest) (*response.TransactionResponse, error) {
+	authorizer := NewAuthorizer(s.DB)
+	if err := authorizer.CanWrite(scope, ResourceTransaction); err != nil {
+		return nil, err
+	}
+
+	// validasi category, harus berada di workspace yang sama dengan transaksi
 	var category entity.Category
-	if err := s.DB.First(&category, req.CategoryID).Error; err != nil {
-		logrus.Errorf("category not found: %v", err)
-		return nil, errors.New("category not found")
+	if err := authorizer.FindOwned(scope, ResourceCategory, &category, req.CategoryID); err != nil {
+		logrus.Errorf("Error getting category: %v", err)
+		return nil, err
 	}
 
 	date, err := time.Parse("2006-01-02", req.Date)
@@ -104,7 +130,8 @@ func (s *TransactionService) CreateTransaction(userID uint, req request.CreateTr
 	}
 
 	transaction := entity.Transaction{
-		UserID:      userID,
+		WorkspaceID: scope.WorkspaceID,
+		UserID:      scope.UserID,
 		CategoryID:  req.CategoryID,
 		Amount:      req.Amount,
 		Type:        req.Type,
@@ -112,10 +139,17 @@ func (s *TransactionService) CreateTransaction(userID uint, req request.CreateTr
 		Date:        date,
 	}
 
-	if err := s.DB.Create(&transaction).Error; err != nil {
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&transaction).Error; err != nil {
+			return err
+		}
+		return utility.ApplyTransactionAggregate(tx, &transaction, 1)
+	})
+	if err != nil {
 		logrus.Errorf("Error creating transaction: %v", err)
 		return nil, errors.New("failed to create transaction")
 	}
+	s.Events.Publish(scope.WorkspaceID)
 
 	return &response.TransactionResponse{
 		ID:          transaction.ID,
@@ -125,26 +159,25 @@ func (s *TransactionService) CreateTransaction(userID uint, req request.CreateTr
 		Type:        transaction.Type,
 		Description: transaction.Description,
 		Date:        transaction.Date,
+		CreatedBy:   transaction.UserID,
 		CreatedAt:   transaction.CreatedAt,
 		UpdatedAt:   transaction.UpdatedAt,
 	}, nil
 }
 
-func (s *TransactionService) UpdateTransaction(userID uint, transactionID uint, req request.UpdateTransactionRequest) (*response.TransactionResponse, error) {
+func (s *TransactionService) UpdateTransaction(scope request.WorkspaceScope, transactionID uint, req request.UpdateTransactionRequest) (*response.TransactionResponse, error) {
+	authorizer := NewAuthorizer(s.DB)
+
 	var transaction entity.Transaction
-	if err := s.DB.Where("id = ? AND user_id = ?", transactionID, userID).
-		First(&transaction).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("transaction not found")
-		}
+	if err := authorizer.FindOwnedForWrite(scope, ResourceTransaction, &transaction, transactionID); err != nil {
 		logrus.Errorf("Error getting transaction: %v", err)
-		return nil, errors.New("failed to get transaction")
+		return nil, err
 	}
 
 	var category entity.Category
-	if err := s.DB.First(&category, req.CategoryID).Error; err != nil {
-		logrus.Errorf("Error category not found: %v", err)
-		return nil, errors.New("category not found")
+	if err := authorizer.FindOwned(scope, ResourceCategory, &category, req.CategoryID); err != nil {
+		logrus.Errorf("Error getting category: %v", err)
+		return nil, err
 	}
 
 	date, err := time.Parse("2006-01-02", req.Date)
@@ -159,10 +192,25 @@ func (s *TransactionService) UpdateTransaction(userID uint, transactionID uint,
 	transaction.Description = req.Description
 	transaction.Date = date
 
-	if err := s.DB.Save(&transaction).Error; err != nil {
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		// nilai lama dibaca ulang dengan lock agar agregat tidak terkurangi dua kali oleh update bersamaan
+		var previous entity.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&previous, transaction.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(&transaction).Error; err != nil {
+			return err
+		}
+		if err := utility.ApplyTransactionAggregate(tx, &previous, -1); err != nil {
+			return err
+		}
+		return utility.ApplyTransactionAggregate(tx, &transaction, 1)
+	})
+	if err != nil {
 		logrus.Errorf("Error update transaction: %v", err)
 		return nil, errors.New("failed to update transaction")
 	}
+	s.Events.Publish(scope.WorkspaceID)
 
 	return &response.TransactionResponse{
 		ID:          transaction.ID,
@@ -172,27 +220,117 @@ func (s *TransactionService) UpdateTransaction(userID uint, transactionID uint,
 		Type:        transaction.Type,
 		Description: transaction.Description,
 		Date:        transaction.Date,
+		CreatedBy:   transaction.UserID,
 		CreatedAt:   transaction.CreatedAt,
 		UpdatedAt:   transaction.UpdatedAt,
 	}, nil
 }
 
-func (s *TransactionService) DeleteTransaction(userID uint, transactionID uint) error {
-	result := s.DB.Where("id = ? AND user_id = ?", transactionID, userID).Delete(&entity.Transaction{})
-	if result.Error != nil {
-		logrus.Errorf("Error to delete transaction: %v", result.Error)
-		return errors.New("failed to delete transaction")
+func (s *TransactionService) DeleteTransaction(scope request.WorkspaceScope, transactionID uint) error {
+	if err := NewAuthorizer(s.DB).CanWrite(scope, ResourceTransaction); err != nil {
+		return err
 	}
 
-	if result.RowsAffected == 0 {
-		return errors.New("transaction not found")
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var transaction entity.Transaction
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND workspace_id = ?", transactionID, scope.WorkspaceID).
+			First(&transaction).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&transaction).Error; err != nil {
+			return err
+		}
+		return utility.ApplyTransactionAggregate(tx, &transaction, -1)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return resourceNotFound(ResourceTransaction)
 	}
+	if err != nil {
+		logrus.Errorf("Error to delete transaction: %v", err)
+		return errors.New("failed to delete transaction")
+	}
+	s.Events.Publish(scope.WorkspaceID)
 
 	return nil
 }
 
-func (s *TransactionService) ExportTransactionsExcel(userID uint, filter request.TransactionFilter) (*bytes.Buffer, error) {
-	transactions, err := s.GetTransactionByUser(userID, filter)
+// RestoreTransaction mengembalikan transaksi yang sudah dihapus di workspace aktif, kategorinya harus masih ada
+func (s *TransactionService) RestoreTransaction(scope request.WorkspaceScope, transactionID uint) (*response.TransactionResponse, error) {
+	authorizer := NewAuthorizer(s.DB)
+	if err := authorizer.CanWrite(scope, ResourceTransaction); err != nil {
+		return nil, err
+	}
+
+	var transaction entity.Transaction
+	if err := s.DB.Unscoped().
+		Where("id = ? AND workspace_id = ? AND deleted_at IS NOT NULL", transactionID, scope.WorkspaceID).
+		First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, resourceNotFound(ResourceTransaction)
+		}
+		logrus.Errorf("Error getting deleted transaction: %v", err)
+		return nil, errors.New("failed to restore transaction")
+	}
+
+	var category entity.Category
+	if err := authorizer.FindOwned(scope, ResourceCategory, &category, transaction.CategoryID); err != nil {
+		return nil, err
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		// hanya baris yang masih terhapus yang dipulihkan, restore bersamaan tidak menambah agregat dua kali
+		result := tx.Unscoped().Model(&entity.Transaction{}).
+			Where("id = ? AND deleted_at IS NOT NULL", transaction.ID).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return utility.ApplyTransactionAggregate(tx, &transaction, 1)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, resourceNotFound(ResourceTransaction)
+	}
+	if err != nil {
+		logrus.Errorf("Error restoring transaction: %v", err)
+		return nil, errors.New("failed to restore transaction")
+	}
+	s.Events.Publish(scope.WorkspaceID)
+
+	return &response.TransactionResponse{
+		ID:          transaction.ID,
+		CategoryID:  transaction.CategoryID,
+		Category:    category.Name,
+		Amount:      transaction.Amount,
+		Type:        transaction.Type,
+		Description: transaction.Description,
+		Date:        transaction.Date,
+		CreatedBy:   transaction.UserID,
+		CreatedAt:   transaction.CreatedAt,
+		UpdatedAt:   transaction.UpdatedAt,
+	}, nil
+}
+
+// ExportTransactionsExcel membuat file Excel transaksi. Jika filter.Month diisi, laporan mencakup satu siklus
+// bulanan sesuai tanggal mulai siklus user, mis. 25 Agu - 24 Sep untuk month 2024-08 dan siklus tanggal 25.
+func (s *TransactionService) ExportTransactionsExcel(scope request.WorkspaceScope, filter request.TransactionFilter) (*bytes.Buffer, error) {
+	preference, err := loadUserPreference(s.DB, scope.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var reportPeriod string
+	if filter.Month != "" {
+		if err := applyCycleMonth(&filter, preference); err != nil {
+			return nil, err
+		}
+		reportPeriod = fmt.Sprintf("%s - %s", filter.StartDate, filter.EndDate)
+	}
+
+	transactions, err := s.GetTransactionByUser(scope, filter)
 	if err != nil {
 		logrus.Errorf("Error getting transactions: %v", err)
 		return nil, err
@@ -210,7 +348,7 @@ func (s *TransactionService) ExportTransactionsExcel(userID uint, filter request
 	f.SetActiveSheet(index)
 
 	// Set header
-	headers := []string{"Date", "Type", "Category", "Amount", "Description"}
+	headers := []string{"Date", "Type", "Category", fmt.Sprintf("Amount (%s)", preference.BaseCurrency), "Description"}
 	for i, header := range headers {
 		cell := fmt.Sprintf("%s1", string(rune('A'+i)))
 		if err := f.SetCellValue(sheet, cell, header); err != nil {
@@ -248,10 +386,17 @@ func (s *TransactionService) ExportTransactionsExcel(userID uint, filter request
 	f.SetCellValue(sheet, fmt.Sprintf("C%d", summaryRow+1), transactions.Summary.TotalExpense)
 	f.SetCellValue(sheet, fmt.Sprintf("B%d", summaryRow+2), "Saldo")
 	f.SetCellValue(sheet, fmt.Sprintf("C%d", summaryRow+2), transactions.Summary.Balance)
+	f.SetCellValue(sheet, fmt.Sprintf("A%d", summaryRow+4), "Exported at")
+	f.SetCellValue(sheet, fmt.Sprintf("B%d", summaryRow+4), time.Now().In(preference.Location()).Format("2006-01-02 15:04 MST"))
+	if reportPeriod != "" {
+		f.SetCellValue(sheet, fmt.Sprintf("A%d", summaryRow+5), "Period")
+		f.SetCellValue(sheet, fmt.Sprintf("B%d", summaryRow+5), reportPeriod)
+	}
 
-	// Styling
+	// Styling, nominal memakai mata uang dasar user
+	currencyFormat := fmt.Sprintf(`#,##0.00 "%s"`, preference.BaseCurrency)
 	if style, err := f.NewStyle(&excelize.Style{
-		NumFmt: 44, // Format currency
+		CustomNumFmt: &currencyFormat,
 	}); err == nil {
 		// Set style untuk kolom amount dan summary
 		for i := 2; i <= len(transactions.Transactions)+1; i++ {
@@ -270,3 +415,15 @@ func (s *TransactionService) ExportTransactionsExcel(userID uint, filter request
 
 	return buffer, nil
 }
+
+// applyCycleMonth mengganti filter.Month dengan rentang tanggal siklus bulanan user
+func applyCycleMonth(filter *request.TransactionFilter, preference entity.UserPreference) error {
+	start, end, err := utility.CycleMonth(filter.Month, preference.CycleStartDay)
+	if err != nil {
+		return err
+	}
+	filter.StartDate = start.Format("2006-01-02")
+	filter.EndDate = end.Format("2006-01-02")
+	filter.Month = ""
+	return nil
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	twoFactorIssuer   = "FinTrack"
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotSetup       = errors.New("two-factor authentication setup has not been started")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor authentication code")
+)
+
+// SetupTwoFactor membuat secret TOTP baru yang belum aktif sampai dikonfirmasi dengan kode dari authenticator
+func (s *UserService) SetupTwoFactor(userID uint) (*response.TwoFactorSetupResponse, error) {
+	var user entity.User
+	if err := s.DB.First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("error getting user: %v", err)
+	}
+
+	if user.TwoFactorEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret := utility.GenerateTOTPSecret()
+	if err := s.DB.Model(&user).Updates(map[string]interface{}{
+		"two_factor_secret":    secret,
+		"two_factor_last_step": 0,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("error saving two-factor secret: %v", err)
+	}
+
+	uri := utility.BuildOTPAuthURI(twoFactorIssuer, user.Email, secret)
+	qrCode, err := utility.EncodeQRCodePNG(uri, 6)
+	if err != nil {
+		return nil, fmt.Errorf("error generating QR code: %v", err)
+	}
+
+	return &response.TwoFactorSetupResponse{
+		Secret:     secret,
+		OTPAuthURI: uri,
+		QRCodePNG:  base64.StdEncoding.EncodeToString(qrCode),
+	}, nil
+}
+
+// ConfirmTwoFactor mengaktifkan 2FA dan mengembalikan recovery code, hanya ditampilkan sekali
+func (s *UserService) ConfirmTwoFactor(userID uint, code string) ([]string, error) {
+	var recoveryCodes []string
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		if user.TwoFactorEnabled {
+			return ErrTwoFactorAlreadyEnabled
+		}
+		if user.TwoFactorSecret == "" {
+			return ErrTwoFactorNotSetup
+		}
+
+		step, ok := utility.ValidateTOTPCode(user.TwoFactorSecret, code, time.Now())
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"two_factor_enabled":   true,
+			"two_factor_last_step": step,
+		}).Error; err != nil {
+			return fmt.Errorf("error enabling two-factor: %v", err)
+		}
+
+		codes, err := s.replaceRecoveryCodes(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		recoveryCodes = codes
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// DisableTwoFactor menonaktifkan 2FA, membutuhkan kode TOTP atau recovery code yang valid
+func (s *UserService) DisableTwoFactor(userID uint, code string) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var user entity.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		if !user.TwoFactorEnabled {
+			return ErrTwoFactorNotEnabled
+		}
+
+		if err := s.verifySecondFactor(tx, &user, code); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"two_factor_enabled":   false,
+			"two_factor_secret":    "",
+			"two_factor_last_step": 0,
+		}).Error; err != nil {
+			return fmt.Errorf("error disabling two-factor: %v", err)
+		}
+
+		if err := tx.Where("user_id = ?", user.ID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+			return fmt.Errorf("error deleting recovery codes: %v", err)
+		}
+
+		return nil
+	})
+}
+
+// VerifyTwoFactorLogin adalah langkah kedua login, menukar challenge token dan kode 2FA menjadi JWT
+func (s *UserService) VerifyTwoFactorLogin(challengeToken, code string, client request.ClientInfo) (string, *entity.User, error) {
+	userID, err := utility.ParseChallengeJWT(challengeToken)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := s.checkLoginGuard(ipAttemptKey(client.IPAddress), accountAttemptKey(userID)); err != nil {
+		return "", nil, err
+	}
+
+	var user entity.User
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&user, userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return utility.ErrInvalidChallengeToken
+			}
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		if !user.TwoFactorEnabled {
+			return utility.ErrInvalidChallengeToken
+		}
+
+		return s.verifySecondFactor(tx, &user, code)
+	})
+	if err == ErrInvalidTwoFactorCode {
+		// kode 2FA yang salah dihitung sebagai gagal login agar tidak bisa di-brute force
+		if err := s.registerLoginFailure(accountAttemptKey(userID), &userID, client); err != nil {
+			return "", nil, err
+		}
+		return "", nil, ErrInvalidTwoFactorCode
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := s.registerLoginSuccess(accountAttemptKey(user.ID)); err != nil {
+		return "", nil, err
+	}
+
+	token, err := s.IssueToken(&user, client)
+	if err != nil {
+		return "", nil, fmt.Errorf("internal server error during login: %v", err)
+	}
+
+	return token, &user, nil
+}
+
+// verifySecondFactor menerima kode TOTP yang belum pernah dipakai atau recovery code yang belum terpakai
+func (s *UserService) verifySecondFactor(tx *gorm.DB, user *entity.User, code string) error {
+	if step, ok := utility.ValidateTOTPCode(user.TwoFactorSecret, code, time.Now()); ok {
+		// update bersyarat agar kode yang sama tidak bisa dipakai dua kali
+		result := tx.Model(&entity.User{}).
+			Where("id = ? AND two_factor_last_step < ?", user.ID, step).
+			Update("two_factor_last_step", step)
+		if result.Error != nil {
+			return fmt.Errorf("error updating two-factor step: %v", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	result := tx.Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, utility.HashToken(utility.NormalizeRecoveryCode(code))).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("error using recovery code: %v", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+func (s *UserService) replaceRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+		return nil, fmt.Errorf("error deleting recovery codes: %v", err)
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	records := make([]entity.RecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		codes[i] = utility.GenerateRecoveryCode()
+		records[i] = entity.RecoveryCode{
+			UserID:   userID,
+			CodeHash: utility.HashToken(utility.NormalizeRecoveryCode(codes[i])),
+		}
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, fmt.Errorf("error creating recovery codes: %v", err)
+	}
+
+	return codes, nil
+}
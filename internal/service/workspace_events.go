@@ -0,0 +1,36 @@
+package service
+
+import "sync"
+
+// WorkspaceEvents menyebarkan kabar bahwa transaksi atau kategori sebuah workspace berubah,
+// dipakai antara lain untuk menghapus cache dashboard workspace tersebut
+type WorkspaceEvents struct {
+	mu        sync.RWMutex
+	listeners []func(workspaceID uint)
+}
+
+func NewWorkspaceEvents() *WorkspaceEvents {
+	return &WorkspaceEvents{}
+}
+
+// Subscribe mendaftarkan listener, dipanggil secara sinkron setelah perubahan berhasil disimpan
+func (e *WorkspaceEvents) Subscribe(listener func(workspaceID uint)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.listeners = append(e.listeners, listener)
+}
+
+// Publish memberi tahu semua listener, aman dipanggil pada nil sehingga service tanpa events tetap berjalan
+func (e *WorkspaceEvents) Publish(workspaceID uint) {
+	if e == nil {
+		return
+	}
+
+	e.mu.RLock()
+	listeners := e.listeners
+	e.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(workspaceID)
+	}
+}
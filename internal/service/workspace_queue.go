@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// workspaceQueue mengumpulkan workspace yang datanya berubah untuk diproses ulang worker di background
+type workspaceQueue struct {
+	mu    sync.Mutex
+	dirty map[uint]bool
+	wake  chan struct{}
+}
+
+func newWorkspaceQueue(events *WorkspaceEvents) *workspaceQueue {
+	q := &workspaceQueue{
+		dirty: make(map[uint]bool),
+		wake:  make(chan struct{}, 1),
+	}
+	if events != nil {
+		events.Subscribe(q.mark)
+	}
+	return q
+}
+
+// mark menandai workspace lalu membangunkan worker tanpa menunggu interval berikutnya
+func (q *workspaceQueue) mark(workspaceID uint) {
+	q.mu.Lock()
+	q.dirty[workspaceID] = true
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// drain mengambil dan mengosongkan workspace yang ditandai
+func (q *workspaceQueue) drain() []uint {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	workspaceIDs := make([]uint, 0, len(q.dirty))
+	for workspaceID := range q.dirty {
+		workspaceIDs = append(workspaceIDs, workspaceID)
+	}
+	q.dirty = make(map[uint]bool)
+	return workspaceIDs
+}
+
+// run memanggil process setiap interval atau saat ada workspace ditandai sampai ctx selesai
+func (q *workspaceQueue) run(ctx context.Context, interval time.Duration, process func(now time.Time)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		process(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// mergeWorkspaceIDs menggabungkan daftar workspace tanpa duplikat, urutan pertama dipertahankan
+func mergeWorkspaceIDs(lists ...[]uint) []uint {
+	seen := map[uint]bool{}
+	var merged []uint
+	for _, list := range lists {
+		for _, workspaceID := range list {
+			if !seen[workspaceID] {
+				seen[workspaceID] = true
+				merged = append(merged, workspaceID)
+			}
+		}
+	}
+	return merged
+}
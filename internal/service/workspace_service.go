@@ -0,0 +1,487 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/utility"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const workspaceInvitationTTL = 7 * 24 * time.Hour
+
+var (
+	ErrWorkspaceNotFound       = errors.New("workspace not found")
+	ErrWorkspaceAccessDenied   = errors.New("you are not a member of this workspace")
+	ErrWorkspaceOwnerRequired  = errors.New("only workspace owners can perform this action")
+	ErrWorkspaceReadOnly       = errors.New("viewers cannot modify workspace data")
+	ErrLastWorkspaceOwner      = errors.New("workspace must have at least one owner")
+	ErrPersonalWorkspace       = errors.New("personal workspace cannot be deleted, left or shared")
+	ErrWorkspaceMemberNotFound = errors.New("workspace member not found")
+	ErrAlreadyWorkspaceMember  = errors.New("user is already a member of this workspace")
+	ErrInvalidInvitation       = errors.New("invitation is invalid or has expired")
+	ErrInvitationEmailMismatch = errors.New("invitation was sent to a different email address")
+)
+
+type WorkspaceService struct {
+	DB          *gorm.DB
+	EmailSender utility.EmailSender
+	AppURL      string // url frontend untuk link undangan
+}
+
+func NewWorkspaceService(db *gorm.DB, emailSender utility.EmailSender, appURL string) *WorkspaceService {
+	return &WorkspaceService{DB: db, EmailSender: emailSender, AppURL: appURL}
+}
+
+// ResolveMembership mencari keanggotaan user pada workspace, workspaceID 0 berarti workspace personal
+func (s *WorkspaceService) ResolveMembership(userID, workspaceID uint) (*entity.WorkspaceMember, error) {
+	if workspaceID == 0 {
+		return s.EnsurePersonalWorkspace(userID)
+	}
+
+	member, err := s.findMember(s.DB, workspaceID, userID)
+	if err == ErrWorkspaceMemberNotFound {
+		return nil, ErrWorkspaceAccessDenied
+	}
+	return member, err
+}
+
+// EnsurePersonalWorkspace mengembalikan workspace personal user dan membuatnya jika belum ada.
+// Unique index idx_workspaces_personal_owner menolak workspace personal kedua ketika request pertama user
+// berjalan bersamaan, request yang kalah membaca ulang workspace yang dibuat request lain.
+func (s *WorkspaceService) EnsurePersonalWorkspace(userID uint) (*entity.WorkspaceMember, error) {
+	member, err := s.findPersonalWorkspace(userID)
+	if err == nil {
+		return member, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("error getting personal workspace: %v", err)
+	}
+
+	created := entity.WorkspaceMember{}
+	createErr := s.DB.Transaction(func(tx *gorm.DB) error {
+		workspace := entity.Workspace{
+			Name:       "Personal",
+			OwnerID:    userID,
+			IsPersonal: true,
+		}
+		if err := tx.Create(&workspace).Error; err != nil {
+			return fmt.Errorf("error creating personal workspace: %v", err)
+		}
+
+		created = entity.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      userID,
+			Role:        entity.WorkspaceRoleOwner,
+			Workspace:   workspace,
+		}
+		if err := tx.Omit("Workspace", "User").Create(&created).Error; err != nil {
+			return fmt.Errorf("error creating workspace member: %v", err)
+		}
+		return nil
+	})
+	if createErr == nil {
+		return &created, nil
+	}
+
+	if member, err := s.findPersonalWorkspace(userID); err == nil {
+		return member, nil
+	}
+	return nil, createErr
+}
+
+func (s *WorkspaceService) findPersonalWorkspace(userID uint) (*entity.WorkspaceMember, error) {
+	var member entity.WorkspaceMember
+	err := s.DB.Joins("Workspace").
+		Where("workspace_members.user_id = ? AND \"Workspace\".is_personal = ? AND \"Workspace\".owner_id = ?", userID, true, userID).
+		First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (s *WorkspaceService) ListWorkspaces(userID, currentWorkspaceID uint) ([]response.WorkspaceResponse, error) {
+	personal, err := s.EnsurePersonalWorkspace(userID)
+	if err != nil {
+		return nil, err
+	}
+	if currentWorkspaceID == 0 {
+		currentWorkspaceID = personal.WorkspaceID
+	}
+
+	var members []entity.WorkspaceMember
+	if err := s.DB.Joins("Workspace").
+		Where("workspace_members.user_id = ?", userID).
+		Order("\"Workspace\".is_personal DESC, workspace_members.created_at").
+		Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("error getting workspaces: %v", err)
+	}
+
+	workspaces := make([]response.WorkspaceResponse, len(members))
+	for i, member := range members {
+		workspaces[i] = toWorkspaceResponse(member, currentWorkspaceID)
+	}
+
+	return workspaces, nil
+}
+
+func (s *WorkspaceService) CreateWorkspace(userID uint, name string) (*response.WorkspaceResponse, error) {
+	var member entity.WorkspaceMember
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		workspace := entity.Workspace{
+			Name:    strings.TrimSpace(name),
+			OwnerID: userID,
+		}
+		if err := tx.Create(&workspace).Error; err != nil {
+			return fmt.Errorf("error creating workspace: %v", err)
+		}
+
+		member = entity.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      userID,
+			Role:        entity.WorkspaceRoleOwner,
+			Workspace:   workspace,
+		}
+		if err := tx.Omit("Workspace", "User").Create(&member).Error; err != nil {
+			return fmt.Errorf("error creating workspace member: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workspace := toWorkspaceResponse(member, 0)
+	return &workspace, nil
+}
+
+// DeleteWorkspace menghapus workspace bersama kategori dan transaksinya, hanya untuk owner
+func (s *WorkspaceService) DeleteWorkspace(userID, workspaceID uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		member, err := s.requireOwner(tx, workspaceID, userID)
+		if err != nil {
+			return err
+		}
+		if member.Workspace.IsPersonal {
+			return ErrPersonalWorkspace
+		}
+
+		for _, model := range []interface{}{&entity.Transaction{}, &entity.MonthlyAggregate{}, &entity.SpendingAnomaly{}, &entity.NetWorthSnapshot{}, &entity.Category{}, &entity.WorkspaceMember{}, &entity.WorkspaceInvitation{}} {
+			if err := tx.Where("workspace_id = ?", workspaceID).Delete(model).Error; err != nil {
+				return fmt.Errorf("error deleting workspace data: %v", err)
+			}
+		}
+
+		if err := tx.Delete(&entity.Workspace{}, workspaceID).Error; err != nil {
+			return fmt.Errorf("error deleting workspace: %v", err)
+		}
+		return nil
+	})
+}
+
+func (s *WorkspaceService) ListMembers(userID, workspaceID uint) ([]response.WorkspaceMemberResponse, error) {
+	if _, err := s.ResolveMembership(userID, workspaceID); err != nil {
+		return nil, err
+	}
+
+	var members []entity.WorkspaceMember
+	if err := s.DB.Preload("User").
+		Where("workspace_id = ?", workspaceID).
+		Order("created_at").
+		Find(&members).Error; err != nil {
+		return nil, fmt.Errorf("error getting workspace members: %v", err)
+	}
+
+	memberResponses := make([]response.WorkspaceMemberResponse, len(members))
+	for i, member := range members {
+		memberResponses[i] = response.WorkspaceMemberResponse{
+			UserID:   member.UserID,
+			Name:     member.User.Name,
+			Email:    member.User.Email,
+			Role:     member.Role,
+			JoinedAt: member.CreatedAt,
+		}
+	}
+
+	return memberResponses, nil
+}
+
+func (s *WorkspaceService) UpdateMemberRole(actorID, workspaceID, memberUserID uint, role string) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := s.requireOwner(tx, workspaceID, actorID); err != nil {
+			return err
+		}
+
+		member, err := s.findMember(tx, workspaceID, memberUserID)
+		if err != nil {
+			return err
+		}
+
+		if member.Role == entity.WorkspaceRoleOwner && role != entity.WorkspaceRoleOwner {
+			if err := s.ensureAnotherOwner(tx, workspaceID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(member).Omit("Workspace", "User").Update("role", role).Error; err != nil {
+			return fmt.Errorf("error updating member role: %v", err)
+		}
+		return nil
+	})
+}
+
+// RemoveMember mengeluarkan member dari workspace, owner bisa mengeluarkan siapa saja dan member bisa keluar sendiri
+func (s *WorkspaceService) RemoveMember(actorID, workspaceID, memberUserID uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if actorID != memberUserID {
+			if _, err := s.requireOwner(tx, workspaceID, actorID); err != nil {
+				return err
+			}
+		}
+
+		member, err := s.findMember(tx, workspaceID, memberUserID)
+		if err != nil {
+			if err == ErrWorkspaceMemberNotFound && actorID == memberUserID {
+				return ErrWorkspaceAccessDenied
+			}
+			return err
+		}
+
+		if member.Workspace.IsPersonal {
+			return ErrPersonalWorkspace
+		}
+
+		if member.Role == entity.WorkspaceRoleOwner {
+			if err := s.ensureAnotherOwner(tx, workspaceID); err != nil {
+				return err
+			}
+		}
+
+		// transaksi dan kategori yang dibuat member tetap ada di workspace. Baris member dihapus permanen karena
+		// idx_workspace_member juga berlaku untuk baris soft delete, sehingga member tidak bisa diundang lagi
+		if err := tx.Unscoped().Delete(member).Error; err != nil {
+			return fmt.Errorf("error removing workspace member: %v", err)
+		}
+		return nil
+	})
+}
+
+// InviteMember membuat undangan dan mengirim link-nya ke email, undangan hanya bisa diterima oleh pemilik email tersebut
+func (s *WorkspaceService) InviteMember(actorID, workspaceID uint, email, role string) (*response.WorkspaceInvitationResponse, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	var invitation entity.WorkspaceInvitation
+	var inviter entity.User
+	var token string
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		owner, err := s.requireOwner(tx, workspaceID, actorID)
+		if err != nil {
+			return err
+		}
+		if owner.Workspace.IsPersonal {
+			return ErrPersonalWorkspace
+		}
+
+		var existing int64
+		if err := tx.Model(&entity.WorkspaceMember{}).
+			Joins("JOIN users ON users.id = workspace_members.user_id").
+			Where("workspace_members.workspace_id = ? AND LOWER(users.email) = ?", workspaceID, email).
+			Count(&existing).Error; err != nil {
+			return fmt.Errorf("error checking workspace member: %v", err)
+		}
+		if existing > 0 {
+			return ErrAlreadyWorkspaceMember
+		}
+
+		if err := tx.First(&inviter, actorID).Error; err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		token = utility.GenerateSecureToken()
+		invitation = entity.WorkspaceInvitation{
+			WorkspaceID: workspaceID,
+			Email:       email,
+			Role:        role,
+			TokenHash:   utility.HashToken(token),
+			InvitedByID: actorID,
+			ExpiresAt:   time.Now().Add(workspaceInvitationTTL),
+			Workspace:   owner.Workspace,
+		}
+		if err := tx.Omit("Workspace").Create(&invitation).Error; err != nil {
+			return fmt.Errorf("error creating workspace invitation: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	link := fmt.Sprintf("%s/workspace-invite?token=%s", s.AppURL, url.QueryEscape(token))
+	body := fmt.Sprintf("Hi,\n\n"+
+		"%s invited you to join the \"%s\" workspace as %s. Open the link below to accept the invitation:\n\n"+
+		"%s\n\n"+
+		"This link expires in %d days. You need to sign in with %s to accept it.\n",
+		inviter.Name, invitation.Workspace.Name, role, link, int(workspaceInvitationTTL.Hours()/24), email)
+
+	if err := s.EmailSender.Send(utility.EmailMessage{
+		To:      email,
+		Subject: "You are invited to a shared workspace",
+		Body:    body,
+	}); err != nil {
+		logrus.Errorf("Failed to send workspace invitation email: %v", err)
+	}
+
+	return &response.WorkspaceInvitationResponse{
+		ID:        invitation.ID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		ExpiresAt: invitation.ExpiresAt,
+	}, nil
+}
+
+func (s *WorkspaceService) AcceptInvitation(userID uint, token string) (*response.WorkspaceResponse, error) {
+	var member entity.WorkspaceMember
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var invitation entity.WorkspaceInvitation
+		if err := tx.Preload("Workspace").Where("token_hash = ?", utility.HashToken(token)).First(&invitation).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidInvitation
+			}
+			return fmt.Errorf("error getting workspace invitation: %v", err)
+		}
+
+		now := time.Now()
+		if invitation.AcceptedAt != nil || now.After(invitation.ExpiresAt) || invitation.Workspace.ID == 0 {
+			return ErrInvalidInvitation
+		}
+
+		var user entity.User
+		if err := tx.First(&user, userID).Error; err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+		if !strings.EqualFold(user.Email, invitation.Email) {
+			return ErrInvitationEmailMismatch
+		}
+
+		if _, err := s.findMember(tx, invitation.WorkspaceID, userID); err == nil {
+			return ErrAlreadyWorkspaceMember
+		} else if err != ErrWorkspaceMemberNotFound {
+			return err
+		}
+
+		// sisa member yang dikeluarkan sebelum RemoveMember menghapus permanen masih memakai idx_workspace_member
+		if err := tx.Unscoped().
+			Where("workspace_id = ? AND user_id = ? AND deleted_at IS NOT NULL", invitation.WorkspaceID, userID).
+			Delete(&entity.WorkspaceMember{}).Error; err != nil {
+			return fmt.Errorf("error clearing removed workspace member: %v", err)
+		}
+
+		member = entity.WorkspaceMember{
+			WorkspaceID: invitation.WorkspaceID,
+			UserID:      userID,
+			Role:        invitation.Role,
+			Workspace:   invitation.Workspace,
+		}
+		if err := tx.Omit("Workspace", "User").Create(&member).Error; err != nil {
+			return fmt.Errorf("error creating workspace member: %v", err)
+		}
+
+		if err := tx.Model(&invitation).Update("accepted_at", now).Error; err != nil {
+			return fmt.Errorf("error updating workspace invitation: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workspace := toWorkspaceResponse(member, 0)
+	return &workspace, nil
+}
+
+// SwitchWorkspace menerbitkan access token baru untuk session yang sama dengan claim workspace aktif
+func (s *WorkspaceService) SwitchWorkspace(userID uint, sessionID string, workspaceID uint) (*response.WorkspaceSwitchResponse, error) {
+	member, err := s.ResolveMembership(userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var user entity.User
+	if err := s.DB.Select("id", "username").First(&user, userID).Error; err != nil {
+		return nil, fmt.Errorf("error getting user: %v", err)
+	}
+
+	token, err := utility.GenerateJWT(user.ID, user.Username, sessionID, member.WorkspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("error generating token: %v", err)
+	}
+
+	return &response.WorkspaceSwitchResponse{
+		WorkspaceID: member.WorkspaceID,
+		AccessToken: token,
+		Expiration:  time.Now().Add(utility.TokenTTL),
+	}, nil
+}
+
+func (s *WorkspaceService) findMember(tx *gorm.DB, workspaceID, userID uint) (*entity.WorkspaceMember, error) {
+	var member entity.WorkspaceMember
+	if err := tx.Joins("Workspace").
+		Where("workspace_members.workspace_id = ? AND workspace_members.user_id = ?", workspaceID, userID).
+		First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWorkspaceMemberNotFound
+		}
+		return nil, fmt.Errorf("error getting workspace member: %v", err)
+	}
+	return &member, nil
+}
+
+func (s *WorkspaceService) requireOwner(tx *gorm.DB, workspaceID, userID uint) (*entity.WorkspaceMember, error) {
+	member, err := s.findMember(tx, workspaceID, userID)
+	if err == ErrWorkspaceMemberNotFound {
+		return nil, ErrWorkspaceAccessDenied
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if member.Role != entity.WorkspaceRoleOwner {
+		return nil, ErrWorkspaceOwnerRequired
+	}
+	return member, nil
+}
+
+// ensureAnotherOwner mengunci baris owner workspace (COUNT tidak bisa FOR UPDATE) agar dua owner yang saling
+// menurunkan role atau mengeluarkan bersamaan tidak sama-sama melihat 2 owner
+func (s *WorkspaceService) ensureAnotherOwner(tx *gorm.DB, workspaceID uint) error {
+	var owners []uint
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(&entity.WorkspaceMember{}).
+		Where("workspace_id = ? AND role = ?", workspaceID, entity.WorkspaceRoleOwner).
+		Order("id").
+		Pluck("id", &owners).Error; err != nil {
+		return fmt.Errorf("error counting workspace owners: %v", err)
+	}
+	if len(owners) <= 1 {
+		return ErrLastWorkspaceOwner
+	}
+	return nil
+}
+
+func toWorkspaceResponse(member entity.WorkspaceMember, currentWorkspaceID uint) response.WorkspaceResponse {
+	return response.WorkspaceResponse{
+		ID:         member.WorkspaceID,
+		Name:       member.Workspace.Name,
+		IsPersonal: member.Workspace.IsPersonal,
+		Role:       member.Role,
+		Current:    member.WorkspaceID == currentWorkspaceID,
+		CreatedAt:  member.Workspace.CreatedAt,
+	}
+}
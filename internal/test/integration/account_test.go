@@ -0,0 +1,147 @@
+package integration
+
+import (
+	"archive/zip"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAccountService(t *testing.T, ts *TestServer) *service.AccountService {
+	return service.NewAccountService(ts.DB, nil, "", t.TempDir(), t.TempDir(), 0)
+}
+
+func TestDataExportContainsOwnedRecords(t *testing.T) {
+	ts := setupTestServer(t)
+	defer cleanupDatabase(ts.DB)
+
+	createResourceFixture(t, ts, "alice")
+	createResourceFixture(t, ts, "bob")
+
+	var user entity.User
+	require.NoError(t, ts.DB.Where("username = ?", "alice").First(&user).Error)
+
+	accountService := newTestAccountService(t, ts)
+	export, err := accountService.RequestExport(user.ID)
+	require.NoError(t, err)
+
+	_, err = accountService.RequestExport(user.ID)
+	assert.Equal(t, service.ErrDataExportInProgress, err)
+
+	accountService.RunPendingJobs()
+
+	path, err := accountService.GetExportFile(user.ID, export.ID)
+	require.NoError(t, err)
+
+	archive, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer archive.Close()
+
+	files := map[string]string{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		require.NoError(t, err)
+		files[file.Name] = string(content)
+	}
+
+	for _, name := range []string{"profile", "preferences", "categories", "transactions", "sessions"} {
+		assert.Contains(t, files, name+".json")
+		assert.Contains(t, files, name+".csv")
+	}
+	assert.Contains(t, files["transactions.csv"], "alice transaction")
+	assert.Contains(t, files["categories.json"], "alice category")
+	assert.NotContains(t, files["transactions.csv"], "bob")
+	assert.NotContains(t, files["profile.json"], user.Password)
+
+	// export milik user lain tidak bisa diunduh
+	var bob entity.User
+	require.NoError(t, ts.DB.Where("username = ?", "bob").First(&bob).Error)
+	_, err = accountService.GetExportFile(bob.ID, export.ID)
+	assert.Equal(t, service.ErrDataExportNotFound, err)
+
+}
+
+func TestAccountDeletionPurgesOwnedData(t *testing.T) {
+	ts := setupTestServer(t)
+	defer cleanupDatabase(ts.DB)
+
+	alice := createResourceFixture(t, ts, "alice")
+	createResourceFixture(t, ts, "bob")
+
+	var aliceUser, bobUser entity.User
+	require.NoError(t, ts.DB.Where("username = ?", "alice").First(&aliceUser).Error)
+	require.NoError(t, ts.DB.Where("username = ?", "bob").First(&bobUser).Error)
+
+	// workspace bersama milik alice dengan bob sebagai editor
+	shared := entity.Workspace{Name: "household", OwnerID: aliceUser.ID}
+	require.NoError(t, ts.DB.Create(&shared).Error)
+	require.NoError(t, ts.DB.Create(&entity.WorkspaceMember{WorkspaceID: shared.ID, UserID: aliceUser.ID, Role: entity.WorkspaceRoleOwner}).Error)
+	require.NoError(t, ts.DB.Create(&entity.WorkspaceMember{WorkspaceID: shared.ID, UserID: bobUser.ID, Role: entity.WorkspaceRoleEditor}).Error)
+
+	sharedCategory := entity.Category{WorkspaceID: shared.ID, UserID: aliceUser.ID, Name: "groceries"}
+	require.NoError(t, ts.DB.Create(&sharedCategory).Error)
+	sharedTransaction := entity.Transaction{
+		WorkspaceID: shared.ID,
+		UserID:      aliceUser.ID,
+		CategoryID:  sharedCategory.ID,
+		Amount:      2500,
+		Type:        "expense",
+		Date:        time.Now(),
+	}
+	require.NoError(t, ts.DB.Create(&sharedTransaction).Error)
+
+	hashedPassword, err := utility.HashPassword("Secret123")
+	require.NoError(t, err)
+	require.NoError(t, ts.DB.Model(&aliceUser).Update("password", hashedPassword).Error)
+
+	accountService := newTestAccountService(t, ts)
+	_, err = accountService.ScheduleDeletion(aliceUser.ID, "wrong")
+	assert.Equal(t, service.ErrDeletionPasswordInvalid, err)
+
+	deletion, err := accountService.ScheduleDeletion(aliceUser.ID, "Secret123")
+	require.NoError(t, err)
+	assert.False(t, deletion.ScheduledFor.After(time.Now()))
+
+	accountService.RunPendingJobs()
+
+	var count int64
+	ts.DB.Unscoped().Model(&entity.User{}).Where("id = ?", aliceUser.ID).Count(&count)
+	assert.Zero(t, count, "user row must be hard-deleted")
+
+	for _, model := range []interface{}{&entity.Session{}, &entity.WorkspaceMember{}, &entity.Category{}, &entity.Transaction{}, &entity.UserPreference{}} {
+		ts.DB.Unscoped().Model(model).Where("user_id = ?", aliceUser.ID).Count(&count)
+		assert.Zero(t, count, "%T rows of the deleted user must be removed", model)
+	}
+
+	ts.DB.Unscoped().Model(&entity.SecurityEvent{}).Where("user_id = ?", aliceUser.ID).Count(&count)
+	assert.Zero(t, count, "security events must be anonymized")
+
+	// data personal alice hilang, data workspace bersama tetap ada dan pindah ke bob
+	ts.DB.Unscoped().Model(&entity.Transaction{}).Where("id = ?", alice.Transaction).Count(&count)
+	assert.Zero(t, count)
+
+	var workspace entity.Workspace
+	require.NoError(t, ts.DB.First(&workspace, shared.ID).Error)
+	assert.Equal(t, bobUser.ID, workspace.OwnerID)
+
+	var member entity.WorkspaceMember
+	require.NoError(t, ts.DB.Where("workspace_id = ? AND user_id = ?", shared.ID, bobUser.ID).First(&member).Error)
+	assert.Equal(t, entity.WorkspaceRoleOwner, member.Role)
+
+	var transaction entity.Transaction
+	require.NoError(t, ts.DB.First(&transaction, sharedTransaction.ID).Error)
+	assert.Equal(t, bobUser.ID, transaction.UserID)
+
+	// data bob tidak tersentuh
+	ts.DB.Model(&entity.Transaction{}).Where("user_id = ? AND description LIKE ?", bobUser.ID, "bob%").Count(&count)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"context"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// loadAggregates menjumlahkan monthly_aggregates workspace per bulan/tipe, baris tanpa transaksi diabaikan
+func loadAggregates(t *testing.T, ts *TestServer, workspaceID uint) map[string]float64 {
+	var aggregates []entity.MonthlyAggregate
+	require.NoError(t, ts.DB.Where("workspace_id = ? AND transaction_count <> 0", workspaceID).Find(&aggregates).Error)
+
+	result := map[string]float64{}
+	for _, aggregate := range aggregates {
+		key := aggregate.Month.UTC().Format("2006-01") + "/" + aggregate.Type
+		result[key] += aggregate.Total
+	}
+	return result
+}
+
+func TestMonthlyAggregatesFollowTransactionChanges(t *testing.T) {
+	ts := setupTestServer(t)
+	defer cleanupDatabase(ts.DB)
+
+	fixture := createResourceFixture(t, ts, "alice")
+	var user entity.User
+	require.NoError(t, ts.DB.Where("username = ?", "alice").First(&user).Error)
+	var category entity.Category
+	require.NoError(t, ts.DB.First(&category, fixture.CategoryID).Error)
+
+	scope := request.WorkspaceScope{WorkspaceID: category.WorkspaceID, UserID: user.ID, Role: entity.WorkspaceRoleOwner}
+	transactionService := service.NewTransactionService(ts.DB)
+
+	// fixture dibuat langsung ke tabel, rebuild menyamakan agregat sebelum test
+	require.NoError(t, ts.DB.Transaction(func(tx *gorm.DB) error {
+		return utility.RebuildMonthlyAggregates(tx, scope.WorkspaceID)
+	}))
+
+	salary, err := transactionService.CreateTransaction(scope, request.CreateTransactionRequest{
+		CategoryID: category.ID, Amount: 5000, Type: "income", Date: "2024-03-25",
+	})
+	require.NoError(t, err)
+	rent, err := transactionService.CreateTransaction(scope, request.CreateTransactionRequest{
+		CategoryID: category.ID, Amount: 1500, Type: "expense", Date: "2024-03-31",
+	})
+	require.NoError(t, err)
+
+	// pindah bulan lewat update
+	_, err = transactionService.UpdateTransaction(scope, rent.ID, request.UpdateTransactionRequest{
+		CategoryID: category.ID, Amount: 1200, Type: "expense", Date: "2024-04-01",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, transactionService.DeleteTransaction(scope, salary.ID))
+	aggregates := loadAggregates(t, ts, scope.WorkspaceID)
+	assert.NotContains(t, aggregates, "2024-03/income")
+	assert.NotContains(t, aggregates, "2024-03/expense")
+	assert.Equal(t, 1200.0, aggregates["2024-04/expense"])
+
+	_, err = transactionService.RestoreTransaction(scope, salary.ID)
+	require.NoError(t, err)
+	_, err = transactionService.RestoreTransaction(scope, salary.ID)
+	assert.Error(t, err)
+
+	aggregates = loadAggregates(t, ts, scope.WorkspaceID)
+	assert.Equal(t, 5000.0, aggregates["2024-03/income"])
+
+	// hasil perubahan bertahap harus sama dengan hasil rebuild dari tabel transactions
+	require.NoError(t, ts.DB.Transaction(func(tx *gorm.DB) error {
+		return utility.RebuildMonthlyAggregates(tx, scope.WorkspaceID)
+	}))
+	assert.Equal(t, aggregates, loadAggregates(t, ts, scope.WorkspaceID))
+
+	// dashboard membaca maret dan april dari agregat, 15 februari dari transaksi
+	dashboardService := service.NewDashboardService(ts.DB)
+	overview, err := dashboardService.GetFinancialOverview(context.Background(), scope, request.DashboardFilter{
+		StartDate: "2024-02-15", EndDate: "2024-04-30", Granularity: "month",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5000.0, overview.MonthlyIncome)
+	assert.Equal(t, 1200.0, overview.MonthlyExpense)
+
+	summary, err := transactionService.GetTransactionByUser(scope, request.TransactionFilter{
+		StartDate: "2024-03-10", EndDate: "2024-04-30", Page: 1, Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5000.0, summary.Summary.TotalIncome)
+	assert.Equal(t, 1200.0, summary.Summary.TotalExpense)
+}
@@ -104,7 +104,7 @@ func TestRegisterHandler(t *testing.T) {
 				ConfirmPassword: "Password123",
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedError:  "username can only contain letters and numbers",
+			expectedError:  "username must be 4-20 letters or numbers",
 		},
 	}
 
@@ -0,0 +1,488 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-fintrack/internal/controller"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"go-fintrack/middleware"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+type resourceFixture struct {
+	Token       string
+	UserID      uint
+	WorkspaceID uint
+	CategoryID  uint
+	Transaction uint
+}
+
+// setupResourceRoutes mendaftarkan endpoint category, transaction, dashboard, insights, workspace, akun dan export data
+// pribadi dengan middleware dan aturan scope API token yang sama seperti router
+func setupResourceRoutes(ts *TestServer) {
+	sessionService := service.NewSessionService(ts.DB)
+	apiTokenService := service.NewAPITokenService(ts.DB)
+	authMiddleware := middleware.Authentication(sessionService, apiTokenService, ts.UserService)
+	workspaceService := service.NewWorkspaceService(ts.DB, nil, "")
+	workspaceMiddleware := middleware.WorkspaceContext(workspaceService)
+
+	categoryController := &controller.CategoryController{CategoryService: &service.CategoryService{DB: ts.DB}}
+	transactionController := &controller.TransactionController{TransactionService: service.NewTransactionService(ts.DB)}
+	dashboardController := controller.NewDashboardController(service.NewCachedDashboardService(service.NewDashboardService(ts.DB), nil, nil))
+	netWorthController := &controller.NetWorthController{NetWorthService: service.NewNetWorthService(ts.DB, nil)}
+	insightController := &controller.InsightController{InsightService: service.NewInsightService(ts.DB, nil)}
+	workspaceController := &controller.WorkspaceController{WorkspaceService: workspaceService}
+	sessionController := &controller.SessionController{SessionService: sessionService}
+	apiTokenController := &controller.APITokenController{APITokenService: apiTokenService}
+	oauthController := &controller.OAuthController{UserService: ts.UserService, IdentityService: service.NewIdentityService(ts.DB)}
+	accountController := &controller.AccountController{
+		AccountService: service.NewAccountService(ts.DB, nil, "", "", filepath.Join(os.TempDir(), "fintrack-test-exports"), 0),
+	}
+
+	category := ts.Router.Group("/api/category", middleware.APITokenScopes(service.ScopeCategoriesRead, service.ScopeCategoriesWrite), authMiddleware, workspaceMiddleware)
+	category.GET("", categoryController.GetAllCategoriesHandler)
+	category.GET("/:id", categoryController.GetCategoryIdHandler)
+	category.POST("", categoryController.CreateCategoryHandler)
+	category.PUT("/:id", categoryController.UpdateCategoryHandler)
+	category.DELETE("/:id", categoryController.DeleteCategoryHandler)
+
+	transaction := ts.Router.Group("/api/transaction", middleware.APITokenScopes(service.ScopeTransactionsRead, service.ScopeTransactionsWrite), authMiddleware, workspaceMiddleware)
+	transaction.GET("", transactionController.GetTransactionHandler)
+	transaction.POST("", transactionController.CreateTransactionHandler)
+	transaction.PUT("/:id", transactionController.UpdateTransactionHandler)
+	transaction.DELETE("/:id", transactionController.DeleteTransactionHandler)
+	transaction.POST("/:id/restore", transactionController.RestoreTransactionHandler)
+	transaction.GET("/export", middleware.RequireScope(service.ScopeExport), transactionController.ExportTransactionsExcelHandler)
+
+	dashboard := ts.Router.Group("/api/dashboard", middleware.APITokenScopes(service.ScopeDashboardRead, ""), authMiddleware, workspaceMiddleware)
+	dashboard.GET("/overview", dashboardController.GetFinancialOverviewHandler)
+	dashboard.GET("/charts", dashboardController.GetDashboardChartsHandler)
+	dashboard.GET("/forecast", dashboardController.GetCashFlowForecastHandler)
+	dashboard.GET("/net-worth", netWorthController.GetNetWorthChartHandler)
+	dashboard.GET("/compare", dashboardController.GetPeriodComparisonHandler)
+	dashboard.GET("/category-trend", dashboardController.GetCategoryTrendHandler)
+	dashboard.GET("/heatmap", dashboardController.GetSpendingHeatmapHandler)
+
+	insights := ts.Router.Group("/api/insights", middleware.APITokenScopes(service.ScopeDashboardRead, ""), authMiddleware, workspaceMiddleware)
+	insights.GET("", insightController.GetInsightsHandler)
+
+	workspaces := ts.Router.Group("/api/workspaces", authMiddleware)
+	workspaces.DELETE("/:id", workspaceController.DeleteWorkspaceHandler)
+	workspaces.POST("/:id/switch", workspaceController.SwitchWorkspaceHandler)
+	workspaces.GET("/:id/members", workspaceController.GetWorkspaceMembersHandler)
+	workspaces.PUT("/:id/members/:userId", workspaceController.UpdateWorkspaceMemberHandler)
+	workspaces.DELETE("/:id/members/:userId", workspaceController.RemoveWorkspaceMemberHandler)
+	workspaces.POST("/:id/invitations", workspaceController.InviteWorkspaceMemberHandler)
+
+	auth := ts.Router.Group("/api/auth", authMiddleware)
+	auth.DELETE("/sessions/:id", sessionController.RevokeSessionHandler)
+	auth.DELETE("/tokens/:id", apiTokenController.RevokeAPITokenHandler)
+	auth.DELETE("/identities/:id", oauthController.UnlinkIdentityHandler)
+
+	me := ts.Router.Group("/api/me", authMiddleware)
+	me.GET("/exports", accountController.GetDataExportsHandler)
+	me.POST("/exports", middleware.RequireVerifiedEmail(ts.UserService, middleware.FeatureExport), accountController.RequestDataExportHandler)
+	me.GET("/exports/:id/download", middleware.RequireVerifiedEmail(ts.UserService, middleware.FeatureExport), accountController.DownloadDataExportHandler)
+}
+
+// createResourceFixture membuat user dengan satu kategori dan satu transaksi di workspace personalnya
+func createResourceFixture(t *testing.T, ts *TestServer, name string) resourceFixture {
+	user := entity.User{
+		Name:     name,
+		Email:    name + "@example.com",
+		Username: name,
+		Password: "hashed",
+		Role:     entity.RoleUser,
+	}
+	require.NoError(t, ts.DB.Create(&user).Error)
+
+	member, err := service.NewWorkspaceService(ts.DB, nil, "").EnsurePersonalWorkspace(user.ID)
+	require.NoError(t, err)
+
+	category := entity.Category{WorkspaceID: member.WorkspaceID, UserID: user.ID, Name: name + " category"}
+	require.NoError(t, ts.DB.Create(&category).Error)
+
+	transaction := entity.Transaction{
+		WorkspaceID: member.WorkspaceID,
+		UserID:      user.ID,
+		CategoryID:  category.ID,
+		Amount:      1000,
+		Type:        "expense",
+		Description: name + " transaction",
+		Date:        time.Now(),
+	}
+	require.NoError(t, ts.DB.Create(&transaction).Error)
+
+	token, err := ts.UserService.IssueToken(&user, request.ClientInfo{UserAgent: "integration-test"})
+	require.NoError(t, err)
+
+	return resourceFixture{
+		Token:       token,
+		UserID:      user.ID,
+		WorkspaceID: member.WorkspaceID,
+		CategoryID:  category.ID,
+		Transaction: transaction.ID,
+	}
+}
+
+func doAuthedRequest(ts *TestServer, method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	w := httptest.NewRecorder()
+	ts.Router.ServeHTTP(w, req)
+	return w
+}
+
+// doWorkspaceRequest mengirim request dengan API token yang memilih workspace lewat header X-Workspace-ID
+func doWorkspaceRequest(ts *TestServer, method, path, apiToken string, workspaceID uint, body interface{}) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+
+	req := httptest.NewRequest(method, path, bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("X-Workspace-ID", fmt.Sprint(workspaceID))
+
+	w := httptest.NewRecorder()
+	ts.Router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCrossUserResourceAccess(t *testing.T) {
+	ts := setupTestServer(t)
+	defer cleanupDatabase(ts.DB)
+	setupResourceRoutes(ts)
+
+	alice := createResourceFixture(t, ts, "alice")
+	bob := createResourceFixture(t, ts, "bob")
+
+	today := time.Now().Format("2006-01-02")
+	transactionBody := func(categoryID uint) request.CreateTransactionRequest {
+		return request.CreateTransactionRequest{
+			CategoryID: categoryID,
+			Amount:     500,
+			Type:       "expense",
+			Date:       today,
+		}
+	}
+
+	// session, API token, identity dan export milik bob
+	var bobSession entity.Session
+	require.NoError(t, ts.DB.Where("user_id = ?", bob.UserID).First(&bobSession).Error)
+	bobAPIToken, err := service.NewAPITokenService(ts.DB).CreateToken(bob.UserID, request.CreateAPITokenRequest{
+		Name: "bob script", Scopes: []string{service.ScopeTransactionsRead},
+	})
+	require.NoError(t, err)
+	bobIdentity := entity.UserIdentity{UserID: bob.UserID, Provider: "google", ProviderUserID: "bob-google", Email: "bob@example.com"}
+	require.NoError(t, ts.DB.Create(&bobIdentity).Error)
+	completedAt := time.Now()
+	expiresAt := completedAt.Add(time.Hour)
+	bobExport := entity.DataExport{UserID: bob.UserID, Status: entity.DataExportCompleted, FilePath: "bob-export.zip", CompletedAt: &completedAt, ExpiresAt: &expiresAt}
+	require.NoError(t, ts.DB.Create(&bobExport).Error)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           interface{}
+		expectedStatus int
+	}{
+		{"get_other_category", http.MethodGet, fmt.Sprintf("/api/category/%d", bob.CategoryID), nil, http.StatusNotFound},
+		{"update_other_category", http.MethodPut, fmt.Sprintf("/api/category/%d", bob.CategoryID), request.UpdateCategoryRequest{Name: "stolen"}, http.StatusNotFound},
+		{"delete_other_category", http.MethodDelete, fmt.Sprintf("/api/category/%d", bob.CategoryID), nil, http.StatusNotFound},
+		{"create_transaction_with_other_category", http.MethodPost, "/api/transaction", transactionBody(bob.CategoryID), http.StatusNotFound},
+		{"update_other_transaction", http.MethodPut, fmt.Sprintf("/api/transaction/%d", bob.Transaction), transactionBody(alice.CategoryID), http.StatusNotFound},
+		{"update_own_transaction_with_other_category", http.MethodPut, fmt.Sprintf("/api/transaction/%d", alice.Transaction), transactionBody(bob.CategoryID), http.StatusNotFound},
+		{"delete_other_transaction", http.MethodDelete, fmt.Sprintf("/api/transaction/%d", bob.Transaction), nil, http.StatusNotFound},
+		// kategori workspace lain diperlakukan sama dengan kategori yang tidak ada
+		{"category_trend_with_other_category", http.MethodGet, fmt.Sprintf("/api/dashboard/category-trend?category_ids=%d", bob.CategoryID), nil, http.StatusBadRequest},
+		{"revoke_other_session", http.MethodDelete, fmt.Sprintf("/api/auth/sessions/%d", bobSession.ID), nil, http.StatusNotFound},
+		{"revoke_other_api_token", http.MethodDelete, fmt.Sprintf("/api/auth/tokens/%d", bobAPIToken.ID), nil, http.StatusNotFound},
+		{"unlink_other_identity", http.MethodDelete, fmt.Sprintf("/api/auth/identities/%d", bobIdentity.ID), nil, http.StatusNotFound},
+		{"download_other_export", http.MethodGet, fmt.Sprintf("/api/me/exports/%d/download", bobExport.ID), nil, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doAuthedRequest(ts, tt.method, tt.path, alice.Token, tt.body)
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			assert.NotContains(t, w.Body.String(), "bob")
+		})
+	}
+
+	// kategori baru selalu masuk ke workspace aktif alice
+	w := doAuthedRequest(ts, http.MethodPost, "/api/category", alice.Token, request.CategoryRequest{Name: "alice groceries"})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var created entity.Category
+	require.NoError(t, ts.DB.Where("name = ?", "alice groceries").First(&created).Error)
+	assert.Equal(t, alice.WorkspaceID, created.WorkspaceID)
+
+	// data bob tidak berubah setelah semua percobaan akses di atas
+	var category entity.Category
+	require.NoError(t, ts.DB.First(&category, bob.CategoryID).Error)
+	assert.Equal(t, "bob category", category.Name)
+
+	var bobCategories int64
+	require.NoError(t, ts.DB.Model(&entity.Category{}).Where("workspace_id = ?", bob.WorkspaceID).Count(&bobCategories).Error)
+	assert.Equal(t, int64(1), bobCategories)
+
+	require.NoError(t, ts.DB.First(&bobSession, bobSession.ID).Error)
+	assert.Nil(t, bobSession.RevokedAt)
+	assert.NoError(t, ts.DB.First(&entity.APIToken{}, bobAPIToken.ID).Error)
+	assert.NoError(t, ts.DB.First(&entity.UserIdentity{}, bobIdentity.ID).Error)
+	assert.NoError(t, ts.DB.First(&entity.DataExport{}, bobExport.ID).Error)
+
+	var transaction entity.Transaction
+	require.NoError(t, ts.DB.First(&transaction, bob.Transaction).Error)
+	assert.Equal(t, bob.CategoryID, transaction.CategoryID)
+
+	var aliceTransaction entity.Transaction
+	require.NoError(t, ts.DB.First(&aliceTransaction, alice.Transaction).Error)
+	assert.Equal(t, alice.CategoryID, aliceTransaction.CategoryID)
+}
+
+func TestCrossUserListIsolation(t *testing.T) {
+	ts := setupTestServer(t)
+	defer cleanupDatabase(ts.DB)
+	setupResourceRoutes(ts)
+
+	alice := createResourceFixture(t, ts, "alice")
+	bob := createResourceFixture(t, ts, "bob")
+
+	// anomali di workspace bob tidak boleh muncul di insights alice
+	now := time.Now().UTC()
+	require.NoError(t, ts.DB.Create(&entity.SpendingAnomaly{
+		WorkspaceID:   bob.WorkspaceID,
+		Kind:          entity.AnomalyLargeTransaction,
+		TransactionID: &bob.Transaction,
+		CategoryID:    bob.CategoryID,
+		Date:          now,
+		Month:         time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		Amount:        1000,
+		Baseline:      100,
+		Ratio:         10,
+		DetectedAt:    now,
+	}).Error)
+
+	paths := []string{
+		"/api/category", "/api/transaction", "/api/insights",
+		"/api/dashboard/overview", "/api/dashboard/charts", "/api/dashboard/forecast", "/api/dashboard/net-worth",
+		"/api/dashboard/compare", "/api/dashboard/category-trend", "/api/dashboard/heatmap",
+	}
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			w := doAuthedRequest(ts, http.MethodGet, path, alice.Token, nil)
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.NotContains(t, w.Body.String(), "bob")
+		})
+	}
+}
+
+func TestOwnResourceAccess(t *testing.T) {
+	ts := setupTestServer(t)
+	defer cleanupDatabase(ts.DB)
+	setupResourceRoutes(ts)
+
+	alice := createResourceFixture(t, ts, "alice")
+
+	w := doAuthedRequest(ts, http.MethodGet, fmt.Sprintf("/api/category/%d", alice.CategoryID), alice.Token, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = doAuthedRequest(ts, http.MethodDelete, fmt.Sprintf("/api/transaction/%d", alice.Transaction), alice.Token, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestCrossUserRestoreAndExport(t *testing.T) {
+	ts := setupTestServer(t)
+	defer cleanupDatabase(ts.DB)
+	setupResourceRoutes(ts)
+
+	alice := createResourceFixture(t, ts, "alice")
+	bob := createResourceFixture(t, ts, "bob")
+
+	// transaksi bob yang sudah dihapus tidak bisa dipulihkan dari workspace alice
+	w := doAuthedRequest(ts, http.MethodDelete, fmt.Sprintf("/api/transaction/%d", bob.Transaction), bob.Token, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = doAuthedRequest(ts, http.MethodPost, fmt.Sprintf("/api/transaction/%d/restore", bob.Transaction), alice.Token, nil)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+	var deleted entity.Transaction
+	require.NoError(t, ts.DB.Unscoped().First(&deleted, bob.Transaction).Error)
+	assert.True(t, deleted.DeletedAt.Valid)
+
+	// export hanya berisi transaksi workspace alice
+	w = doAuthedRequest(ts, http.MethodGet, "/api/transaction/export", alice.Token, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	file, err := excelize.OpenReader(w.Body)
+	require.NoError(t, err)
+	defer file.Close()
+
+	rows, err := file.GetRows("Transactions")
+	require.NoError(t, err)
+	var descriptions []string
+	for _, row := range rows[1:] {
+		if len(row) >= 5 {
+			descriptions = append(descriptions, row[4])
+		}
+	}
+	assert.Equal(t, []string{"alice transaction"}, descriptions)
+}
+
+func TestCrossWorkspaceAccess(t *testing.T) {
+	ts := setupTestServer(t)
+	defer cleanupDatabase(ts.DB)
+	setupResourceRoutes(ts)
+
+	alice := createResourceFixture(t, ts, "alice")
+	bob := createResourceFixture(t, ts, "bob")
+
+	// workspace bersama milik bob, alice hanya viewer
+	shared := entity.Workspace{Name: "bob household", OwnerID: bob.UserID}
+	require.NoError(t, ts.DB.Create(&shared).Error)
+	require.NoError(t, ts.DB.Create(&entity.WorkspaceMember{WorkspaceID: shared.ID, UserID: bob.UserID, Role: entity.WorkspaceRoleOwner}).Error)
+	require.NoError(t, ts.DB.Create(&entity.WorkspaceMember{WorkspaceID: shared.ID, UserID: alice.UserID, Role: entity.WorkspaceRoleViewer}).Error)
+
+	invite := request.InviteWorkspaceMemberRequest{Email: "mallory@example.com", Role: entity.WorkspaceRoleEditor}
+	promote := request.UpdateWorkspaceMemberRequest{Role: entity.WorkspaceRoleOwner}
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           interface{}
+		expectedStatus int
+	}{
+		// workspace personal bob, alice bukan member
+		{"switch_to_other_personal", http.MethodPost, fmt.Sprintf("/api/workspaces/%d/switch", bob.WorkspaceID), nil, http.StatusForbidden},
+		{"list_other_members", http.MethodGet, fmt.Sprintf("/api/workspaces/%d/members", bob.WorkspaceID), nil, http.StatusForbidden},
+		{"update_other_member", http.MethodPut, fmt.Sprintf("/api/workspaces/%d/members/%d", bob.WorkspaceID, bob.UserID), request.UpdateWorkspaceMemberRequest{Role: entity.WorkspaceRoleViewer}, http.StatusForbidden},
+		{"remove_other_member", http.MethodDelete, fmt.Sprintf("/api/workspaces/%d/members/%d", bob.WorkspaceID, bob.UserID), nil, http.StatusForbidden},
+		{"invite_to_other", http.MethodPost, fmt.Sprintf("/api/workspaces/%d/invitations", bob.WorkspaceID), invite, http.StatusForbidden},
+		{"delete_other", http.MethodDelete, fmt.Sprintf("/api/workspaces/%d", bob.WorkspaceID), nil, http.StatusForbidden},
+		// workspace bersama, viewer tidak boleh mengatur member atau menghapus workspace
+		{"viewer_promotes_self", http.MethodPut, fmt.Sprintf("/api/workspaces/%d/members/%d", shared.ID, alice.UserID), promote, http.StatusForbidden},
+		{"viewer_removes_owner", http.MethodDelete, fmt.Sprintf("/api/workspaces/%d/members/%d", shared.ID, bob.UserID), nil, http.StatusForbidden},
+		{"viewer_invites", http.MethodPost, fmt.Sprintf("/api/workspaces/%d/invitations", shared.ID), invite, http.StatusForbidden},
+		{"viewer_deletes_workspace", http.MethodDelete, fmt.Sprintf("/api/workspaces/%d", shared.ID), nil, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doAuthedRequest(ts, tt.method, tt.path, alice.Token, tt.body)
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+		})
+	}
+
+	var members []entity.WorkspaceMember
+	require.NoError(t, ts.DB.Where("workspace_id = ?", shared.ID).Order("user_id").Find(&members).Error)
+	require.Len(t, members, 2)
+	for _, member := range members {
+		if member.UserID == alice.UserID {
+			assert.Equal(t, entity.WorkspaceRoleViewer, member.Role)
+		}
+	}
+
+	var invitations int64
+	require.NoError(t, ts.DB.Model(&entity.WorkspaceInvitation{}).Count(&invitations).Error)
+	assert.Zero(t, invitations)
+
+	// token hasil switch ke workspace bersama hanya boleh membaca
+	w := doAuthedRequest(ts, http.MethodPost, fmt.Sprintf("/api/workspaces/%d/switch", shared.ID), alice.Token, nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var switched struct {
+		Data struct {
+			AccessToken string `json:"access_token"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &switched))
+
+	w = doAuthedRequest(ts, http.MethodGet, "/api/dashboard/charts", switched.Data.AccessToken, nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = doAuthedRequest(ts, http.MethodPost, "/api/transaction", switched.Data.AccessToken, request.CreateTransactionRequest{
+		CategoryID: bob.CategoryID, Amount: 1, Type: "expense", Date: time.Now().Format("2006-01-02"),
+	})
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+}
+
+func TestCrossWorkspaceAPITokenAccess(t *testing.T) {
+	ts := setupTestServer(t)
+	defer cleanupDatabase(ts.DB)
+	setupResourceRoutes(ts)
+
+	alice := createResourceFixture(t, ts, "alice")
+	bob := createResourceFixture(t, ts, "bob")
+
+	apiToken, err := service.NewAPITokenService(ts.DB).CreateToken(alice.UserID, request.CreateAPITokenRequest{
+		Name: "alice script",
+		Scopes: []string{
+			service.ScopeCategoriesRead, service.ScopeCategoriesWrite,
+			service.ScopeTransactionsRead, service.ScopeDashboardRead,
+		},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		body      interface{}
+		ownStatus int
+	}{
+		{"list_categories", http.MethodGet, "/api/category", nil, http.StatusOK},
+		{"create_category", http.MethodPost, "/api/category", request.CategoryRequest{Name: "scripted"}, http.StatusCreated},
+		{"list_transactions", http.MethodGet, "/api/transaction", nil, http.StatusOK},
+		{"overview", http.MethodGet, "/api/dashboard/overview", nil, http.StatusOK},
+		{"charts", http.MethodGet, "/api/dashboard/charts", nil, http.StatusOK},
+		{"forecast", http.MethodGet, "/api/dashboard/forecast", nil, http.StatusOK},
+		{"net_worth", http.MethodGet, "/api/dashboard/net-worth", nil, http.StatusOK},
+		{"compare", http.MethodGet, "/api/dashboard/compare", nil, http.StatusOK},
+		{"category_trend", http.MethodGet, fmt.Sprintf("/api/dashboard/category-trend?category_ids=%d", bob.CategoryID), nil, http.StatusBadRequest},
+		{"heatmap", http.MethodGet, "/api/dashboard/heatmap", nil, http.StatusOK},
+		{"insights", http.MethodGet, "/api/insights", nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// workspace sendiri memastikan token dan scope-nya memang diterima endpoint ini
+			w := doWorkspaceRequest(ts, tt.method, tt.path, apiToken.Token, alice.WorkspaceID, tt.body)
+			assert.Equal(t, tt.ownStatus, w.Code, w.Body.String())
+
+			w = doWorkspaceRequest(ts, tt.method, tt.path, apiToken.Token, bob.WorkspaceID, tt.body)
+			assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+			assert.NotContains(t, w.Body.String(), "bob")
+		})
+	}
+
+	// kategori hanya dibuat di workspace alice, workspace bob tidak berubah
+	var categories []entity.Category
+	require.NoError(t, ts.DB.Where("name = ?", "scripted").Find(&categories).Error)
+	require.Len(t, categories, 1)
+	assert.Equal(t, alice.WorkspaceID, categories[0].WorkspaceID)
+
+	var bobCategories int64
+	require.NoError(t, ts.DB.Model(&entity.Category{}).Where("workspace_id = ?", bob.WorkspaceID).Count(&bobCategories).Error)
+	assert.Equal(t, int64(1), bobCategories)
+}
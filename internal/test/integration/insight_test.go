@@ -0,0 +1,69 @@
+package integration
+
+import (
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsightsFlagUnusualExpense(t *testing.T) {
+	ts := setupTestServer(t)
+	defer cleanupDatabase(ts.DB)
+
+	fixture := createResourceFixture(t, ts, "alice")
+	var user entity.User
+	require.NoError(t, ts.DB.Where("username = ?", "alice").First(&user).Error)
+	var category entity.Category
+	require.NoError(t, ts.DB.First(&category, fixture.CategoryID).Error)
+
+	scope := request.WorkspaceScope{WorkspaceID: category.WorkspaceID, UserID: user.ID, Role: entity.WorkspaceRoleOwner}
+	events := service.NewWorkspaceEvents()
+	transactionService := service.NewTransactionService(ts.DB)
+	transactionService.Events = events
+	insightService := service.NewInsightService(ts.DB, events)
+
+	today := time.Date(2024, time.August, 20, 0, 0, 0, 0, time.UTC)
+	for _, day := range []string{"2024-05-03", "2024-05-20", "2024-06-04", "2024-06-21", "2024-07-05", "2024-07-22"} {
+		_, err := transactionService.CreateTransaction(scope, request.CreateTransactionRequest{
+			CategoryID: category.ID, Amount: 100, Type: "expense", Date: day,
+		})
+		require.NoError(t, err)
+	}
+	large, err := transactionService.CreateTransaction(scope, request.CreateTransactionRequest{
+		CategoryID: category.ID, Amount: 450, Type: "expense", Date: "2024-08-18",
+	})
+	require.NoError(t, err)
+
+	// perubahan transaksi menandai workspace sehingga putaran worker berikutnya menganalisisnya
+	insightService.RunPendingAnalysis(today.Add(time.Hour))
+
+	insights, err := insightService.GetInsights(scope, request.InsightFilter{Month: "2024-08"})
+	require.NoError(t, err)
+	kinds := map[string]response.InsightResponse{}
+	for _, insight := range insights {
+		kinds[insight.Kind] = insight
+	}
+	require.Contains(t, kinds, entity.AnomalyLargeTransaction)
+	assert.Equal(t, large.ID, *kinds[entity.AnomalyLargeTransaction].TransactionID)
+	assert.Equal(t, 4.5, kinds[entity.AnomalyLargeTransaction].Ratio)
+	// 450 dari 20/31 x 200 sebulan
+	assert.Contains(t, kinds, entity.AnomalyCategoryPace)
+
+	list, err := transactionService.GetTransactionByUser(scope, request.TransactionFilter{Page: 1, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, list.Transactions[0].Anomalies, 1)
+	assert.Equal(t, entity.AnomalyLargeTransaction, list.Transactions[0].Anomalies[0].Kind)
+
+	// transaksi yang dihapus tidak lagi muncul di insights setelah dianalisis ulang
+	require.NoError(t, transactionService.DeleteTransaction(scope, large.ID))
+	require.NoError(t, insightService.AnalyzeWorkspace(scope.WorkspaceID, today))
+	insights, err = insightService.GetInsights(scope, request.InsightFilter{Month: "2024-08"})
+	require.NoError(t, err)
+	assert.Empty(t, insights)
+}
@@ -52,6 +52,25 @@ func setupTestDatabase() (*gorm.DB, error) {
 		&entity.User{},
 		&entity.Category{},
 		&entity.Transaction{},
+		&entity.Session{},
+		&entity.PasswordReset{},
+		&entity.EmailVerification{},
+		&entity.RecoveryCode{},
+		&entity.LoginAttempt{},
+		&entity.SecurityEvent{},
+		&entity.APIToken{},
+		&entity.UserActivity{},
+		&entity.ChatUsage{},
+		&entity.Workspace{},
+		&entity.WorkspaceMember{},
+		&entity.WorkspaceInvitation{},
+		&entity.OAuthState{},
+		&entity.UserIdentity{},
+		&entity.UserPreference{},
+		&entity.DataExport{},
+		&entity.MonthlyAggregate{},
+		&entity.SpendingAnomaly{},
+		&entity.NetWorthSnapshot{},
 	); err != nil {
 		return nil, fmt.Errorf("failed to run auto migration: %v", err)
 	}
@@ -94,7 +113,7 @@ func setupTestServer(t *testing.T) *TestServer {
 func cleanupDatabase(db *gorm.DB) error {
 	// Hapus semua data dari tabel-tabel
 	if err := db.Exec(`
-        TRUNCATE TABLE users, categories, transactions CASCADE;
+        TRUNCATE TABLE users, categories, transactions, sessions, password_resets, email_verifications, recovery_codes, login_attempts, security_events, api_tokens, user_activities, chat_usages, workspaces, workspace_members, workspace_invitations, oauth_states, user_identities, user_preferences, data_exports, monthly_aggregates, spending_anomalies, net_worth_snapshots CASCADE;
     `).Error; err != nil {
 		return fmt.Errorf("failed to cleanup database: %v", err)
 	}
@@ -0,0 +1,38 @@
+package integration
+
+import (
+	"go-fintrack/config"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/middleware"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnverifiedEmailCannotExportPersonalData(t *testing.T) {
+	ts := setupTestServer(t)
+	defer cleanupDatabase(ts.DB)
+	setupResourceRoutes(ts)
+
+	previous := config.UnverifiedRestrictions
+	config.UnverifiedRestrictions = map[string]bool{middleware.FeatureExport: true}
+	t.Cleanup(func() { config.UnverifiedRestrictions = previous })
+
+	alice := createResourceFixture(t, ts, "alice")
+
+	w := doAuthedRequest(ts, http.MethodPost, "/api/me/exports", alice.Token, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	w = doAuthedRequest(ts, http.MethodGet, "/api/me/exports/1/download", alice.Token, nil)
+	assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+
+	var exports int64
+	require.NoError(t, ts.DB.Model(&entity.DataExport{}).Where("user_id = ?", alice.UserID).Count(&exports).Error)
+	assert.Zero(t, exports)
+
+	// setelah email diverifikasi export bisa diminta
+	require.NoError(t, ts.DB.Model(&entity.User{}).Where("id = ?", alice.UserID).Update("email_verified", true).Error)
+	w = doAuthedRequest(ts, http.MethodPost, "/api/me/exports", alice.Token, nil)
+	assert.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+}
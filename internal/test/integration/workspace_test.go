@@ -0,0 +1,73 @@
+package integration
+
+import (
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// invitationToken mengambil token dari satu-satunya email undangan di outbox
+func invitationToken(t *testing.T, outbox string) string {
+	files, err := filepath.Glob(filepath.Join(outbox, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	match := regexp.MustCompile(`/workspace-invite\?token=(\S+)`).FindStringSubmatch(string(content))
+	require.Len(t, match, 2)
+	token, err := url.QueryUnescape(match[1])
+	require.NoError(t, err)
+	return token
+}
+
+func TestRemovedMemberCanRejoin(t *testing.T) {
+	ts := setupTestServer(t)
+	defer cleanupDatabase(ts.DB)
+
+	alice := createResourceFixture(t, ts, "alice")
+	bob := createResourceFixture(t, ts, "bob")
+
+	workspaceService := service.NewWorkspaceService(ts.DB, nil, "http://localhost:3000")
+	household, err := workspaceService.CreateWorkspace(alice.UserID, "household")
+	require.NoError(t, err)
+
+	invite := func() {
+		outbox := t.TempDir()
+		workspaceService.EmailSender = &utility.FileSender{Dir: outbox, From: "no-reply@test.local"}
+		_, err := workspaceService.InviteMember(alice.UserID, household.ID, "bob@example.com", entity.WorkspaceRoleEditor)
+		require.NoError(t, err)
+		_, err = workspaceService.AcceptInvitation(bob.UserID, invitationToken(t, outbox))
+		require.NoError(t, err)
+	}
+
+	invite()
+	require.NoError(t, workspaceService.RemoveMember(alice.UserID, household.ID, bob.UserID))
+	_, err = workspaceService.ResolveMembership(bob.UserID, household.ID)
+	assert.Equal(t, service.ErrWorkspaceAccessDenied, err)
+
+	// diundang lagi setelah dikeluarkan
+	invite()
+	member, err := workspaceService.ResolveMembership(bob.UserID, household.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.WorkspaceRoleEditor, member.Role)
+
+	// baris member soft delete dari versi sebelumnya tidak menghalangi undangan baru
+	require.NoError(t, ts.DB.Delete(member).Error)
+	invite()
+	_, err = workspaceService.ResolveMembership(bob.UserID, household.ID)
+	assert.NoError(t, err)
+
+	var rows int64
+	require.NoError(t, ts.DB.Unscoped().Model(&entity.WorkspaceMember{}).
+		Where("workspace_id = ? AND user_id = ?", household.ID, bob.UserID).Count(&rows).Error)
+	assert.Equal(t, int64(1), rows)
+}
@@ -0,0 +1,49 @@
+package unit
+
+import (
+	"go-fintrack/internal/service"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAccountService(t *testing.T) (*service.AccountService, sqlmock.Sqlmock) {
+	db, mock := setupTestDB(t)
+	return service.NewAccountService(db, nil, "", t.TempDir(), t.TempDir(), 14*24*time.Hour), mock
+}
+
+func TestRequestExportAlreadyInProgress(t *testing.T) {
+	accountService, mock := newTestAccountService(t)
+
+	mock.ExpectQuery("SELECT count(.+) FROM `data_exports`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	_, err := accountService.RequestExport(1)
+	assert.Equal(t, service.ErrDataExportInProgress, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetExportFileNotReady(t *testing.T) {
+	accountService, mock := newTestAccountService(t)
+
+	mock.ExpectQuery("SELECT (.+) FROM `data_exports`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "status"}).AddRow(1, 1, "processing"))
+
+	_, err := accountService.GetExportFile(1, 1)
+	assert.Equal(t, service.ErrDataExportUnavailable, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCancelDeletionNotScheduled(t *testing.T) {
+	accountService, mock := newTestAccountService(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `users` SET `deletion_scheduled_for`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	assert.Equal(t, service.ErrDeletionNotScheduled, accountService.CancelDeletion(1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminCannotModifySelf(t *testing.T) {
+	db, mock := setupTestDB(t)
+	adminService := service.NewAdminService(db, &service.UserService{DB: db})
+
+	// aksi terhadap akun sendiri ditolak tanpa query ke database
+	assert.Equal(t, service.ErrCannotModifySelf, adminService.SuspendUser(1, 1, ""))
+	assert.Equal(t, service.ErrCannotModifySelf, adminService.DeleteUser(1, 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAdminSuspendUnknownUser(t *testing.T) {
+	db, mock := setupTestDB(t)
+	adminService := service.NewAdminService(db, &service.UserService{DB: db})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	assert.Equal(t, service.ErrUserNotFound, adminService.SuspendUser(1, 2, "spam"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAdminCannotReactivateStaffWithoutRoleAssign(t *testing.T) {
+	db, mock := setupTestDB(t)
+	adminService := service.NewAdminService(db, &service.UserService{DB: db})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "suspended_at"}).AddRow(2, entity.RoleSuperadmin, time.Now()))
+	mock.ExpectQuery("SELECT (.+) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role"}).AddRow(1, entity.RoleAdmin))
+	mock.ExpectRollback()
+
+	assert.Equal(t, service.ErrInsufficientRole, adminService.ReactivateUser(1, 2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAdminForcePasswordResetRevokesAPITokens(t *testing.T) {
+	db, mock := setupTestDB(t)
+	adminService := service.NewAdminService(db, &service.UserService{DB: db, EmailSender: &utility.FileSender{Dir: t.TempDir(), From: "no-reply@test.local"}})
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role", "email", "name"}).AddRow(2, entity.RoleUser, "user@example.com", "User"))
+	mock.ExpectExec("UPDATE `users` SET `password_reset_required`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `sessions` SET `revoked_at`").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("UPDATE `api_tokens` SET `deleted_at`").WithArgs(sqlmock.AnyArg(), 2).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("INSERT INTO `password_resets`").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO `security_events`").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, adminService.ForcePasswordReset(1, 2))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAdminListUsers(t *testing.T) {
+	db, mock := setupTestDB(t)
+	adminService := service.NewAdminService(db, &service.UserService{DB: db})
+
+	now := time.Now()
+	mock.ExpectQuery("SELECT count(.+) FROM `users` WHERE \\(LOWER\\(name\\) LIKE (.+)\\) AND suspended_at IS NOT NULL").
+		WithArgs("%john%", "%john%", "%john%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(11))
+	mock.ExpectQuery("SELECT (.+) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "username", "provider", "suspended_at", "created_at"}).
+			AddRow(2, "John", "john@example.com", "john", "email", now, now))
+
+	result, err := adminService.ListUsers(request.AdminUserFilter{
+		Search: " John ",
+		Status: "suspended",
+		Page:   2,
+		Limit:  10,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, result.Users, 1)
+	assert.True(t, result.Users[0].Suspended)
+	assert.Equal(t, 2, result.Pagination.TotalPage)
+	assert.Equal(t, int64(11), result.Pagination.TotalItems)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCheckAccountStatus(t *testing.T) {
+	userService := &service.UserService{}
+	now := time.Now()
+
+	assert.NoError(t, userService.CheckAccountStatus(&entity.User{}))
+	assert.Equal(t, service.ErrAccountSuspended, userService.CheckAccountStatus(&entity.User{SuspendedAt: &now}))
+	assert.Equal(t, service.ErrPasswordResetRequired, userService.CheckAccountStatus(&entity.User{PasswordResetRequired: true}))
+}
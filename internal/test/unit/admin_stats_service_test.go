@@ -0,0 +1,52 @@
+package unit
+
+import (
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSignupStatsFillsEmptyPeriods(t *testing.T) {
+	db, mock := setupTestDB(t)
+	statsService := service.NewAdminStatsService(db)
+
+	mock.ExpectQuery("SELECT TO_CHAR(.+) AS period, provider, COUNT(.+) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"period", "provider", "total"}).
+			AddRow("2024-01", "email", 3).
+			AddRow("2024-01", "google", 2).
+			AddRow("2024-03", "google", 1))
+
+	stats, err := statsService.GetSignupStats(request.AdminStatsFilter{
+		StartDate:   "2024-01-15",
+		EndDate:     "2024-03-02",
+		Granularity: "month",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-01-01", stats.StartDate)
+	assert.Equal(t, "2024-03-31", stats.EndDate)
+	assert.Len(t, stats.Points, 3)
+	assert.Equal(t, int64(5), stats.Points[0].Total)
+	assert.Equal(t, int64(2), stats.Points[0].ByProvider["google"])
+	assert.Equal(t, int64(0), stats.Points[1].Total)
+	assert.Equal(t, int64(1), stats.Points[2].Total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStatsInvalidRange(t *testing.T) {
+	db, mock := setupTestDB(t)
+	statsService := service.NewAdminStatsService(db)
+
+	filters := []request.AdminStatsFilter{
+		{StartDate: "2024-03-01", EndDate: "2024-01-01"},
+		{StartDate: "2020-01-01", EndDate: "2024-01-01", Granularity: "day"},
+		{StartDate: "01-01-2024"},
+	}
+	for _, filter := range filters {
+		_, err := statsService.GetTransactionVolumeStats(filter)
+		assert.Equal(t, service.ErrInvalidStatsRange, err)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,124 @@
+package unit
+
+import (
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var calendarCycle = utility.PeriodConfig{CycleStartDay: 1}
+
+func TestMedian(t *testing.T) {
+	assert.Equal(t, 0.0, utility.Median(nil))
+	assert.Equal(t, 3.0, utility.Median([]float64{5, 1, 3}))
+	assert.Equal(t, 2.5, utility.Median([]float64{4, 1, 3, 2}))
+}
+
+func TestDetectLargeTransactions(t *testing.T) {
+	expenses := []utility.ExpenseRecord{
+		{TransactionID: 1, CategoryID: 1, Amount: 50, Date: date("2024-06-01")},
+		{TransactionID: 2, CategoryID: 1, Amount: 60, Date: date("2024-06-10")},
+		{TransactionID: 3, CategoryID: 1, Amount: 40, Date: date("2024-06-20")},
+		{TransactionID: 4, CategoryID: 1, Amount: 55, Date: date("2024-07-01")},
+		{TransactionID: 5, CategoryID: 1, Amount: 45, Date: date("2024-07-15")},
+		// median 50, 149 belum 3x
+		{TransactionID: 6, CategoryID: 1, Amount: 149, Date: date("2024-08-02")},
+		{TransactionID: 7, CategoryID: 1, Amount: 400, Date: date("2024-08-05")},
+		// kategori dengan riwayat kurang dari 5 transaksi tidak dinilai
+		{TransactionID: 8, CategoryID: 2, Amount: 10, Date: date("2024-07-01")},
+		{TransactionID: 9, CategoryID: 2, Amount: 900, Date: date("2024-08-05")},
+	}
+
+	anomalies := utility.DetectLargeTransactions(expenses, date("2024-08-01"), calendarCycle)
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, entity.AnomalyLargeTransaction, anomalies[0].Kind)
+	assert.Equal(t, uint(7), *anomalies[0].TransactionID)
+	// median riwayat 50, 55, 45, 40, 60, 149 adalah 52.5
+	assert.Equal(t, 52.5, anomalies[0].Baseline)
+	assert.Equal(t, 7.62, anomalies[0].Ratio)
+	assert.Equal(t, date("2024-08-01"), anomalies[0].Month)
+
+	// siklus tanggal 25: 5 Agu masih masuk siklus bulan juli
+	anomalies = utility.DetectLargeTransactions(expenses, date("2024-08-01"), utility.PeriodConfig{CycleStartDay: 25})
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, date("2024-07-01"), anomalies[0].Month)
+}
+
+func TestDetectCategoryPace(t *testing.T) {
+	history := map[uint][]float64{
+		1: {300, 310, 290},
+		2: {300, 300, 300},
+		3: {300, 300}, // riwayat kurang dari 3 bulan
+	}
+
+	// 15 dari 30 hari: laju normal 150, kategori 1 sudah 240 (60% di atas), kategori 2 masih 200
+	today := date("2024-06-15")
+	anomalies := utility.DetectCategoryPace(map[uint]float64{1: 240, 2: 200, 3: 1000}, history, today, calendarCycle)
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, uint(1), anomalies[0].CategoryID)
+	assert.Equal(t, 150.0, anomalies[0].Baseline)
+	assert.Equal(t, 1.6, anomalies[0].Ratio)
+
+	// awal bulan laju dihitung dari 7 hari: 300 * 7/30 = 70
+	anomalies = utility.DetectCategoryPace(map[uint]float64{2: 100}, history, date("2024-06-02"), calendarCycle)
+	assert.Empty(t, anomalies)
+	anomalies = utility.DetectCategoryPace(map[uint]float64{2: 105}, history, date("2024-06-02"), calendarCycle)
+	assert.Len(t, anomalies, 1)
+}
+
+func TestDetectCategoryPaceFollowsCycle(t *testing.T) {
+	history := map[uint][]float64{1: {300, 300, 300}}
+	cycle := utility.PeriodConfig{CycleStartDay: 25}
+
+	// siklus 25 Jun - 24 Jul (30 hari), 9 Jul adalah hari ke-15: laju normal 150
+	anomalies := utility.DetectCategoryPace(map[uint]float64{1: 240}, history, date("2024-07-09"), cycle)
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, 150.0, anomalies[0].Baseline)
+	assert.Equal(t, date("2024-06-01"), anomalies[0].Month)
+
+	// bulan kalender hanya berjalan 9 hari sehingga 150 sudah dianggap di atas laju, siklus belum
+	assert.Len(t, utility.DetectCategoryPace(map[uint]float64{1: 150}, history, date("2024-07-09"), calendarCycle), 1)
+	assert.Empty(t, utility.DetectCategoryPace(map[uint]float64{1: 150}, history, date("2024-07-09"), cycle))
+}
+
+func TestAnalyzeWorkspaceUsesOwnerPreference(t *testing.T) {
+	db, mock := setupTestDB(t)
+	insightService := service.NewInsightService(db, service.NewWorkspaceEvents())
+
+	mock.ExpectQuery("SELECT `id`,`owner_id` FROM `workspaces`").
+		WithArgs(1, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner_id"}).AddRow(1, 7))
+	mock.ExpectQuery("FROM `user_preferences`").
+		WithArgs(7, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "timezone", "cycle_start_day", "first_day_of_week", "locale"}).
+			AddRow(1, 7, "Asia/Jakarta", 25, 1, "id-ID"))
+	// 24 Jul 20:00 UTC sudah 25 Jul di Jakarta, hari pertama siklus bulan juli
+	mock.ExpectQuery("FROM `transactions`").
+		WithArgs(1, sqlmock.AnyArg(), date("2024-07-25")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "category_id", "amount", "date"}).
+			AddRow(1, 1, 300.0, date("2024-05-01")).
+			AddRow(2, 1, 300.0, date("2024-06-01")).
+			AddRow(3, 1, 300.0, date("2024-07-01")).
+			AddRow(4, 1, 200.0, date("2024-07-25")))
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM `spending_anomalies`").
+		WithArgs(1, entity.AnomalyLargeTransaction, date("2024-06-26"), entity.AnomalyCategoryPace, date("2024-07-01")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec("DELETE FROM `spending_anomalies`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	// 1 Jul masuk siklus juni sehingga ada 3 siklus riwayat, laju normal 300 * 7/31
+	mock.ExpectExec("INSERT INTO `spending_anomalies`").
+		WithArgs(1, entity.AnomalyCategoryPace, nil, 1, date("2024-07-25"), date("2024-07-01"), 200.0, 300.0*7/31, 2.95, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := insightService.AnalyzeWorkspace(1, time.Date(2024, time.July, 24, 20, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
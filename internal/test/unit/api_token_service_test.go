@@ -0,0 +1,90 @@
+package unit
+
+import (
+	"errors"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateAPITokenInvalidScope(t *testing.T) {
+	db, mock := setupTestDB(t)
+	apiTokenService := service.NewAPITokenService(db)
+
+	// scope tidak dikenal ditolak sebelum menyentuh database
+	_, err := apiTokenService.CreateToken(1, request.CreateAPITokenRequest{
+		Name:   "script",
+		Scopes: []string{service.ScopeTransactionsRead, "admin"},
+	})
+	assert.True(t, errors.Is(err, service.ErrInvalidAPITokenScope))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuthenticateAPIToken(t *testing.T) {
+	db, mock := setupTestDB(t)
+	apiTokenService := service.NewAPITokenService(db)
+
+	columns := []string{"id", "user_id", "token_hash", "scopes", "expires_at", "last_used_at"}
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "Valid token recently used",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM `api_tokens`").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, 1, "hash", "transactions:read", nil, now))
+			},
+		},
+		{
+			name: "Expired token",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM `api_tokens`").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, 1, "hash", "transactions:read", now.Add(-time.Hour), now))
+			},
+			expectedError: service.ErrInvalidAPIToken,
+		},
+		{
+			name: "Unknown or revoked token",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM `api_tokens`").
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			expectedError: service.ErrInvalidAPIToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock(mock)
+
+			token, err := apiTokenService.Authenticate(service.APITokenPrefix + "secret")
+			if tt.expectedError != nil {
+				assert.Equal(t, tt.expectedError, err)
+				assert.Nil(t, token)
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, token.HasScope(service.ScopeTransactionsRead))
+				assert.False(t, token.HasScope(service.ScopeTransactionsWrite))
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAPITokenScopeList(t *testing.T) {
+	token := entity.APIToken{Scopes: "transactions:read,export"}
+	assert.Equal(t, []string{"transactions:read", "export"}, token.ScopeList())
+	empty := entity.APIToken{}
+	assert.Empty(t, empty.ScopeList())
+}
@@ -61,18 +61,19 @@ func (suite *CategoryServiceTestSuite) TearDownTest() {
 
 func (suite *CategoryServiceTestSuite) TestGetCategories() {
 	userID := uint(1)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: userID}
 	now := time.Now()
 
-	query := "SELECT * FROM `categories` WHERE user_id = ? AND `categories`.`deleted_at` IS NULL"
+	query := "SELECT * FROM `categories` WHERE workspace_id = ? AND `categories`.`deleted_at` IS NULL"
 	rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "name", "user_id"}).
 		AddRow(1, now, now, nil, "food", userID).
 		AddRow(2, now, now, nil, "transport", userID)
 
 	suite.mock.ExpectQuery(query).
-		WithArgs(userID).
+		WithArgs(scope.WorkspaceID).
 		WillReturnRows(rows)
 
-	categories, err := suite.service.GetCategories(userID)
+	categories, err := suite.service.GetCategories(scope)
 
 	assert.NoError(suite.T(), err)
 	assert.Len(suite.T(), categories, 2)
@@ -84,24 +85,25 @@ func (suite *CategoryServiceTestSuite) TestGetCategories() {
 
 func (suite *CategoryServiceTestSuite) TestCreateCategory() {
 	userID := uint(1)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: userID}
 	name := "groceries"
 
 	// Check existing
-	checkQuery := "SELECT * FROM `categories` WHERE (LOWER(name) = ? AND user_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?"
+	checkQuery := "SELECT * FROM `categories` WHERE (LOWER(name) = ? AND workspace_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?"
 	suite.mock.ExpectQuery(checkQuery).
-		WithArgs("groceries", userID, 1).
+		WithArgs("groceries", scope.WorkspaceID, 1).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	// Create
 	suite.mock.ExpectBegin()
-	createQuery := "INSERT INTO `categories` (`created_at`,`updated_at`,`deleted_at`,`user_id`,`name`) VALUES (?,?,?,?,?)"
+	createQuery := "INSERT INTO `categories` (`created_at`,`updated_at`,`deleted_at`,`workspace_id`,`user_id`,`name`) VALUES (?,?,?,?,?,?)"
 	suite.mock.ExpectExec(createQuery).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, name).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, scope.WorkspaceID, userID, name).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	suite.mock.ExpectCommit()
 
 	req := &request.CategoryRequest{Name: name}
-	category, err := suite.service.CreateCategory(req, userID)
+	category, err := suite.service.CreateCategory(req, scope)
 
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), category)
@@ -113,34 +115,35 @@ func (suite *CategoryServiceTestSuite) TestCreateCategory() {
 func (suite *CategoryServiceTestSuite) TestUpdateCategory() {
 	categoryID := uint(1)
 	userID := uint(1)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: userID}
 	oldName := "food"
 	newName := "updated food"
 	now := time.Now()
 
 	// Get existing category
-	getQuery := "SELECT * FROM `categories` WHERE (id = ? AND user_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?"
-	getRows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "name", "user_id"}).
-		AddRow(categoryID, now, now, nil, oldName, userID)
+	getQuery := "SELECT * FROM `categories` WHERE (id = ? AND workspace_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?"
+	getRows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "name", "workspace_id", "user_id"}).
+		AddRow(categoryID, now, now, nil, oldName, scope.WorkspaceID, userID)
 	suite.mock.ExpectQuery(getQuery).
-		WithArgs(categoryID, userID, 1).
+		WithArgs(categoryID, scope.WorkspaceID, 1).
 		WillReturnRows(getRows)
 
 	// Check duplicate name
-	checkQuery := "SELECT * FROM `categories` WHERE (LOWER(name) = ? AND user_id = ? AND id != ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?"
+	checkQuery := "SELECT * FROM `categories` WHERE (LOWER(name) = ? AND workspace_id = ? AND id != ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?"
 	suite.mock.ExpectQuery(checkQuery).
-		WithArgs(newName, userID, categoryID, 1).
+		WithArgs(newName, scope.WorkspaceID, categoryID, 1).
 		WillReturnError(gorm.ErrRecordNotFound)
 
 	// Update
 	suite.mock.ExpectBegin()
-	updateQuery := "UPDATE `categories` SET `created_at`=?,`updated_at`=?,`deleted_at`=?,`user_id`=?,`name`=? WHERE `categories`.`deleted_at` IS NULL AND `id` = ?"
+	updateQuery := "UPDATE `categories` SET `created_at`=?,`updated_at`=?,`deleted_at`=?,`workspace_id`=?,`user_id`=?,`name`=? WHERE `categories`.`deleted_at` IS NULL AND `id` = ?"
 	suite.mock.ExpectExec(updateQuery).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, newName, categoryID).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, scope.WorkspaceID, userID, newName, categoryID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	suite.mock.ExpectCommit()
 
 	req := &request.UpdateCategoryRequest{Name: newName}
-	result, err := suite.service.UpdateCategory(categoryID, userID, req)
+	result, err := suite.service.UpdateCategory(categoryID, scope, req)
 
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), result)
@@ -152,16 +155,17 @@ func (suite *CategoryServiceTestSuite) TestUpdateCategory() {
 func (suite *CategoryServiceTestSuite) TestDeleteCategory() {
 	categoryID := uint(1)
 	userID := uint(1)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: userID}
 
 	// Mock soft delete
 	suite.mock.ExpectBegin()
-	deleteQuery := "UPDATE `categories` SET `deleted_at`=? WHERE (id = ? AND user_id = ?) AND `categories`.`deleted_at` IS NULL"
+	deleteQuery := "UPDATE `categories` SET `deleted_at`=? WHERE (id = ? AND workspace_id = ?) AND `categories`.`deleted_at` IS NULL"
 	suite.mock.ExpectExec(deleteQuery).
-		WithArgs(sqlmock.AnyArg(), categoryID, userID).
+		WithArgs(sqlmock.AnyArg(), categoryID, scope.WorkspaceID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	suite.mock.ExpectCommit()
 
-	err := suite.service.DeleteCategory(categoryID, userID)
+	err := suite.service.DeleteCategory(categoryID, scope)
 	assert.NoError(suite.T(), err)
 }
 
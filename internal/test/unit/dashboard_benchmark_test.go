@@ -0,0 +1,127 @@
+package unit
+
+import (
+	"context"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"sync/atomic"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// Jumlah query dashboard dicek langsung oleh TestDashboardQueryCounts, benchmark melaporkan waktu dan
+// queries/op per request. Jalankan dengan: go test ./internal/test/unit -run '^$' -bench Dashboard
+
+// countQueries menghitung query yang dijalankan gorm lewat callback query dan row. Subquery dirender gorm
+// dalam mode DryRun sehingga tidak ikut terhitung.
+func countQueries(tb testing.TB, db *gorm.DB) *int64 {
+	var count int64
+	increment := func(tx *gorm.DB) {
+		if !tx.DryRun {
+			atomic.AddInt64(&count, 1)
+		}
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("test:count_query", increment); err != nil {
+		tb.Fatal(err)
+	}
+	if err := db.Callback().Row().After("gorm:row").Register("test:count_row", increment); err != nil {
+		tb.Fatal(err)
+	}
+	return &count
+}
+
+func reportQueries(b *testing.B, count *int64) {
+	b.ReportMetric(float64(atomic.LoadInt64(count))/float64(b.N), "queries/op")
+}
+
+var (
+	overviewFilter = request.DashboardFilter{StartDate: "2024-08-01", EndDate: "2024-08-31", Granularity: "month"}
+	chartsFilter   = request.DashboardFilter{StartDate: "2024-03-01", EndDate: "2024-08-31", Granularity: "month"}
+)
+
+// expectOverviewQueries: preferensi user lalu satu query saldo, income dan expense
+func expectOverviewQueries(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("AS balance").
+		WillReturnRows(sqlmock.NewRows([]string{"balance", "income", "expense"}).AddRow(100.0, 50.0, 20.0))
+}
+
+// expectChartsQueries: preferensi user, satu query total per bucket dan tipe, satu query total per kategori
+func expectChartsQueries(mock sqlmock.Sqlmock) {
+	mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("AS bucket").
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "type", "total"}).AddRow(0, "income", 100.0).AddRow(5, "expense", 40.0))
+	mock.ExpectQuery("categories.name").
+		WillReturnRows(sqlmock.NewRows([]string{"category_name", "total"}).AddRow("Food", 80.0))
+}
+
+func TestDashboardQueryCounts(t *testing.T) {
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: 1}
+
+	t.Run("Overview", func(t *testing.T) {
+		db, mock := setupTestDB(t)
+		count := countQueries(t, db)
+		expectOverviewQueries(mock)
+
+		_, err := service.NewDashboardService(db).GetFinancialOverview(context.Background(), scope, overviewFilter)
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(2), atomic.LoadInt64(count))
+	})
+
+	t.Run("Charts", func(t *testing.T) {
+		db, mock := setupTestDB(t)
+		mock.MatchExpectationsInOrder(false)
+		count := countQueries(t, db)
+		expectChartsQueries(mock)
+
+		// jumlah query tidak bertambah dengan banyaknya bucket (6 bulan)
+		_, err := service.NewDashboardService(db).GetDashboardCharts(context.Background(), scope, chartsFilter)
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.Equal(t, int64(3), atomic.LoadInt64(count))
+	})
+}
+
+func BenchmarkDashboardOverview(b *testing.B) {
+	db, mock := setupTestDB(b)
+	count := countQueries(b, db)
+	dashboardService := service.NewDashboardService(db)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: 1}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		expectOverviewQueries(mock)
+		b.StartTimer()
+
+		if _, err := dashboardService.GetFinancialOverview(context.Background(), scope, overviewFilter); err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportQueries(b, count)
+}
+
+func BenchmarkDashboardCharts(b *testing.B) {
+	db, mock := setupTestDB(b)
+	mock.MatchExpectationsInOrder(false)
+	count := countQueries(b, db)
+	dashboardService := service.NewDashboardService(db)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: 1}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		expectChartsQueries(mock)
+		b.StartTimer()
+
+		if _, err := dashboardService.GetDashboardCharts(context.Background(), scope, chartsFilter); err != nil {
+			b.Fatal(err)
+		}
+	}
+	reportQueries(b, count)
+}
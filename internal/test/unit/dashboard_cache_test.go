@@ -0,0 +1,129 @@
+package unit
+
+import (
+	"context"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestLRUDashboardCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := service.NewLRUDashboardCache(2, time.Minute)
+	cache.Set("dashboard:1:a", []byte("a"))
+	cache.Set("dashboard:1:b", []byte("b"))
+
+	// a baru dipakai sehingga b yang dikeluarkan saat c masuk
+	_, ok := cache.Get("dashboard:1:a")
+	require.True(t, ok)
+	cache.Set("dashboard:2:c", []byte("c"))
+
+	_, ok = cache.Get("dashboard:1:b")
+	assert.False(t, ok)
+	_, ok = cache.Get("dashboard:1:a")
+	assert.True(t, ok)
+
+	cache.DeletePrefix("dashboard:1:")
+	_, ok = cache.Get("dashboard:1:a")
+	assert.False(t, ok)
+	value, ok := cache.Get("dashboard:2:c")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("c"), value)
+}
+
+func TestLRUDashboardCacheExpires(t *testing.T) {
+	cache := service.NewLRUDashboardCache(10, time.Millisecond)
+	cache.Set("dashboard:1:a", []byte("a"))
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := cache.Get("dashboard:1:a")
+	assert.False(t, ok)
+}
+
+func TestCachedDashboardInvalidatedByWorkspaceEvents(t *testing.T) {
+	db, mock := setupTestDB(t)
+	events := service.NewWorkspaceEvents()
+	dashboardService := service.NewCachedDashboardService(service.NewDashboardService(db),
+		service.NewLRUDashboardCache(10, time.Minute), events)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: 1}
+	filter := request.DashboardFilter{StartDate: "2024-08-01", EndDate: "2024-08-31"}
+
+	expectOverview := func(balance float64) {
+		mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery("AS balance").
+			WillReturnRows(sqlmock.NewRows([]string{"balance", "income", "expense"}).AddRow(balance, 50.0, 20.0))
+	}
+
+	expectOverview(100)
+	first, err := dashboardService.GetFinancialOverview(context.Background(), scope, filter)
+	require.NoError(t, err)
+
+	// request kedua hanya membaca preferensi, totals diambil dari cache dengan ETag yang sama
+	mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	cached, err := dashboardService.GetFinancialOverview(context.Background(), scope, filter)
+	require.NoError(t, err)
+	assert.Equal(t, first.ETag, cached.ETag)
+
+	// perubahan di workspace lain tidak menghapus cache workspace 1
+	events.Publish(2)
+	mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = dashboardService.GetFinancialOverview(context.Background(), scope, filter)
+	require.NoError(t, err)
+
+	events.Publish(1)
+	expectOverview(150)
+	fresh, err := dashboardService.GetFinancialOverview(context.Background(), scope, filter)
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ETag, fresh.ETag)
+	assert.Contains(t, string(fresh.Body), `"current_balance":150`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCachedDashboardSkipsResultComputedBeforeInvalidate(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardService := service.NewCachedDashboardService(service.NewDashboardService(db),
+		service.NewLRUDashboardCache(10, time.Minute), service.NewWorkspaceEvents())
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: 1}
+	filter := request.DashboardFilter{StartDate: "2024-08-01", EndDate: "2024-08-31"}
+
+	// transaksi berubah tepat setelah totals lama dibaca, sebelum hasilnya masuk cache
+	invalidateOnce := true
+	invalidate := func(tx *gorm.DB) {
+		if invalidateOnce && strings.Contains(tx.Statement.SQL.String(), "AS balance") {
+			invalidateOnce = false
+			dashboardService.Invalidate(scope.WorkspaceID)
+		}
+	}
+	require.NoError(t, db.Callback().Query().After("gorm:query").Register("test:invalidate_query", invalidate))
+	require.NoError(t, db.Callback().Row().After("gorm:row").Register("test:invalidate_row", invalidate))
+
+	expectOverview := func(balance float64) {
+		mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectQuery("AS balance").
+			WillReturnRows(sqlmock.NewRows([]string{"balance", "income", "expense"}).AddRow(balance, 50.0, 20.0))
+	}
+
+	expectOverview(100)
+	stale, err := dashboardService.GetFinancialOverview(context.Background(), scope, filter)
+	require.NoError(t, err)
+	assert.Contains(t, string(stale.Body), `"current_balance":100`)
+
+	// hasil lama tidak disimpan sehingga request berikutnya menghitung ulang
+	expectOverview(150)
+	fresh, err := dashboardService.GetFinancialOverview(context.Background(), scope, filter)
+	require.NoError(t, err)
+	assert.Contains(t, string(fresh.Body), `"current_balance":150`)
+
+	// tanpa invalidasi baru hasil terakhir dipakai dari cache
+	mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	cached, err := dashboardService.GetFinancialOverview(context.Background(), scope, filter)
+	require.NoError(t, err)
+	assert.Equal(t, fresh.ETag, cached.ETag)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,102 @@
+package unit
+
+import (
+	"context"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPeriodComparison(t *testing.T) {
+	db, mock := setupTestDB(t)
+	// kedua query kategori berjalan paralel
+	mock.MatchExpectationsInOrder(false)
+	dashboardService := service.NewDashboardService(db)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: 1}
+
+	expectTotals := func(start, end string, rows *sqlmock.Rows) {
+		from, to := date(start), date(end)
+		mock.ExpectQuery("AS totals").
+			WithArgs(1, "expense", from, to, 1, "expense", from, to, from, to).
+			WillReturnRows(rows)
+	}
+	mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	expectTotals("2024-08-01", "2024-09-01", sqlmock.NewRows([]string{"category_id", "category_name", "total"}).
+		AddRow(1, "food", 300.0).
+		AddRow(2, "travel", 800.0).
+		AddRow(3, "rent", 1000.0))
+	expectTotals("2024-07-01", "2024-08-01", sqlmock.NewRows([]string{"category_id", "category_name", "total"}).
+		AddRow(1, "food", 400.0).
+		AddRow(3, "rent", 1000.0).
+		AddRow(4, "gym", 50.0))
+
+	comparison, err := dashboardService.GetPeriodComparison(context.Background(), scope, request.ComparisonFilter{
+		CurrentStartDate: "2024-08-01", CurrentEndDate: "2024-08-31",
+		PreviousStartDate: "2024-07-01", PreviousEndDate: "2024-07-31",
+		Type: "expense", Top: 2,
+	})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, "2024-08-31", comparison.Current.EndDate)
+	assert.Equal(t, 2100.0, comparison.Current.Total)
+	assert.Equal(t, 1450.0, comparison.Previous.Total)
+	assert.Equal(t, 650.0, comparison.Delta)
+	require.NotNil(t, comparison.DeltaPercent)
+	assert.Equal(t, 44.83, *comparison.DeltaPercent)
+
+	require.Len(t, comparison.Categories, 4)
+	assert.Equal(t, "rent", comparison.Categories[0].Category)
+	assert.Equal(t, service.ComparisonUnchanged, comparison.Categories[0].Status)
+	food := comparison.Categories[2]
+	assert.Equal(t, -100.0, food.Delta)
+	assert.Equal(t, -25.0, *food.DeltaPercent)
+
+	require.Len(t, comparison.NewCategories, 1)
+	assert.Equal(t, "travel", comparison.NewCategories[0].Category)
+	assert.Nil(t, comparison.NewCategories[0].DeltaPercent)
+	require.Len(t, comparison.DisappearedCategories, 1)
+	assert.Equal(t, "gym", comparison.DisappearedCategories[0].Category)
+
+	require.Len(t, comparison.BiggestMovers, 2)
+	assert.Equal(t, "travel", comparison.BiggestMovers[0].Category)
+	assert.Equal(t, "food", comparison.BiggestMovers[1].Category)
+}
+
+func TestGetPeriodComparisonDefaultsPreviousCycleMonth(t *testing.T) {
+	db, mock := setupTestDB(t)
+	mock.MatchExpectationsInOrder(false)
+	dashboardService := service.NewDashboardService(db)
+
+	// siklus tanggal 25: agustus adalah 25 agu - 24 sep, sebelumnya 25 jul - 24 agu
+	mock.ExpectQuery("FROM `user_preferences`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "timezone", "cycle_start_day"}).AddRow(1, 1, "UTC", 25))
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery("AS totals").WillReturnRows(sqlmock.NewRows([]string{"category_id", "category_name", "total"}))
+	}
+
+	comparison, err := dashboardService.GetPeriodComparison(context.Background(), request.WorkspaceScope{WorkspaceID: 1, UserID: 1},
+		request.ComparisonFilter{CurrentMonth: "2024-08"})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-08-25", comparison.Current.StartDate)
+	assert.Equal(t, "2024-09-24", comparison.Current.EndDate)
+	assert.Equal(t, "2024-07", comparison.Previous.Month)
+	assert.Equal(t, "2024-07-25", comparison.Previous.StartDate)
+	assert.Equal(t, "2024-08-24", comparison.Previous.EndDate)
+	assert.Empty(t, comparison.Categories)
+	assert.Nil(t, comparison.DeltaPercent)
+}
+
+func TestGetPeriodComparisonRejectsPreviousWithoutCurrent(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardService := service.NewDashboardService(db)
+	mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := dashboardService.GetPeriodComparison(context.Background(), request.WorkspaceScope{WorkspaceID: 1, UserID: 1},
+		request.ComparisonFilter{PreviousMonth: "2024-07"})
+	assert.Equal(t, service.ErrInvalidComparison, err)
+}
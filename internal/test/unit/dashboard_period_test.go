@@ -0,0 +1,235 @@
+package unit
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(value string) time.Time {
+	parsed, _ := time.Parse("2006-01-02", value)
+	return parsed
+}
+
+func TestPeriodStart(t *testing.T) {
+	// 2024-08-14 adalah hari rabu
+	day := date("2024-08-14")
+	monday := utility.PeriodConfig{FirstDayOfWeek: int(time.Monday), CycleStartDay: 1}
+	sunday := utility.PeriodConfig{FirstDayOfWeek: int(time.Sunday), CycleStartDay: 1}
+
+	assert.Equal(t, date("2024-08-12"), utility.PeriodStart(day, utility.GranularityWeek, monday))
+	assert.Equal(t, date("2024-08-11"), utility.PeriodStart(day, utility.GranularityWeek, sunday))
+	assert.Equal(t, date("2024-08-01"), utility.PeriodStart(day, utility.GranularityMonth, monday))
+	assert.Equal(t, date("2024-07-01"), utility.PeriodStart(day, utility.GranularityQuarter, monday))
+	assert.Equal(t, date("2024-01-01"), utility.PeriodStart(day, utility.GranularityYear, monday))
+}
+
+func TestPeriodKey(t *testing.T) {
+	assert.Equal(t, "2024-08-14", utility.PeriodKey(date("2024-08-14"), utility.GranularityDay))
+	assert.Equal(t, "2024-W33", utility.PeriodKey(date("2024-08-12"), utility.GranularityWeek))
+	// minggu yang dimulai hari minggu memakai minggu ISO dari hari senin di dalamnya
+	assert.Equal(t, "2024-W33", utility.PeriodKey(date("2024-08-11"), utility.GranularityWeek))
+	// 2024-12-30 termasuk minggu ISO pertama 2025
+	assert.Equal(t, "2025-W01", utility.PeriodKey(date("2024-12-30"), utility.GranularityWeek))
+	assert.Equal(t, "2024-08", utility.PeriodKey(date("2024-08-01"), utility.GranularityMonth))
+	assert.Equal(t, "2024-Q3", utility.PeriodKey(date("2024-07-01"), utility.GranularityQuarter))
+	assert.Equal(t, "2024", utility.PeriodKey(date("2024-01-01"), utility.GranularityYear))
+}
+
+func TestBuildPeriodsClipsToRange(t *testing.T) {
+	periods := utility.BuildPeriods(date("2024-01-15"), date("2024-04-11"), utility.GranularityMonth, utility.PeriodConfig{CycleStartDay: 1, Locale: "en-US"})
+	require.Len(t, periods, 4)
+
+	assert.Equal(t, "2024-01", periods[0].Key)
+	assert.Equal(t, "Jan", periods[0].Label)
+	assert.Equal(t, date("2024-01-15"), periods[0].Start)
+	assert.Equal(t, date("2024-02-01"), periods[0].End)
+	// februari 2024 kabisat
+	assert.Equal(t, date("2024-03-01"), periods[1].End)
+	assert.Equal(t, "2024-04", periods[3].Key)
+	assert.Equal(t, date("2024-04-11"), periods[3].End)
+}
+
+func TestBuildPeriodsMultiYearLabels(t *testing.T) {
+	periods := utility.BuildPeriods(date("2023-12-01"), date("2024-02-01"), utility.GranularityMonth, utility.PeriodConfig{CycleStartDay: 1, Locale: "id-ID"})
+	require.Len(t, periods, 2)
+	assert.Equal(t, "Des 2023", periods[0].Label)
+	assert.Equal(t, "Jan 2024", periods[1].Label)
+}
+
+func TestPeriodStartCycleMonth(t *testing.T) {
+	payday := utility.PeriodConfig{CycleStartDay: 25}
+
+	// siklus tanggal 25: 25 agu - 24 sep diberi key bulan mulainya
+	assert.Equal(t, date("2024-07-25"), utility.PeriodStart(date("2024-08-24"), utility.GranularityMonth, payday))
+	assert.Equal(t, date("2024-08-25"), utility.PeriodStart(date("2024-08-25"), utility.GranularityMonth, payday))
+	assert.Equal(t, date("2024-08-25"), utility.PeriodStart(date("2024-09-10"), utility.GranularityMonth, payday))
+	assert.Equal(t, date("2024-12-25"), utility.PeriodStart(date("2025-01-03"), utility.GranularityMonth, payday))
+}
+
+func TestCycleStartShortMonths(t *testing.T) {
+	// tanggal 31 memakai tanggal terakhir bulan yang lebih pendek
+	assert.Equal(t, date("2024-02-29"), utility.CycleStart(2024, time.February, 31))
+	assert.Equal(t, date("2023-02-28"), utility.CycleStart(2023, time.February, 30))
+	assert.Equal(t, date("2024-04-30"), utility.CycleStart(2024, time.April, 31))
+
+	endOfMonth := utility.PeriodConfig{CycleStartDay: 31}
+	// 2024-03-15 masih di siklus februari karena siklus maret baru dimulai 31 maret
+	assert.Equal(t, date("2024-02-29"), utility.PeriodStart(date("2024-03-15"), utility.GranularityMonth, endOfMonth))
+	// menggeser dari siklus februari tidak boleh membawa tanggal 29 ke bulan berikutnya
+	assert.Equal(t, date("2024-03-31"), utility.AddPeriods(date("2024-02-29"), utility.GranularityMonth, 1, endOfMonth))
+	assert.Equal(t, date("2024-01-31"), utility.AddPeriods(date("2024-03-31"), utility.GranularityMonth, -2, endOfMonth))
+
+	start, end, err := utility.CycleMonth("2024-01", 31)
+	require.NoError(t, err)
+	assert.Equal(t, date("2024-01-31"), start)
+	assert.Equal(t, date("2024-02-28"), end)
+}
+
+func TestBuildPeriodsCycleMonth(t *testing.T) {
+	config := utility.PeriodConfig{CycleStartDay: 25, Locale: "en-US"}
+	periods := utility.BuildPeriods(date("2024-07-25"), date("2024-09-25"), utility.GranularityMonth, config)
+	require.Len(t, periods, 2)
+
+	assert.Equal(t, "2024-07", periods[0].Key)
+	assert.Equal(t, date("2024-08-25"), periods[0].End)
+	assert.Equal(t, "2024-08", periods[1].Key)
+	assert.Equal(t, "Aug", periods[1].Label)
+	assert.Equal(t, date("2024-09-25"), periods[1].End)
+}
+
+func TestGetFinancialOverviewInvalidRange(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardService := service.NewDashboardService(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM `user_preferences`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := dashboardService.GetFinancialOverview(context.Background(),
+		request.WorkspaceScope{WorkspaceID: 1, UserID: 1},
+		request.DashboardFilter{StartDate: "2024-05-01", EndDate: "2024-04-01", Granularity: "month"},
+	)
+	assert.Equal(t, service.ErrInvalidDashboardRange, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDashboardChartsTooManyPeriods(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardService := service.NewDashboardService(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM `user_preferences`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := dashboardService.GetDashboardCharts(context.Background(),
+		request.WorkspaceScope{WorkspaceID: 1, UserID: 1},
+		request.DashboardFilter{StartDate: "2020-01-01", EndDate: "2024-01-01", Granularity: "day"},
+	)
+	assert.Equal(t, service.ErrInvalidDashboardRange, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetIncomeExpenseSeriesBucketsByPeriod(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardUtil := &utility.DashboardUtil{DB: db}
+
+	periods := utility.BuildPeriods(date("2024-08-05"), date("2024-08-19"), utility.GranularityWeek, utility.PeriodConfig{FirstDayOfWeek: int(time.Monday), Locale: "en-US"})
+	require.Len(t, periods, 2)
+
+	// periode mingguan tidak sejajar bulan kalender, semua dibaca dari transaksi dalam satu query GROUP BY periode
+	mock.ExpectQuery("SELECT bucket, type, SUM\\(total\\) AS total FROM \\(SELECT CASE WHEN month < \\? THEN 0 ELSE 1 END AS bucket(.+)"+
+		"UNION ALL SELECT CASE WHEN date < \\? THEN 0 ELSE 1 END AS bucket, type, SUM\\(amount\\) AS total FROM `transactions` (.+)\\) AS series GROUP BY bucket, type").
+		WithArgs(date("2024-08-12"), 1, date("2024-08-19"), date("2024-08-19"),
+			date("2024-08-12"), 1, date("2024-08-05"), date("2024-08-19"), date("2024-08-19"), date("2024-08-19")).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "type", "total"}).
+			AddRow(0, "income", 1000.0).
+			AddRow(0, "expense", 200.0).
+			AddRow(1, "expense", 50.0).
+			AddRow(1, "income", 300.0))
+
+	income, expense, err := dashboardUtil.GetIncomeExpenseSeries(context.Background(), 1, periods)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1000, 300}, income)
+	assert.Equal(t, []float64{200, 50}, expense)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDashboardChartsPropagatesQueryError(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardService := service.NewDashboardService(db)
+	mock.MatchExpectationsInOrder(false)
+
+	mock.ExpectQuery("SELECT (.+) FROM `user_preferences`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("AS bucket").
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "type", "total"}))
+	// query kategori gagal, error harus sampai ke caller walaupun query series berhasil
+	mock.ExpectQuery("categories.name").
+		WillReturnError(errors.New("connection reset"))
+
+	_, err := dashboardService.GetDashboardCharts(context.Background(),
+		request.WorkspaceScope{WorkspaceID: 1, UserID: 1},
+		request.DashboardFilter{Granularity: "month"},
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection reset")
+}
+
+func TestGetFinancialOverviewSingleQuery(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardService := service.NewDashboardService(db)
+
+	mock.ExpectQuery("SELECT (.+) FROM `user_preferences`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	// agustus penuh dibaca dari monthly_aggregates, transaksi hanya dibaca untuk bulan yang terpotong
+	mock.ExpectQuery("SELECT COALESCE(.+) FROM \\(SELECT (.+) AS balance,(.+) AS income,(.+) AS expense FROM `monthly_aggregates` (.+) UNION ALL SELECT (.+) FROM `transactions` (.+)\\) AS totals").
+		WithArgs(date("2024-08-01"), date("2024-08-01"), 1, date("2024-09-01"),
+			date("2024-09-01"), date("2024-08-01"), date("2024-08-01"), 1, date("2024-09-01"), date("2024-08-01"), date("2024-08-01"), date("2024-09-01")).
+		WillReturnRows(sqlmock.NewRows([]string{"balance", "income", "expense"}).AddRow(5000.0, 3000.0, 1200.0))
+
+	overview, err := dashboardService.GetFinancialOverview(context.Background(),
+		request.WorkspaceScope{WorkspaceID: 1, UserID: 1},
+		request.DashboardFilter{StartDate: "2024-08-01", EndDate: "2024-08-31", Granularity: "month"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 5000.0, overview.CurrentBalance)
+	assert.Equal(t, 3000.0, overview.MonthlyIncome)
+	assert.Equal(t, 1200.0, overview.MonthlyExpense)
+	assert.Equal(t, 1800.0, overview.TotalSavings)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetIncomeExpenseSeriesReadsFullMonthsFromAggregates(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardUtil := &utility.DashboardUtil{DB: db}
+
+	// januari terpotong di awal, februari dan maret penuh, april terpotong di akhir
+	periods := utility.BuildPeriods(date("2024-01-15"), date("2024-04-11"), utility.GranularityMonth, utility.PeriodConfig{CycleStartDay: 1})
+	require.Len(t, periods, 4)
+
+	bucketArgs := []driver.Value{date("2024-02-01"), date("2024-03-01"), date("2024-04-01")}
+	args := append(append([]driver.Value{}, bucketArgs...), 1, date("2024-02-01"), date("2024-04-01"))
+	args = append(append(args, bucketArgs...), 1, date("2024-01-15"), date("2024-02-01"), date("2024-04-01"), date("2024-04-11"))
+
+	mock.ExpectQuery("FROM `monthly_aggregates` (.+) UNION ALL (.+) FROM `transactions`").
+		WithArgs(args...).
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "type", "total"}).
+			AddRow(0, "expense", 10.0).
+			AddRow(1, "income", 2000.0).
+			AddRow(2, "income", 2100.0).
+			AddRow(3, "expense", 40.0))
+
+	income, expense, err := dashboardUtil.GetIncomeExpenseSeries(context.Background(), 1, periods)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0, 2000, 2100, 0}, income)
+	assert.Equal(t, []float64{10, 0, 0, 40}, expense)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,135 @@
+package unit
+
+import (
+	"context"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryChartColor(t *testing.T) {
+	assert.Equal(t, "#3B82F6", utility.CategoryChartColor("bg-blue-100", 0))
+	assert.Equal(t, "#F43F5E", utility.CategoryChartColor("text-rose-700", 0))
+	assert.Equal(t, "#123abc", utility.CategoryChartColor("#123abc", 0))
+	assert.Equal(t, "rgb(1, 2, 3)", utility.CategoryChartColor("rgb(1, 2, 3)", 0))
+	// warna yang tidak dikenal memakai palette sesuai urutan
+	assert.Equal(t, utility.ChartPalette[1], utility.CategoryChartColor("", 1))
+	assert.Equal(t, utility.ChartPalette[0], utility.CategoryChartColor("bg-unknown-100", len(utility.ChartPalette)))
+}
+
+func TestGetCategoryTrendSelectedCategories(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardService := service.NewDashboardService(db)
+
+	mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("FROM `categories`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "color"}).
+			AddRow(2, "food", "bg-green-100").
+			AddRow(5, "travel", "#112233"))
+	mock.ExpectQuery("AS series").
+		WillReturnRows(sqlmock.NewRows([]string{"bucket", "category_id", "total"}).
+			AddRow(0, 5, 300.0).
+			AddRow(1, 2, 120.0).
+			AddRow(2, 2, 80.0))
+
+	trend, err := dashboardService.GetCategoryTrend(context.Background(), request.WorkspaceScope{WorkspaceID: 1, UserID: 1},
+		request.CategoryTrendFilter{StartDate: "2024-06-01", EndDate: "2024-08-31", CategoryIDs: []uint{5, 2, 5}, Type: "expense"})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, []string{"2024-06", "2024-07", "2024-08"}, trend.Keys)
+	assert.Equal(t, "month", trend.Range.Granularity)
+	// urutan dataset mengikuti category_ids tanpa duplikat
+	require.Len(t, trend.Datasets, 2)
+	assert.Equal(t, uint(5), trend.Datasets[0].CategoryID)
+	assert.Equal(t, "travel", trend.Datasets[0].Label)
+	assert.Equal(t, "#112233", trend.Datasets[0].BorderColor)
+	assert.Equal(t, []float64{300, 0, 0}, trend.Datasets[0].Data)
+	assert.Equal(t, "#22C55E", trend.Datasets[1].BorderColor)
+	assert.Equal(t, []float64{0, 120, 80}, trend.Datasets[1].Data)
+}
+
+func TestGetCategoryTrendRejectsForeignCategory(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardService := service.NewDashboardService(db)
+
+	mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("FROM `categories`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "color"}).AddRow(2, "food", "bg-green-100"))
+
+	_, err := dashboardService.GetCategoryTrend(context.Background(), request.WorkspaceScope{WorkspaceID: 1, UserID: 1},
+		request.CategoryTrendFilter{CategoryIDs: []uint{2, 9}})
+	assert.Equal(t, service.ErrInvalidTrendCategories, err)
+}
+
+func TestGetCategoryTrendDefaultsToTopCategories(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardService := service.NewDashboardService(db)
+
+	mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("AS totals").
+		WillReturnRows(sqlmock.NewRows([]string{"category_id", "category_name", "total"}).
+			AddRow(1, "food", 100.0).
+			AddRow(3, nil, 900.0).
+			AddRow(2, "rent", 500.0))
+	mock.ExpectQuery("FROM `categories`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "color"}).
+			AddRow(1, "food", "").
+			AddRow(2, "rent", ""))
+	mock.ExpectQuery("AS series").WillReturnRows(sqlmock.NewRows([]string{"bucket", "category_id", "total"}))
+
+	trend, err := dashboardService.GetCategoryTrend(context.Background(), request.WorkspaceScope{WorkspaceID: 1, UserID: 1},
+		request.CategoryTrendFilter{StartDate: "2024-06-01", EndDate: "2024-08-31"})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// kategori terhapus dilewati, sisanya urut dari total terbesar
+	require.Len(t, trend.Datasets, 2)
+	assert.Equal(t, "rent", trend.Datasets[0].Label)
+	assert.Equal(t, utility.ChartPalette[0], trend.Datasets[0].BorderColor)
+	assert.Equal(t, "food", trend.Datasets[1].Label)
+	assert.Equal(t, "expense", trend.Type)
+}
+
+func TestGetSpendingHeatmap(t *testing.T) {
+	db, mock := setupTestDB(t)
+	dashboardService := service.NewDashboardService(db)
+
+	mock.ExpectQuery("FROM `user_preferences`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "timezone", "locale", "first_day_of_week"}).
+			AddRow(1, 1, "UTC", "id-ID", 1))
+	mock.ExpectQuery("FROM `transactions`").
+		WithArgs(1, date("2023-01-01"), date("2024-01-01")).
+		WillReturnRows(sqlmock.NewRows([]string{"date", "total", "count"}).
+			AddRow(date("2023-01-07"), 100.0, 2).
+			AddRow(date("2023-01-09"), 52.0, 1))
+
+	heatmap, err := dashboardService.GetSpendingHeatmap(context.Background(), request.WorkspaceScope{WorkspaceID: 1, UserID: 1},
+		request.HeatmapFilter{Year: 2023})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	require.Len(t, heatmap.Days, 365)
+	assert.Equal(t, "2023-01-07", heatmap.Days[6].Date)
+	assert.Equal(t, int64(2), heatmap.Days[6].Count)
+	assert.Equal(t, 100.0, heatmap.MaxDaily)
+	assert.Equal(t, 152.0, heatmap.Total)
+
+	// minggu dimulai senin sesuai preferensi, 2023 punya 52 senin
+	require.Len(t, heatmap.DayOfWeek, 7)
+	assert.Equal(t, "Sen", heatmap.DayOfWeek[0].Label)
+	assert.Equal(t, 52.0, heatmap.DayOfWeek[0].Total)
+	assert.Equal(t, 1.0, heatmap.DayOfWeek[0].Average)
+	assert.Equal(t, 0, heatmap.DayOfWeek[6].Day)
+
+	assert.Equal(t, 260, heatmap.Weekday.Days)
+	assert.Equal(t, 52.0, heatmap.Weekday.Total)
+	assert.Equal(t, 105, heatmap.Weekend.Days)
+	assert.Equal(t, 100.0, heatmap.Weekend.Total)
+	assert.Equal(t, 0.2, heatmap.Weekday.DailyAverage)
+}
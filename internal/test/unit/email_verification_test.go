@@ -0,0 +1,249 @@
+package unit
+
+import (
+	"database/sql/driver"
+	"go-fintrack/config"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"go-fintrack/middleware"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenHashArg mencatat token_hash yang disimpan agar bisa dicocokkan dengan link di email
+type tokenHashArg struct {
+	value string
+}
+
+func (a *tokenHashArg) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	if ok && len(s) == 64 {
+		a.value = s
+	}
+	return ok
+}
+
+func newVerificationService(t *testing.T) (*service.UserService, sqlmock.Sqlmock, string) {
+	db, mock := setupTestDB(t)
+	outbox := t.TempDir()
+	return &service.UserService{
+		DB:          db,
+		EmailSender: &utility.FileSender{Dir: outbox, From: "no-reply@test.local"},
+		AppURL:      "http://localhost:3000",
+	}, mock, outbox
+}
+
+// verificationLinkToken mengambil token dari link verifikasi di email terakhir
+func verificationLinkToken(t *testing.T, outbox string) string {
+	files, _ := filepath.Glob(filepath.Join(outbox, "*.eml"))
+	require.Len(t, files, 1)
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "To: test@example.com")
+
+	match := regexp.MustCompile(`/verify-email\?token=(\S+)`).FindStringSubmatch(string(content))
+	require.Len(t, match, 2)
+	token, err := url.QueryUnescape(match[1])
+	require.NoError(t, err)
+	return token
+}
+
+func TestSendVerificationEmail(t *testing.T) {
+	userService, mock, outbox := newVerificationService(t)
+
+	hash := &tokenHashArg{}
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `email_verifications`").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), uint(1), "test@example.com", hash, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	user := &entity.User{Name: "Test User", Email: "test@example.com"}
+	user.ID = 1
+	err := userService.SendVerificationEmail(user)
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// hanya hash token yang disimpan, token asli dikirim lewat email
+	token := verificationLinkToken(t, outbox)
+	assert.Equal(t, utility.HashToken(token), hash.value)
+	assert.NotEqual(t, token, hash.value)
+}
+
+func TestResendVerificationEmail(t *testing.T) {
+	t.Run("Unverified user", func(t *testing.T) {
+		userService, mock, outbox := newVerificationService(t)
+
+		mock.ExpectQuery("SELECT (.+) FROM `users`").
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "email_verified"}).
+				AddRow(1, "Test User", "test@example.com", false))
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `email_verifications`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := userService.ResendVerificationEmail(1)
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+		assert.NotEmpty(t, verificationLinkToken(t, outbox))
+	})
+
+	t.Run("Already verified", func(t *testing.T) {
+		userService, mock, outbox := newVerificationService(t)
+
+		mock.ExpectQuery("SELECT (.+) FROM `users`").
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "email_verified"}).
+				AddRow(1, "Test User", "test@example.com", true))
+
+		err := userService.ResendVerificationEmail(1)
+		assert.Equal(t, service.ErrEmailAlreadyVerified, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+
+		files, _ := filepath.Glob(filepath.Join(outbox, "*.eml"))
+		assert.Empty(t, files)
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	token := "verification-token"
+	columns := []string{"id", "user_id", "email", "token_hash", "expires_at", "used_at"}
+
+	t.Run("Valid token", func(t *testing.T) {
+		userService, mock, _ := newVerificationService(t)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT (.+) FROM `email_verifications`").
+			WithArgs(utility.HashToken(token), 1).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, 1, "test@example.com", utility.HashToken(token), time.Now().Add(time.Hour), nil))
+		mock.ExpectExec("UPDATE `users` SET (.+) WHERE \\(id = (.+) AND email = (.+)\\)").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("UPDATE `email_verifications` SET `used_at`=(.+) WHERE \\(user_id = (.+) AND used_at IS NULL\\)").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		assert.NoError(t, userService.VerifyEmail(token))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Unknown token", func(t *testing.T) {
+		userService, mock, _ := newVerificationService(t)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT (.+) FROM `email_verifications`").
+			WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectRollback()
+
+		assert.Equal(t, service.ErrInvalidVerificationToken, userService.VerifyEmail(token))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		userService, mock, _ := newVerificationService(t)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT (.+) FROM `email_verifications`").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, 1, "test@example.com", utility.HashToken(token), time.Now().Add(-time.Hour), nil))
+		mock.ExpectRollback()
+
+		assert.Equal(t, service.ErrInvalidVerificationToken, userService.VerifyEmail(token))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Used token", func(t *testing.T) {
+		userService, mock, _ := newVerificationService(t)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT (.+) FROM `email_verifications`").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, 1, "test@example.com", utility.HashToken(token), time.Now().Add(time.Hour), time.Now().Add(-time.Minute)))
+		mock.ExpectRollback()
+
+		assert.Equal(t, service.ErrInvalidVerificationToken, userService.VerifyEmail(token))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Email changed after token issued", func(t *testing.T) {
+		userService, mock, _ := newVerificationService(t)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("SELECT (.+) FROM `email_verifications`").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(1, 1, "old@example.com", utility.HashToken(token), time.Now().Add(time.Hour), nil))
+		mock.ExpectExec("UPDATE `users`").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		assert.Equal(t, service.ErrInvalidVerificationToken, userService.VerifyEmail(token))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRequireVerifiedEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	previous := config.UnverifiedRestrictions
+	config.UnverifiedRestrictions = map[string]bool{middleware.FeatureExport: true}
+	t.Cleanup(func() { config.UnverifiedRestrictions = previous })
+
+	newRouter := func(userService *service.UserService) *gin.Engine {
+		router := gin.New()
+		setUser := func(ctx *gin.Context) { ctx.Set("userID", uint(1)) }
+		ok := func(ctx *gin.Context) { ctx.Status(http.StatusOK) }
+		router.GET("/export", setUser, middleware.RequireVerifiedEmail(userService, middleware.FeatureExport), ok)
+		router.GET("/chat", setUser, middleware.RequireVerifiedEmail(userService, middleware.FeatureChat), ok)
+		return router
+	}
+	serve := func(router *gin.Engine, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("Unverified user on restricted feature", func(t *testing.T) {
+		userService, mock, _ := newVerificationService(t)
+		mock.ExpectQuery("SELECT `id`,`email_verified` FROM `users`").
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "email_verified"}).AddRow(1, false))
+
+		w := serve(newRouter(userService), "/export")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), service.ErrEmailNotVerified.Error())
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Verified user on restricted feature", func(t *testing.T) {
+		userService, mock, _ := newVerificationService(t)
+		mock.ExpectQuery("SELECT `id`,`email_verified` FROM `users`").
+			WithArgs(1, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "email_verified"}).AddRow(1, true))
+
+		w := serve(newRouter(userService), "/export")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Feature not restricted by policy", func(t *testing.T) {
+		userService, mock, _ := newVerificationService(t)
+
+		// tanpa pembatasan tidak ada query ke database
+		w := serve(newRouter(userService), "/chat")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,87 @@
+package unit
+
+import (
+	"go-fintrack/internal/utility"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gaji tanggal 25 dan sewa tanggal 1 muncul di tiga bulan penuh sebelum agustus, belanja tidak rutin
+func forecastHistory() []utility.ForecastTransaction {
+	var history []utility.ForecastTransaction
+	for _, month := range []string{"2024-05", "2024-06", "2024-07"} {
+		history = append(history,
+			utility.ForecastTransaction{CategoryID: 1, Category: "salary", Type: "income", Amount: 5000, Date: date(month + "-25")},
+			utility.ForecastTransaction{CategoryID: 2, Category: "rent", Type: "expense", Amount: 1500, Date: date(month + "-01")},
+		)
+	}
+	return append(history,
+		utility.ForecastTransaction{CategoryID: 2, Category: "rent", Type: "expense", Amount: 1500, Date: date("2024-08-01")},
+		utility.ForecastTransaction{CategoryID: 3, Category: "food", Type: "expense", Amount: 900, Date: date("2024-07-10")},
+		utility.ForecastTransaction{CategoryID: 3, Category: "food", Type: "expense", Amount: 900, Date: date("2024-08-05")},
+	)
+}
+
+func TestDetectRecurring(t *testing.T) {
+	items := utility.DetectRecurring(forecastHistory(), date("2024-08-14"))
+	require.Len(t, items, 2)
+
+	assert.Equal(t, "rent", items[0].Category)
+	assert.Equal(t, 1, items[0].DayOfMonth)
+	assert.Equal(t, date("2024-08-01"), items[0].LastDate)
+	assert.Equal(t, "salary", items[1].Category)
+	assert.Equal(t, 25, items[1].DayOfMonth)
+}
+
+func TestVariableSpendExcludesRecurring(t *testing.T) {
+	today := date("2024-08-14")
+	history := forecastHistory()
+	spends := utility.VariableSpend(history, utility.DetectRecurring(history, today), today)
+
+	require.Len(t, spends, 1)
+	assert.Equal(t, "food", spends[0].Category)
+	assert.InDelta(t, 1800.0/utility.VariableSpendWindowDays, spends[0].DailyAverage, 0.0001)
+}
+
+func TestProjectCashFlowFirstNegativeBalance(t *testing.T) {
+	today := date("2024-08-14")
+	history := forecastHistory()
+	recurring := utility.DetectRecurring(history, today)
+	scheduled := []utility.ForecastTransaction{
+		{CategoryID: 4, Category: "insurance", Type: "expense", Amount: 800, Date: date("2024-08-20")},
+	}
+
+	days := utility.ProjectCashFlow(today, 30, 950, scheduled, recurring, 20)
+	require.Len(t, days, 30)
+	assert.Equal(t, date("2024-08-15"), days[0].Date)
+	assert.Equal(t, 930.0, days[0].Balance)
+
+	// 20-08: 950 - 6*20 - 800 = 30, 24-08 negatif sebelum gaji masuk tanggal 25
+	assert.Equal(t, 30.0, days[5].Balance)
+	assert.Equal(t, 10.0, days[6].Balance)
+	assert.Equal(t, -50.0, days[9].Balance)
+	assert.Equal(t, 5000.0, days[10].Income)
+
+	// sewa agustus sudah tercatat sehingga baru diproyeksikan lagi 1 september
+	var expenseBeforeSeptember float64
+	for _, day := range days[:17] {
+		expenseBeforeSeptember += day.Expense
+	}
+	assert.Equal(t, 17*20.0+800, expenseBeforeSeptember)
+	assert.Equal(t, date("2024-09-01"), days[17].Date)
+	assert.Equal(t, 1520.0, days[17].Expense)
+}
+
+func TestProjectCashFlowShortMonth(t *testing.T) {
+	recurring := []utility.RecurringItem{
+		{CategoryID: 1, Type: "income", Amount: 100, DayOfMonth: 31, LastDate: date("2024-01-31")},
+	}
+
+	// tanggal 31 jatuh di 29 februari 2024
+	days := utility.ProjectCashFlow(date("2024-02-27"), 3, 0, nil, recurring, 0)
+	assert.Equal(t, 0.0, days[0].Income)
+	assert.Equal(t, 100.0, days[1].Income)
+	assert.Equal(t, 0.0, days[2].Income)
+}
@@ -0,0 +1,95 @@
+package unit
+
+import (
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnlinkIdentityLastLoginMethod(t *testing.T) {
+	db, mock := setupTestDB(t)
+	identityService := service.NewIdentityService(db)
+
+	// akun tanpa password dengan satu identitas tidak boleh kehilangan cara login terakhirnya
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `user_identities`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "provider", "provider_user_id"}).
+			AddRow(5, 1, "google", "g-1"))
+	mock.ExpectQuery("SELECT (.+) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password"}).AddRow(1, ""))
+	mock.ExpectQuery("SELECT count(.+) FROM `user_identities`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectRollback()
+
+	assert.Equal(t, service.ErrLastLoginMethod, identityService.UnlinkIdentity(1, 5))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLinkIdentityOwnedByAnotherUser(t *testing.T) {
+	db, mock := setupTestDB(t)
+	identityService := service.NewIdentityService(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `user_identities`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "provider", "provider_user_id"}).
+			AddRow(5, 2, "github", "gh-1"))
+	mock.ExpectRollback()
+
+	_, err := identityService.LinkIdentity(1, &request.OAuthUser{Provider: "github", ProviderUserID: "gh-1", Email: "a@example.com"})
+	assert.Equal(t, service.ErrIdentityAlreadyLinked, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResolveOAuthLoginExistingEmailNotAutoLinked(t *testing.T) {
+	db, mock := setupTestDB(t)
+	identityService := service.NewIdentityService(db)
+
+	// akun password dengan email yang sama tidak boleh diambil alih lewat login OAuth
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `user_identities`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT (.+) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "provider"}).AddRow(1, "a@example.com", "local"))
+	mock.ExpectRollback()
+
+	_, err := identityService.ResolveOAuthLogin(&request.OAuthUser{
+		Provider:       "google",
+		ProviderUserID: "g-1",
+		Email:          "a@example.com",
+		EmailVerified:  true,
+	})
+	assert.Equal(t, service.ErrOAuthAccountExists, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResolveOAuthLoginGeneratesUniqueUsername(t *testing.T) {
+	db, mock := setupTestDB(t)
+	identityService := service.NewIdentityService(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `user_identities`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT (.+) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT `username` FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"username"}).AddRow("johndoe").AddRow("johndoe1"))
+	mock.ExpectExec("INSERT INTO `users`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT INTO `user_identities`").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	user, err := identityService.ResolveOAuthLogin(&request.OAuthUser{
+		Provider:       "github",
+		ProviderUserID: "gh-1",
+		Email:          "john@example.com",
+		Username:       "John-Doe",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "johndoe2", user.Username)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
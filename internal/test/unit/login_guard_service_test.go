@@ -0,0 +1,74 @@
+package unit
+
+import (
+	"errors"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginGuardLockout(t *testing.T) {
+	guard := service.NewLoginGuard(service.NewMemoryLoginAttemptStore(), nil)
+	client := request.ClientInfo{IPAddress: "10.0.0.1"}
+	userID := uint(1)
+
+	// 4 kegagalan pertama belum mengunci akun
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, guard.RegisterFailure("user:1", &userID, client))
+	}
+	assert.NoError(t, guard.Check("user:1"))
+
+	// kegagalan ke-5 memulai lockout
+	err := guard.RegisterFailure("user:1", &userID, client)
+	var lockedErr *service.LoginLockedError
+	assert.True(t, errors.As(err, &lockedErr))
+	first := lockedErr.RetryAfter
+
+	err = guard.Check("user:1")
+	assert.True(t, errors.As(err, &lockedErr))
+	assert.LessOrEqual(t, lockedErr.RetryAfter, first)
+
+	// lockout berikutnya berlipat dua
+	err = guard.RegisterFailure("user:1", &userID, client)
+	assert.True(t, errors.As(err, &lockedErr))
+	assert.Equal(t, first*2, lockedErr.RetryAfter)
+
+	// key lain tidak terpengaruh
+	assert.NoError(t, guard.Check("user:2"))
+
+	// admin unlock membuka kunci akun
+	assert.NoError(t, guard.Unlock(userID, 99))
+	assert.NoError(t, guard.Check("user:1"))
+}
+
+func TestLoginGuardIPLockout(t *testing.T) {
+	guard := service.NewLoginGuard(service.NewMemoryLoginAttemptStore(), nil)
+	client := request.ClientInfo{IPAddress: "10.0.0.2"}
+
+	// kegagalan tersebar di banyak akun tetap dihitung per IP
+	var err error
+	for i := 0; i < 20; i++ {
+		err = guard.RegisterFailure("login:user"+string(rune('a'+i)), nil, client)
+	}
+
+	var lockedErr *service.LoginLockedError
+	assert.True(t, errors.As(err, &lockedErr))
+	assert.Equal(t, time.Minute, lockedErr.RetryAfter)
+	assert.Error(t, guard.Check("ip:10.0.0.2"))
+}
+
+func TestNewLoginAttemptStoreDriver(t *testing.T) {
+	store, err := service.NewLoginAttemptStore("db", nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &service.DBLoginAttemptStore{}, store)
+
+	store, err = service.NewLoginAttemptStore("", nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &service.MemoryLoginAttemptStore{}, store)
+
+	_, err = service.NewLoginAttemptStore("database", nil)
+	assert.ErrorIs(t, err, service.ErrUnknownLoginAttemptStore)
+}
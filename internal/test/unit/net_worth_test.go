@@ -0,0 +1,108 @@
+package unit
+
+import (
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildNetWorthSnapshots(t *testing.T) {
+	db, mock := setupTestDB(t)
+	today := date("2024-08-03")
+
+	// bulan sebelum agustus 2024 dari monthly_aggregates, juli 2023 tanpa transaksi
+	mock.ExpectQuery("FROM `monthly_aggregates`").
+		WillReturnRows(sqlmock.NewRows([]string{"period", "income", "expense"}).
+			AddRow(date("2023-06-01"), 1000.0, 0.0).
+			AddRow(date("2023-08-01"), 0.0, 100.0).
+			AddRow(date("2024-07-01"), 0.0, 200.0))
+	// transaksi harian sejak awal bulan jendela snapshot harian
+	mock.ExpectQuery("FROM `transactions`").
+		WillReturnRows(sqlmock.NewRows([]string{"period", "income", "expense"}).
+			AddRow(date("2023-08-10"), 0.0, 100.0).
+			AddRow(date("2024-07-15"), 0.0, 200.0).
+			AddRow(date("2024-08-02"), 50.0, 0.0))
+
+	snapshots, err := utility.BuildNetWorthSnapshots(db, 1, today)
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	var monthly, daily []entity.NetWorthSnapshot
+	for _, snapshot := range snapshots {
+		if snapshot.Granularity == entity.NetWorthMonthly {
+			monthly = append(monthly, snapshot)
+		} else {
+			daily = append(daily, snapshot)
+		}
+	}
+
+	require.Len(t, monthly, 15)
+	assert.Equal(t, date("2023-06-30"), monthly[0].Date)
+	assert.Equal(t, 1000.0, monthly[1].NetWorth)
+	assert.Equal(t, 900.0, monthly[2].NetWorth)
+	assert.Equal(t, 700.0, monthly[13].NetWorth)
+	// bulan berjalan berakhir hari ini
+	assert.Equal(t, today, monthly[14].Date)
+	assert.Equal(t, 750.0, monthly[14].NetWorth)
+
+	require.Len(t, daily, utility.NetWorthDailyDays)
+	assert.Equal(t, date("2023-08-04"), daily[0].Date)
+	assert.Equal(t, 1000.0, daily[0].NetWorth)
+	assert.Equal(t, 900.0, daily[6].NetWorth)
+	assert.Equal(t, today, daily[len(daily)-1].Date)
+	assert.Equal(t, 750.0, daily[len(daily)-1].NetWorth)
+}
+
+func TestBuildNetWorthSnapshotsWithoutTransactions(t *testing.T) {
+	db, mock := setupTestDB(t)
+
+	mock.ExpectQuery("FROM `monthly_aggregates`").WillReturnRows(sqlmock.NewRows([]string{"period", "income", "expense"}))
+	mock.ExpectQuery("FROM `transactions`").WillReturnRows(sqlmock.NewRows([]string{"period", "income", "expense"}))
+
+	snapshots, err := utility.BuildNetWorthSnapshots(db, 1, date("2024-08-03"))
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+}
+
+func TestGetNetWorthChartCarriesPreviousSnapshot(t *testing.T) {
+	db, mock := setupTestDB(t)
+	netWorthService := service.NewNetWorthService(db, nil)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: 1}
+
+	mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("SELECT count").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(20))
+	// snapshot terakhir sebelum rentang ikut dibaca sebagai nilai awal
+	mock.ExpectQuery(regexp.QuoteMeta("(date >= ? OR date = (SELECT MAX(date) FROM `net_worth_snapshots`")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id", "granularity", "date", "net_worth"}).
+			AddRow(1, 1, "month", date("2024-02-29"), 500.0).
+			AddRow(2, 1, "month", date("2024-04-30"), 650.0))
+
+	chart, err := netWorthService.GetNetWorthChart(scope, request.NetWorthFilter{
+		StartDate: "2024-03-01", EndDate: "2024-05-31", Granularity: "month",
+	})
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, []string{"2024-03", "2024-04", "2024-05"}, chart.Keys)
+	require.Len(t, chart.Datasets, 1)
+	assert.Equal(t, []float64{500, 650, 650}, chart.Datasets[0].Data)
+}
+
+func TestGetNetWorthChartDailyRetention(t *testing.T) {
+	db, mock := setupTestDB(t)
+	netWorthService := service.NewNetWorthService(db, nil)
+
+	mock.ExpectQuery("FROM `user_preferences`").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := netWorthService.GetNetWorthChart(request.WorkspaceScope{WorkspaceID: 1, UserID: 1}, request.NetWorthFilter{
+		StartDate: "2020-01-01", EndDate: "2020-01-31", Granularity: "day",
+	})
+	assert.Equal(t, service.ErrInvalidNetWorthRange, err)
+}
@@ -0,0 +1,243 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"go-fintrack/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// mockIdP adalah identity provider OIDC lokal: discovery, authorize, token (dengan validasi PKCE) dan userinfo
+type mockIdP struct {
+	*httptest.Server
+	mu         sync.Mutex
+	challenges map[string]string // code -> code_challenge
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	idp := &mockIdP{challenges: make(map[string]string)}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"userinfo_endpoint":      idp.URL + "/userinfo",
+		})
+	})
+
+	// user dianggap langsung menyetujui login, code dikembalikan ke redirect_uri
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+			http.Error(w, "pkce required", http.StatusBadRequest)
+			return
+		}
+
+		idp.mu.Lock()
+		idp.challenges["mock-code"] = query.Get("code_challenge")
+		idp.mu.Unlock()
+
+		redirect, _ := url.Parse(query.Get("redirect_uri"))
+		values := redirect.Query()
+		values.Set("code", "mock-code")
+		values.Set("state", query.Get("state"))
+		redirect.RawQuery = values.Encode()
+		http.Redirect(w, r, redirect.String(), http.StatusFound)
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		idp.mu.Lock()
+		challenge, ok := idp.challenges[r.Form.Get("code")]
+		delete(idp.challenges, r.Form.Get("code"))
+		idp.mu.Unlock()
+
+		if !ok || oauth2.S256ChallengeFromVerifier(r.Form.Get("code_verifier")) != challenge {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "mock-access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	})
+
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer mock-access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sub":            "mock-user-1",
+			"email":          "jane@example.com",
+			"email_verified": "true",
+			"name":           "Jane Doe",
+		})
+	})
+
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+// authorize mengikuti URL login seperti browser dan mengembalikan code serta state dari redirect
+func (idp *mockIdP) authorize(t *testing.T, authURL string) (string, string) {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func newMockOAuthService(t *testing.T, idp *mockIdP) *service.OAuthService {
+	provider, err := service.NewOAuthProvider(context.Background(), service.OAuthProviderConfig{
+		Name:         "mock",
+		Type:         service.OAuthTypeOIDC,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:3000/auth/mock/callback",
+		IssuerURL:    idp.URL,
+	})
+	require.NoError(t, err)
+
+	oauthService := service.NewOAuthService(service.NewMemoryOAuthStateStore())
+	oauthService.Register(provider)
+	return oauthService
+}
+
+func TestOAuthLoginWithPKCE(t *testing.T) {
+	idp := newMockIdP(t)
+	oauthService := newMockOAuthService(t, idp)
+
+	login, err := oauthService.BeginAuth("mock")
+	require.NoError(t, err)
+
+	code, state := idp.authorize(t, login.RedirectURL)
+	assert.Equal(t, login.State, state)
+	require.NotEmpty(t, login.Nonce)
+
+	result, err := oauthService.CompleteAuth(context.Background(), "mock", state, login.Nonce, code)
+	require.NoError(t, err)
+	assert.Nil(t, result.LinkUserID)
+
+	user := result.User
+	assert.Equal(t, "mock", user.Provider)
+	assert.Equal(t, "mock-user-1", user.ProviderUserID)
+	assert.Equal(t, "jane@example.com", user.Email)
+	assert.True(t, user.EmailVerified)
+
+	// state hanya bisa dipakai sekali
+	_, err = oauthService.CompleteAuth(context.Background(), "mock", state, login.Nonce, code)
+	assert.ErrorIs(t, err, service.ErrInvalidOAuthState)
+}
+
+func TestOAuthCallbackRejectsUnknownState(t *testing.T) {
+	idp := newMockIdP(t)
+	oauthService := newMockOAuthService(t, idp)
+
+	_, err := oauthService.CompleteAuth(context.Background(), "mock", "forged-state", "nonce", "mock-code")
+	assert.ErrorIs(t, err, service.ErrInvalidOAuthState)
+
+	_, err = oauthService.CompleteAuth(context.Background(), "unknown", "forged-state", "nonce", "mock-code")
+	assert.ErrorIs(t, err, service.ErrOAuthProviderNotFound)
+}
+
+func TestOAuthStateBoundToProvider(t *testing.T) {
+	idp := newMockIdP(t)
+	oauthService := newMockOAuthService(t, idp)
+
+	other, err := service.NewOAuthProvider(context.Background(), service.OAuthProviderConfig{
+		Name:         "other",
+		Type:         service.OAuthTypeOIDC,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		IssuerURL:    idp.URL,
+	})
+	require.NoError(t, err)
+	oauthService.Register(other)
+
+	login, err := oauthService.BeginAuth("mock")
+	require.NoError(t, err)
+	code, state := idp.authorize(t, login.RedirectURL)
+
+	_, err = oauthService.CompleteAuth(context.Background(), "other", state, login.Nonce, code)
+	assert.ErrorIs(t, err, service.ErrInvalidOAuthState)
+}
+
+func TestOAuthStateBoundToBrowserNonce(t *testing.T) {
+	idp := newMockIdP(t)
+	oauthService := newMockOAuthService(t, idp)
+
+	// penyerang memulai login dan mengirim URL authorize ke korban, browser korban tidak punya cookie nonce
+	login, err := oauthService.BeginAuth("mock")
+	require.NoError(t, err)
+	code, state := idp.authorize(t, login.RedirectURL)
+
+	_, err = oauthService.CompleteAuth(context.Background(), "mock", state, "", code)
+	assert.ErrorIs(t, err, service.ErrInvalidOAuthState)
+	_, err = oauthService.CompleteAuth(context.Background(), "mock", state, "other-browser-nonce", code)
+	assert.ErrorIs(t, err, service.ErrInvalidOAuthState)
+
+	// percobaan dengan nonce salah tidak menghabiskan state milik browser yang memulai login
+	result, err := oauthService.CompleteAuth(context.Background(), "mock", state, login.Nonce, code)
+	require.NoError(t, err)
+	assert.Equal(t, "mock-user-1", result.User.ProviderUserID)
+}
+
+func TestOIDCDiscoveryIssuerMismatch(t *testing.T) {
+	idp := newMockIdP(t)
+
+	_, err := service.NewOAuthProvider(context.Background(), service.OAuthProviderConfig{
+		Name:         "mock",
+		Type:         service.OAuthTypeOIDC,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		IssuerURL:    idp.URL + "/realms/other",
+	})
+	assert.Error(t, err)
+}
+
+func TestOAuthLinkBoundToSession(t *testing.T) {
+	idp := newMockIdP(t)
+	oauthService := newMockOAuthService(t, idp)
+
+	// link hanya bisa dimulai dari session login, bukan API token
+	_, err := oauthService.BeginLink("mock", 7, "")
+	assert.ErrorIs(t, err, service.ErrOAuthLinkSession)
+
+	login, err := oauthService.BeginLink("mock", 7, "session-a")
+	require.NoError(t, err)
+	code, state := idp.authorize(t, login.RedirectURL)
+
+	result, err := oauthService.CompleteAuth(context.Background(), "mock", state, login.Nonce, code)
+	require.NoError(t, err)
+	require.NotNil(t, result.LinkUserID)
+	assert.Equal(t, uint(7), *result.LinkUserID)
+
+	assert.True(t, result.LinkAllowed(7, "session-a"))
+	assert.False(t, result.LinkAllowed(7, "session-b"))
+	assert.False(t, result.LinkAllowed(8, "session-a"))
+	assert.False(t, result.LinkAllowed(0, ""))
+}
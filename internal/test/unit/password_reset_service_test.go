@@ -0,0 +1,130 @@
+package unit
+
+import (
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForgotPassword(t *testing.T) {
+	db, mock := setupTestDB(t)
+	outbox := t.TempDir()
+
+	userService := &service.UserService{
+		DB:          db,
+		EmailSender: &utility.FileSender{Dir: outbox, From: "no-reply@test.local"},
+		AppURL:      "http://localhost:3000",
+	}
+
+	t.Run("Registered email", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM `users`").
+			WithArgs("test@example.com", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email"}).
+				AddRow(1, "Test User", "test@example.com"))
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `password_resets`").
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := userService.ForgotPassword("test@example.com")
+		assert.NoError(t, err)
+
+		files, _ := filepath.Glob(filepath.Join(outbox, "*.eml"))
+		assert.Len(t, files, 1)
+		if len(files) == 1 {
+			content, _ := os.ReadFile(files[0])
+			assert.Contains(t, string(content), "To: test@example.com")
+			assert.Contains(t, string(content), "http://localhost:3000/reset-password?token=")
+		}
+	})
+
+	t.Run("Unknown email", func(t *testing.T) {
+		mock.ExpectQuery("SELECT (.+) FROM `users`").
+			WithArgs("unknown@example.com", 1).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		err := userService.ForgotPassword("unknown@example.com")
+		assert.NoError(t, err)
+
+		files, _ := filepath.Glob(filepath.Join(outbox, "*.eml"))
+		assert.Len(t, files, 1)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	db, mock := setupTestDB(t)
+	userService := &service.UserService{DB: db}
+
+	columns := []string{"id", "user_id", "token_hash", "expires_at", "used_at"}
+
+	tests := []struct {
+		name          string
+		password      string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name:          "Weak password",
+			password:      "password",
+			setupMock:     func(mock sqlmock.Sqlmock) {},
+			expectedError: service.ErrWeakPassword,
+		},
+		{
+			// token tidak dikenal, kedaluwarsa, sudah dipakai atau diklaim request lain: UPDATE tidak mengenai baris
+			name:     "Token cannot be claimed",
+			password: "Password123",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `password_resets` SET `used_at`=(.+) WHERE \\(token_hash = \\? AND used_at IS NULL AND expires_at > \\?\\)").
+					WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), utility.HashToken("reset-token"), sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedError: service.ErrInvalidResetToken,
+		},
+		{
+			name:     "Claimed token updates password",
+			password: "Password123",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec("UPDATE `password_resets` SET `used_at`").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectQuery("SELECT (.+) FROM `password_resets`").
+					WithArgs(utility.HashToken("reset-token"), 1).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, 7, utility.HashToken("reset-token"), time.Now().Add(time.Hour), time.Now()))
+				mock.ExpectExec("UPDATE `users` SET").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("UPDATE `password_resets` SET `used_at`=(.+) WHERE \\(user_id = \\? AND used_at IS NULL\\)").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("UPDATE `sessions` SET `revoked_at`").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectCommit()
+			},
+			expectedError: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock(mock)
+
+			err := userService.ResetPassword("reset-token", tt.password)
+			assert.ErrorIs(t, err, tt.expectedError)
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
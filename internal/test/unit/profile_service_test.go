@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"go-fintrack/internal/payload/request"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsernameRuleSharedByRegisterAndProfile(t *testing.T) {
+	for _, tt := range []struct {
+		username string
+		valid    bool
+	}{
+		{"abc", false},
+		{"abcd", true},
+		{"user2024", true},
+		{"abcdefghijklmnopqrst", true},
+		{"abcdefghijklmnopqrstu", false},
+		{"john_doe", false},
+		{"jöhn", false},
+	} {
+		t.Run(tt.username, func(t *testing.T) {
+			register := request.RegisterRequest{
+				Name: "Test User", Username: tt.username, Email: "test@example.com", Password: "Password1", ConfirmPassword: "Password1",
+			}
+			username := tt.username
+			profile := request.UpdateProfileRequest{Username: &username}
+
+			// registrasi dan update profile menerima username yang sama
+			assert.Equal(t, tt.valid, binding.Validator.ValidateStruct(register) == nil)
+			assert.Equal(t, tt.valid, binding.Validator.ValidateStruct(profile) == nil)
+			assert.Equal(t, tt.valid, utility.IsValidUsername(tt.username))
+		})
+	}
+}
+
+func TestChangePasswordWrongCurrentPassword(t *testing.T) {
+	db, mock := setupTestDB(t)
+	userService := &service.UserService{DB: db}
+
+	hashed, err := utility.HashPassword("OldPassword1")
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password"}).AddRow(1, hashed))
+	mock.ExpectRollback()
+
+	err = userService.ChangePassword(1, "", request.ChangePasswordRequest{
+		CurrentPassword: "WrongPassword1",
+		NewPassword:     "NewPassword1",
+		ConfirmPassword: "NewPassword1",
+	})
+	assert.Equal(t, service.ErrInvalidCurrentPassword, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestChangePasswordWeakPassword(t *testing.T) {
+	db, mock := setupTestDB(t)
+	userService := &service.UserService{DB: db}
+
+	err := userService.ChangePassword(1, "", request.ChangePasswordRequest{
+		CurrentPassword: "OldPassword1",
+		NewPassword:     "weakpassword",
+		ConfirmPassword: "weakpassword",
+	})
+	assert.Equal(t, service.ErrWeakPassword, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateProfileUsernameTaken(t *testing.T) {
+	db, mock := setupTestDB(t)
+	userService := &service.UserService{DB: db}
+	username := "takenname"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT count(.+) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	_, err := userService.UpdateProfile(1, request.UpdateProfileRequest{Username: &username})
+	assert.Equal(t, service.ErrUsernameTaken, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPreferencesDefault(t *testing.T) {
+	db, mock := setupTestDB(t)
+	userService := &service.UserService{DB: db}
+
+	// user yang belum pernah menyimpan preferensi mendapat nilai default
+	mock.ExpectQuery("SELECT (.+) FROM `user_preferences`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	preferences, err := userService.GetPreferences(1)
+	require.NoError(t, err)
+	assert.Equal(t, "IDR", preferences.BaseCurrency)
+	assert.Equal(t, "Asia/Jakarta", preferences.Timezone)
+	assert.Equal(t, int(time.Monday), preferences.FirstDayOfWeek)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "1,234,567.89", utility.FormatAmount(1234567.89, "comma_dot"))
+	assert.Equal(t, "1.234.567,89", utility.FormatAmount(1234567.89, "dot_comma"))
+	assert.Equal(t, "1 234,50", utility.FormatAmount(1234.5, "space_comma"))
+	assert.Equal(t, "-999.05", utility.FormatAmount(-999.05, "comma_dot"))
+	assert.Equal(t, "0.00", utility.FormatAmount(0, "comma_dot"))
+}
+
+func TestMonthLabel(t *testing.T) {
+	date := time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, "Agu", utility.MonthLabel(date, "id-ID"))
+	assert.Equal(t, "Aug", utility.MonthLabel(date, "en-US"))
+}
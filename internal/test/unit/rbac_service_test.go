@@ -0,0 +1,59 @@
+package unit
+
+import (
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/service"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRolePermissions(t *testing.T) {
+	assert.False(t, service.IsStaffRole(entity.RoleUser))
+	assert.True(t, service.IsStaffRole(entity.RoleSupportReadonly))
+
+	assert.True(t, service.RoleHasPermission(entity.RoleSupportReadonly, service.PermissionUsersRead))
+	assert.False(t, service.RoleHasPermission(entity.RoleSupportReadonly, service.PermissionUsersManage))
+	assert.True(t, service.RoleHasPermission(entity.RoleAdmin, service.PermissionUsersManage))
+	assert.False(t, service.RoleHasPermission(entity.RoleAdmin, service.PermissionRolesAssign))
+	assert.True(t, service.RoleHasPermission(entity.RoleSuperadmin, service.PermissionRolesAssign))
+	assert.False(t, service.RoleHasPermission("unknown", service.PermissionUsersRead))
+}
+
+func TestHasPermissionUsesCache(t *testing.T) {
+	db, mock := setupTestDB(t)
+	rbacService := service.NewRBACService(db)
+
+	// role hanya diambil sekali dari database selama cache masih berlaku
+	mock.ExpectQuery("SELECT `id`,`role` FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role"}).AddRow(1, entity.RoleAdmin))
+
+	allowed, err := rbacService.HasPermission(1, service.PermissionUsersManage)
+	assert.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = rbacService.HasPermission(1, service.PermissionRolesAssign)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAssignRoleLastSuperadmin(t *testing.T) {
+	db, mock := setupTestDB(t)
+	rbacService := service.NewRBACService(db)
+
+	assert.Equal(t, service.ErrInvalidRole, rbacService.AssignRole(1, 2, "owner"))
+	assert.Equal(t, service.ErrCannotModifySelf, rbacService.AssignRole(1, 1, entity.RoleUser))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `users`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "role"}).AddRow(2, entity.RoleSuperadmin))
+	mock.ExpectQuery("SELECT `id` FROM `users` WHERE role = (.+) FOR UPDATE").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectRollback()
+
+	assert.Equal(t, service.ErrLastSuperadmin, rbacService.AssignRole(1, 2, entity.RoleAdmin))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,101 @@
+package unit
+
+import (
+	"go-fintrack/internal/service"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSession(t *testing.T) {
+	db, mock := setupTestDB(t)
+	sessionService := service.NewSessionService(db)
+
+	columns := []string{"id", "user_id", "token_id", "last_used_at", "expires_at", "revoked_at"}
+	now := time.Now()
+
+	tests := []struct {
+		name          string
+		setupMock     func(sqlmock.Sqlmock)
+		expectedError error
+	}{
+		{
+			name: "Active session",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM `sessions`").
+					WithArgs("sid-1", 1, 1).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, 1, "sid-1", now, now.Add(time.Hour), nil))
+			},
+		},
+		{
+			name: "Revoked session",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM `sessions`").
+					WithArgs("sid-1", 1, 1).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, 1, "sid-1", now, now.Add(time.Hour), now))
+			},
+			expectedError: service.ErrSessionInvalid,
+		},
+		{
+			name: "Expired session",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM `sessions`").
+					WithArgs("sid-1", 1, 1).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(1, 1, "sid-1", now, now.Add(-time.Hour), nil))
+			},
+			expectedError: service.ErrSessionInvalid,
+		},
+		{
+			name: "Unknown session",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery("SELECT (.+) FROM `sessions`").
+					WithArgs("sid-1", 1, 1).
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+			expectedError: service.ErrSessionInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock(mock)
+
+			session, err := sessionService.ValidateSession("sid-1", 1)
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				assert.Nil(t, session)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, session)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	db, mock := setupTestDB(t)
+	sessionService := service.NewSessionService(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `sessions` SET `revoked_at`").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), 2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := sessionService.RevokeSession(1, 2)
+	assert.ErrorIs(t, err, service.ErrSessionNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
@@ -2,6 +2,7 @@ package unit
 
 import (
 	"database/sql"
+	"go-fintrack/internal/payload/entity"
 	"go-fintrack/internal/payload/request"
 	"go-fintrack/internal/service"
 	"io"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -60,6 +62,7 @@ func (suite *TransactionServiceTestSuite) TearDownTest() {
 
 func (suite *TransactionServiceTestSuite) TestGetTransactionByUser() {
 	userID := uint(1)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: userID}
 	now := time.Now()
 	filter := request.TransactionFilter{
 		Page:  1,
@@ -68,21 +71,14 @@ func (suite *TransactionServiceTestSuite) TestGetTransactionByUser() {
 
 	// Mock count query
 	countRows := sqlmock.NewRows([]string{"count"}).AddRow(2)
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `transactions` WHERE user_id = ? AND `transactions`.`deleted_at` IS NULL")).
-		WithArgs(userID).
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `transactions` WHERE workspace_id = ? AND `transactions`.`deleted_at` IS NULL")).
+		WithArgs(scope.WorkspaceID).
 		WillReturnRows(countRows)
 
-	// Mock income query
-	incomeRows := sqlmock.NewRows([]string{"sum"}).AddRow(1000.0)
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(SUM(amount), 0) FROM `transactions` WHERE user_id = ? AND `transactions`.`deleted_at` IS NULL AND type = ?")).
-		WithArgs(userID, "income").
-		WillReturnRows(incomeRows)
-
-	// Mock expense query
-	expenseRows := sqlmock.NewRows([]string{"sum"}).AddRow(500.0)
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(SUM(amount), 0) FROM `transactions` WHERE user_id = ? AND `transactions`.`deleted_at` IS NULL AND type = ?")).
-		WithArgs(userID, "expense").
-		WillReturnRows(expenseRows)
+	// Mock summary query, bulan penuh dari monthly_aggregates dan sisanya dari transactions
+	summaryRows := sqlmock.NewRows([]string{"income", "expense"}).AddRow(1000.0, 500.0)
+	suite.mock.ExpectQuery("SELECT COALESCE\\(SUM\\(income\\), 0\\), COALESCE\\(SUM\\(expense\\), 0\\) FROM \\(SELECT (.+) FROM `monthly_aggregates` (.+) UNION ALL SELECT (.+) FROM `transactions` (.+)\\) AS summary").
+		WillReturnRows(summaryRows)
 
 	// Mock transaction list query
 	transactionRows := sqlmock.NewRows([]string{
@@ -93,8 +89,8 @@ func (suite *TransactionServiceTestSuite) TestGetTransactionByUser() {
 		AddRow(1, now, now, nil, userID, 1, 1000.0, "income", "Salary", now).
 		AddRow(2, now, now, nil, userID, 2, 500.0, "expense", "Shopping", now)
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE user_id = ? AND `transactions`.`deleted_at` IS NULL ORDER BY date DESC LIMIT ?")).
-		WithArgs(userID, 10).
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE workspace_id = ? AND `transactions`.`deleted_at` IS NULL ORDER BY date DESC LIMIT ?")).
+		WithArgs(scope.WorkspaceID, 10).
 		WillReturnRows(transactionRows)
 
 	// Mock category preload (using IN clause)
@@ -109,11 +105,20 @@ func (suite *TransactionServiceTestSuite) TestGetTransactionByUser() {
 		WithArgs(1, 2).
 		WillReturnRows(categoryRows)
 
-	result, err := suite.service.GetTransactionByUser(userID, filter)
+	// anomali hasil analisis ikut ditempelkan pada transaksinya
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `spending_anomalies` WHERE transaction_id IN (?,?)")).
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id", "kind", "transaction_id", "category_id", "baseline", "ratio"}).
+			AddRow(7, 1, "large_transaction", 2, 2, 100.0, 5.0))
+
+	result, err := suite.service.GetTransactionByUser(scope, filter)
 
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), result)
 	assert.Len(suite.T(), result.Transactions, 2)
+	assert.Empty(suite.T(), result.Transactions[0].Anomalies)
+	require.Len(suite.T(), result.Transactions[1].Anomalies, 1)
+	assert.Equal(suite.T(), "This Category 2 expense is 5.0x larger than usual", result.Transactions[1].Anomalies[0].Message)
 	assert.Equal(suite.T(), float64(1000), result.Summary.TotalIncome)
 	assert.Equal(suite.T(), float64(500), result.Summary.TotalExpense)
 	assert.Equal(suite.T(), float64(500), result.Summary.Balance)
@@ -121,6 +126,7 @@ func (suite *TransactionServiceTestSuite) TestGetTransactionByUser() {
 
 func (suite *TransactionServiceTestSuite) TestCreateTransaction() {
 	userID := uint(1)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: userID}
 	now := time.Now()
 	date := "2025-01-29"
 	req := request.CreateTransactionRequest{
@@ -137,18 +143,22 @@ func (suite *TransactionServiceTestSuite) TestCreateTransaction() {
 		"user_id", "name",
 	}).AddRow(1, now, now, nil, userID, "Salary")
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE `categories`.`id` = ? AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
-		WithArgs(req.CategoryID, 1).
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE (id = ? AND workspace_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
+		WithArgs(req.CategoryID, scope.WorkspaceID, 1).
 		WillReturnRows(categoryRows)
 
 	// Mock create transaction
 	suite.mock.ExpectBegin()
-	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `transactions` (`created_at`,`updated_at`,`deleted_at`,`user_id`,`category_id`,`amount`,`type`,`description`,`date`) VALUES (?,?,?,?,?,?,?,?,?)")).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, req.CategoryID, req.Amount, req.Type, req.Description, sqlmock.AnyArg()).
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `transactions` (`created_at`,`updated_at`,`deleted_at`,`workspace_id`,`user_id`,`category_id`,`amount`,`type`,`description`,`date`) VALUES (?,?,?,?,?,?,?,?,?,?)")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, scope.WorkspaceID, userID, req.CategoryID, req.Amount, req.Type, req.Description, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `monthly_aggregates`")).
+		WithArgs(scope.WorkspaceID, userID, req.CategoryID, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), req.Type, req.Amount, int64(1), sqlmock.AnyArg(),
+			req.Amount, int64(1), sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	suite.mock.ExpectCommit()
 
-	result, err := suite.service.CreateTransaction(userID, req)
+	result, err := suite.service.CreateTransaction(scope, req)
 
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), result)
@@ -159,6 +169,7 @@ func (suite *TransactionServiceTestSuite) TestCreateTransaction() {
 
 func (suite *TransactionServiceTestSuite) TestUpdateTransaction() {
 	userID := uint(1)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: userID}
 	transactionID := uint(1)
 	now := time.Now()
 	req := request.UpdateTransactionRequest{
@@ -172,12 +183,12 @@ func (suite *TransactionServiceTestSuite) TestUpdateTransaction() {
 	// Mock get existing transaction
 	txRows := sqlmock.NewRows([]string{
 		"id", "created_at", "updated_at", "deleted_at",
-		"user_id", "category_id", "amount", "type",
+		"workspace_id", "user_id", "category_id", "amount", "type",
 		"description", "date",
-	}).AddRow(transactionID, now, now, nil, userID, 1, 1000.0, "income", "Salary", now)
+	}).AddRow(transactionID, now, now, nil, scope.WorkspaceID, userID, 1, 1000.0, "income", "Salary", now)
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE (id = ? AND user_id = ?) AND `transactions`.`deleted_at` IS NULL ORDER BY `transactions`.`id` LIMIT ?")).
-		WithArgs(transactionID, userID, 1).
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE (id = ? AND workspace_id = ?) AND `transactions`.`deleted_at` IS NULL ORDER BY `transactions`.`id` LIMIT ?")).
+		WithArgs(transactionID, scope.WorkspaceID, 1).
 		WillReturnRows(txRows)
 
 	// Mock category check
@@ -186,18 +197,31 @@ func (suite *TransactionServiceTestSuite) TestUpdateTransaction() {
 		"user_id", "name",
 	}).AddRow(1, now, now, nil, userID, "Salary")
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE `categories`.`id` = ? AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
-		WithArgs(req.CategoryID, 1).
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE (id = ? AND workspace_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
+		WithArgs(req.CategoryID, scope.WorkspaceID, 1).
 		WillReturnRows(categoryRows)
 
-	// Mock update
+	// Mock update, nilai lama dibaca ulang dengan lock untuk mengurangi agregat
 	suite.mock.ExpectBegin()
-	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE `transactions` SET `created_at`=?,`updated_at`=?,`deleted_at`=?,`user_id`=?,`category_id`=?,`amount`=?,`type`=?,`description`=?,`date`=? WHERE `transactions`.`deleted_at` IS NULL AND `id` = ?")).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, userID, req.CategoryID, req.Amount, req.Type, req.Description, sqlmock.AnyArg(), transactionID).
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE `transactions`.`id` = ? AND `transactions`.`deleted_at` IS NULL ORDER BY `transactions`.`id` LIMIT ? FOR UPDATE")).
+		WithArgs(transactionID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id", "user_id", "category_id", "amount", "type", "date"}).
+			AddRow(transactionID, scope.WorkspaceID, userID, 1, 1000.0, "income", time.Date(2024, time.December, 20, 0, 0, 0, 0, time.UTC)))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE `transactions` SET `created_at`=?,`updated_at`=?,`deleted_at`=?,`workspace_id`=?,`user_id`=?,`category_id`=?,`amount`=?,`type`=?,`description`=?,`date`=? WHERE `transactions`.`deleted_at` IS NULL AND `id` = ?")).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, scope.WorkspaceID, userID, req.CategoryID, req.Amount, req.Type, req.Description, sqlmock.AnyArg(), transactionID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// transaksi pindah dari desember 2024 ke januari 2025
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `monthly_aggregates`")).
+		WithArgs(scope.WorkspaceID, userID, uint(1), time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC), "income", -1000.0, int64(-1), sqlmock.AnyArg(),
+			-1000.0, int64(-1), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `monthly_aggregates`")).
+		WithArgs(scope.WorkspaceID, userID, req.CategoryID, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), "income", req.Amount, int64(1), sqlmock.AnyArg(),
+			req.Amount, int64(1), sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	suite.mock.ExpectCommit()
 
-	result, err := suite.service.UpdateTransaction(userID, transactionID, req)
+	result, err := suite.service.UpdateTransaction(scope, transactionID, req)
 
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), result)
@@ -207,20 +231,31 @@ func (suite *TransactionServiceTestSuite) TestUpdateTransaction() {
 
 func (suite *TransactionServiceTestSuite) TestDeleteTransaction() {
 	userID := uint(1)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: userID}
 	transactionID := uint(1)
 
 	suite.mock.ExpectBegin()
-	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE `transactions` SET `deleted_at`=? WHERE (id = ? AND user_id = ?) AND `transactions`.`deleted_at` IS NULL")).
-		WithArgs(sqlmock.AnyArg(), transactionID, userID).
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `transactions` WHERE (id = ? AND workspace_id = ?) AND `transactions`.`deleted_at` IS NULL ORDER BY `transactions`.`id` LIMIT ? FOR UPDATE")).
+		WithArgs(transactionID, scope.WorkspaceID, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "workspace_id", "user_id", "category_id", "amount", "type", "date"}).
+			AddRow(transactionID, scope.WorkspaceID, userID, 2, 250.0, "expense", time.Date(2025, time.January, 29, 0, 0, 0, 0, time.UTC)))
+	suite.mock.ExpectExec(regexp.QuoteMeta("UPDATE `transactions` SET `deleted_at`=? WHERE `transactions`.`id` = ? AND `transactions`.`deleted_at` IS NULL")).
+		WithArgs(sqlmock.AnyArg(), transactionID).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// soft delete mengurangi agregat bulan transaksi
+	suite.mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `monthly_aggregates`")).
+		WithArgs(scope.WorkspaceID, userID, uint(2), time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), "expense", -250.0, int64(-1), sqlmock.AnyArg(),
+			-250.0, int64(-1), sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	suite.mock.ExpectCommit()
 
-	err := suite.service.DeleteTransaction(userID, transactionID)
+	err := suite.service.DeleteTransaction(scope, transactionID)
 	assert.NoError(suite.T(), err)
 }
 
 func (suite *TransactionServiceTestSuite) TestCreateTransaction_CategoryNotFound() {
 	userID := uint(1)
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: userID}
 	req := request.CreateTransactionRequest{
 		CategoryID:  999,
 		Amount:      1000.0,
@@ -229,16 +264,47 @@ func (suite *TransactionServiceTestSuite) TestCreateTransaction_CategoryNotFound
 		Date:        "2025-01-29",
 	}
 
-	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE `categories`.`id` = ? AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
-		WithArgs(req.CategoryID, 1).
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE (id = ? AND workspace_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
+		WithArgs(req.CategoryID, scope.WorkspaceID, 1).
 		WillReturnError(gorm.ErrRecordNotFound)
 
-	result, err := suite.service.CreateTransaction(userID, req)
+	result, err := suite.service.CreateTransaction(scope, req)
 	assert.Error(suite.T(), err)
 	assert.Nil(suite.T(), result)
 	assert.Contains(suite.T(), err.Error(), "category not found")
 }
 
+func (suite *TransactionServiceTestSuite) TestCreateTransaction_CategoryFromOtherWorkspace() {
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: 1}
+	req := request.CreateTransactionRequest{
+		CategoryID: 7, // milik workspace lain
+		Amount:     1000.0,
+		Type:       "expense",
+		Date:       "2025-01-29",
+	}
+
+	suite.mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `categories` WHERE (id = ? AND workspace_id = ?) AND `categories`.`deleted_at` IS NULL ORDER BY `categories`.`id` LIMIT ?")).
+		WithArgs(req.CategoryID, scope.WorkspaceID, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	result, err := suite.service.CreateTransaction(scope, req)
+	assert.Nil(suite.T(), result)
+	assert.ErrorIs(suite.T(), err, service.ErrResourceNotFound)
+
+	var accessErr *service.AccessError
+	assert.ErrorAs(suite.T(), err, &accessErr)
+	assert.Equal(suite.T(), service.ResourceCategory, accessErr.Resource)
+}
+
+func (suite *TransactionServiceTestSuite) TestDeleteTransaction_Viewer() {
+	scope := request.WorkspaceScope{WorkspaceID: 1, UserID: 2, Role: entity.WorkspaceRoleViewer}
+
+	// viewer ditolak sebelum query apapun dijalankan
+	err := suite.service.DeleteTransaction(scope, 1)
+	assert.ErrorIs(suite.T(), err, service.ErrResourceForbidden)
+	assert.NoError(suite.T(), suite.mock.ExpectationsWereMet())
+}
+
 func TestTransactionServiceSuite(t *testing.T) {
 	suite.Run(t, new(TransactionServiceTestSuite))
 }
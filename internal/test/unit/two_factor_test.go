@@ -0,0 +1,98 @@
+package unit
+
+import (
+	"bytes"
+	"go-fintrack/internal/utility"
+	"image/png"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateTOTPCode(t *testing.T) {
+	// test vector RFC 6238 (SHA1), secret ASCII "12345678901234567890"
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	tests := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		code, err := utility.GenerateTOTPCode(secret, utility.TOTPStep(time.Unix(tt.unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, code)
+	}
+}
+
+func TestValidateTOTPCode(t *testing.T) {
+	secret := utility.GenerateTOTPSecret()
+	now := time.Now()
+
+	current, _ := utility.GenerateTOTPCode(secret, utility.TOTPStep(now))
+	previous, _ := utility.GenerateTOTPCode(secret, utility.TOTPStep(now)-1)
+	stale, _ := utility.GenerateTOTPCode(secret, utility.TOTPStep(now)-5)
+
+	step, ok := utility.ValidateTOTPCode(secret, current, now)
+	assert.True(t, ok)
+	assert.Equal(t, utility.TOTPStep(now), step)
+
+	_, ok = utility.ValidateTOTPCode(secret, previous, now)
+	assert.True(t, ok, "code from previous period should be accepted for clock drift")
+
+	if stale != current && stale != previous {
+		_, ok = utility.ValidateTOTPCode(secret, stale, now)
+		assert.False(t, ok)
+	}
+
+	_, ok = utility.ValidateTOTPCode(secret, "12345", now)
+	assert.False(t, ok)
+}
+
+func TestBuildOTPAuthURI(t *testing.T) {
+	uri := utility.BuildOTPAuthURI("FinTrack", "test@example.com", "SECRET")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/FinTrack:test@example.com?"))
+	assert.Contains(t, uri, "secret=SECRET")
+	assert.Contains(t, uri, "issuer=FinTrack")
+}
+
+func TestEncodeQRCodePNG(t *testing.T) {
+	uri := utility.BuildOTPAuthURI("FinTrack", "test@example.com", utility.GenerateTOTPSecret())
+
+	data, err := utility.EncodeQRCodePNG(uri, 4)
+	assert.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+
+	// ukuran = (4*versi + 17 + quiet zone 8) * scale
+	width := img.Bounds().Dx()
+	assert.Equal(t, width, img.Bounds().Dy())
+	assert.Equal(t, 0, width%4)
+	assert.Equal(t, 0, (width/4-8-17)%4)
+
+	// pojok kiri atas finder pattern harus gelap, quiet zone harus terang
+	r, _, _, _ := img.At(4*4, 4*4).RGBA()
+	assert.Equal(t, uint32(0), r)
+	r, _, _, _ = img.At(0, 0).RGBA()
+	assert.NotEqual(t, uint32(0), r)
+
+	_, err = utility.EncodeQRCodePNG(strings.Repeat("a", 3000), 4)
+	assert.ErrorIs(t, err, utility.ErrQRCodeTooLong)
+}
+
+func TestGenerateRecoveryCode(t *testing.T) {
+	code := utility.GenerateRecoveryCode()
+
+	assert.Regexp(t, regexp.MustCompile(`^[A-Z2-9]{5}-[A-Z2-9]{5}$`), code)
+	assert.Equal(t, strings.ReplaceAll(code, "-", ""), utility.NormalizeRecoveryCode(" "+strings.ToLower(code)+" "))
+}
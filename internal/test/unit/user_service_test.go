@@ -3,6 +3,7 @@ package unit
 import (
 	"errors"
 	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/payload/request"
 	"go-fintrack/internal/service"
 	"go-fintrack/internal/utility"
 	"os"
@@ -16,7 +17,7 @@ import (
 	"gorm.io/gorm/logger"
 )
 
-func setupTestDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+func setupTestDB(t testing.TB) (*gorm.DB, sqlmock.Sqlmock) {
 	// Set JWT secret untuk testing
 	os.Setenv("JWT_SECRET", "test-secret-key")
 
@@ -77,7 +78,7 @@ func TestRegisterUser(t *testing.T) {
 						"test@example.com", // email
 						"testUser",         // username
 						sqlmock.AnyArg(),   // password (hashed)
-						false,              // is_admin
+						"user",             // role
 					).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 				mock.ExpectCommit()
@@ -159,11 +160,11 @@ func TestLogin(t *testing.T) {
 					WithArgs("test@example.com", "test@example.com").
 					WillReturnRows(sqlmock.NewRows([]string{
 						"id", "created_at", "updated_at", "deleted_at",
-						"name", "email", "username", "password", "is_admin",
+						"name", "email", "username", "password", "role",
 					}).AddRow(
 						1, time.Now(), time.Now(), nil,
 						"Test User", "test@example.com", "testUser",
-						hashedPassword, false,
+						hashedPassword, "user",
 					))
 			},
 			expectedUser: &entity.User{
@@ -174,7 +175,7 @@ func TestLogin(t *testing.T) {
 				Email:    "test@example.com",
 				Username: "testUser",
 				Password: hashedPassword,
-				IsAdmin:  false,
+				Role:     entity.RoleUser,
 			},
 			expectedError: nil,
 		},
@@ -199,11 +200,11 @@ func TestLogin(t *testing.T) {
 					WithArgs("test@example.com", "test@example.com").
 					WillReturnRows(sqlmock.NewRows([]string{
 						"id", "created_at", "updated_at", "deleted_at",
-						"name", "email", "username", "password", "is_admin",
+						"name", "email", "username", "password", "role",
 					}).AddRow(
 						1, time.Now(), time.Now(), nil,
 						"Test User", "test@example.com", "testUser",
-						hashedPassword, false,
+						hashedPassword, "user",
 					))
 			},
 			expectedUser:  nil,
@@ -221,7 +222,7 @@ func TestLogin(t *testing.T) {
 			}
 
 			// Execute
-			token, user, err := userService.Login(tt.inputCredential, tt.inputPassword)
+			token, user, err := userService.Login(tt.inputCredential, tt.inputPassword, request.ClientInfo{})
 
 			// Assert
 			if tt.expectedError != nil {
@@ -241,7 +242,7 @@ func TestLogin(t *testing.T) {
 					assert.Equal(t, tt.expectedUser.Email, user.Email)
 					assert.Equal(t, tt.expectedUser.Username, user.Username)
 					assert.Equal(t, tt.expectedUser.Password, user.Password)
-					assert.Equal(t, tt.expectedUser.IsAdmin, user.IsAdmin)
+					assert.Equal(t, tt.expectedUser.Role, user.Role)
 				}
 			}
 
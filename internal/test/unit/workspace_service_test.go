@@ -0,0 +1,111 @@
+package unit
+
+import (
+	"errors"
+	"go-fintrack/internal/payload/entity"
+	"go-fintrack/internal/service"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func memberRows(workspaceID, userID uint, role string, personal bool) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "workspace_id", "user_id", "role", "Workspace__id", "Workspace__is_personal"}).
+		AddRow(1, workspaceID, userID, role, workspaceID, personal)
+}
+
+func TestWorkspaceMemberCanWrite(t *testing.T) {
+	assert.True(t, (&entity.WorkspaceMember{Role: entity.WorkspaceRoleOwner}).CanWrite())
+	assert.True(t, (&entity.WorkspaceMember{Role: entity.WorkspaceRoleEditor}).CanWrite())
+	assert.False(t, (&entity.WorkspaceMember{Role: entity.WorkspaceRoleViewer}).CanWrite())
+}
+
+func TestRemoveMemberPersonalWorkspace(t *testing.T) {
+	db, mock := setupTestDB(t)
+	workspaceService := service.NewWorkspaceService(db, nil, "")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `workspace_members`").
+		WillReturnRows(memberRows(1, 1, entity.WorkspaceRoleOwner, true))
+	mock.ExpectRollback()
+
+	assert.Equal(t, service.ErrPersonalWorkspace, workspaceService.RemoveMember(1, 1, 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoveMemberLastOwner(t *testing.T) {
+	db, mock := setupTestDB(t)
+	workspaceService := service.NewWorkspaceService(db, nil, "")
+
+	// owner terakhir tidak boleh keluar sebelum menyerahkan role owner ke member lain
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `workspace_members`").
+		WillReturnRows(memberRows(2, 1, entity.WorkspaceRoleOwner, false))
+	mock.ExpectQuery("SELECT `id` FROM `workspace_members` WHERE (.+) ORDER BY id FOR UPDATE").
+		WithArgs(2, entity.WorkspaceRoleOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectRollback()
+
+	assert.Equal(t, service.ErrLastWorkspaceOwner, workspaceService.RemoveMember(1, 2, 1))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateMemberRoleLocksOwners(t *testing.T) {
+	db, mock := setupTestDB(t)
+	workspaceService := service.NewWorkspaceService(db, nil, "")
+
+	// baris owner dikunci sebelum dihitung, penurunan role bersamaan menunggu lalu hanya melihat owner tersisa
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `workspace_members`").
+		WillReturnRows(memberRows(2, 1, entity.WorkspaceRoleOwner, false))
+	mock.ExpectQuery("SELECT (.+) FROM `workspace_members`").
+		WillReturnRows(memberRows(2, 3, entity.WorkspaceRoleOwner, false))
+	mock.ExpectQuery("SELECT `id` FROM `workspace_members` WHERE (.+) ORDER BY id FOR UPDATE").
+		WithArgs(2, entity.WorkspaceRoleOwner).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	mock.ExpectExec("UPDATE `workspace_members` SET `role`=(.+)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, workspaceService.UpdateMemberRole(1, 2, 3, entity.WorkspaceRoleViewer))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoveMemberDeletesPermanently(t *testing.T) {
+	db, mock := setupTestDB(t)
+	workspaceService := service.NewWorkspaceService(db, nil, "")
+
+	// baris member dihapus permanen agar member bisa diundang lagi tanpa melanggar idx_workspace_member
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT (.+) FROM `workspace_members`").
+		WillReturnRows(memberRows(2, 3, entity.WorkspaceRoleEditor, false))
+	mock.ExpectExec("DELETE FROM `workspace_members` WHERE `workspace_members`.`id` = ?").
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, workspaceService.RemoveMember(3, 2, 3))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnsurePersonalWorkspaceConcurrentCreate(t *testing.T) {
+	db, mock := setupTestDB(t)
+	workspaceService := service.NewWorkspaceService(db, nil, "")
+
+	// request lain sudah membuat workspace personal di antara lookup dan insert, unique index menolak insert
+	mock.ExpectQuery("SELECT (.+) FROM `workspace_members`").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO `workspaces`").
+		WillReturnError(errors.New("duplicate key value violates unique constraint \"idx_workspaces_personal_owner\""))
+	mock.ExpectRollback()
+	mock.ExpectQuery("SELECT (.+) FROM `workspace_members`").
+		WillReturnRows(memberRows(7, 1, entity.WorkspaceRoleOwner, true))
+
+	member, err := workspaceService.EnsurePersonalWorkspace(1)
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), member.WorkspaceID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,80 @@
+package utility
+
+import (
+	"go-fintrack/internal/payload/entity"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MonthStart mengembalikan tanggal 1 bulan kalender dari t, 00:00 UTC
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// ApplyTransactionAggregate menambah (sign 1) atau mengurangi (sign -1) kontribusi transaksi pada
+// monthly_aggregates. Harus dipanggil dengan tx yang sama dengan perubahan transaksinya.
+func ApplyTransactionAggregate(tx *gorm.DB, transaction *entity.Transaction, sign int) error {
+	aggregate := entity.MonthlyAggregate{
+		WorkspaceID:      transaction.WorkspaceID,
+		UserID:           transaction.UserID,
+		CategoryID:       transaction.CategoryID,
+		Month:            MonthStart(transaction.Date),
+		Type:             transaction.Type,
+		Total:            float64(sign) * transaction.Amount,
+		TransactionCount: int64(sign),
+		UpdatedAt:        time.Now(),
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "workspace_id"}, {Name: "user_id"}, {Name: "category_id"}, {Name: "month"}, {Name: "type"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"total":             gorm.Expr("monthly_aggregates.total + ?", aggregate.Total),
+			"transaction_count": gorm.Expr("monthly_aggregates.transaction_count + ?", aggregate.TransactionCount),
+			"updated_at":        aggregate.UpdatedAt,
+		}),
+	}).Create(&aggregate).Error
+}
+
+// RebuildMonthlyAggregates menghitung ulang monthly_aggregates dari tabel transactions. Tanpa workspaceIDs
+// semua workspace dibangun ulang, jalankan di dalam database transaction agar pembaca tidak melihat tabel kosong.
+func RebuildMonthlyAggregates(tx *gorm.DB, workspaceIDs ...uint) error {
+	deleteQuery := tx.Session(&gorm.Session{AllowGlobalUpdate: true})
+	if len(workspaceIDs) > 0 {
+		deleteQuery = deleteQuery.Where("workspace_id IN ?", workspaceIDs)
+	}
+	if err := deleteQuery.Delete(&entity.MonthlyAggregate{}).Error; err != nil {
+		return err
+	}
+
+	// tanggal transaksi disimpan 00:00 UTC, bulan dihitung di UTC agar tidak bergantung timezone session
+	month := "date_trunc('month', date AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'"
+	query := `INSERT INTO monthly_aggregates (workspace_id, user_id, category_id, month, type, total, transaction_count, updated_at)
+		SELECT workspace_id, user_id, category_id, ` + month + `, type, SUM(amount), COUNT(*), NOW()
+		FROM transactions WHERE deleted_at IS NULL`
+	args := []interface{}{}
+	if len(workspaceIDs) > 0 {
+		query += " AND workspace_id IN ?"
+		args = append(args, workspaceIDs)
+	}
+	query += " GROUP BY workspace_id, user_id, category_id, " + month + ", type"
+
+	return tx.Exec(query, args...).Error
+}
+
+// aggregateSpan membagi rentang [start, end) menjadi bulan kalender penuh [from, to) yang dibaca dari
+// monthly_aggregates, sisanya ([start, from) dan [to, end)) dibaca langsung dari transactions.
+// to selalu awal bulan dari end sehingga saldo sebelum to juga bisa dibaca dari agregat.
+func aggregateSpan(start, end time.Time) (time.Time, time.Time) {
+	to := MonthStart(end)
+	from := MonthStart(start)
+	if from.Before(start) {
+		from = from.AddDate(0, 1, 0)
+	}
+	if from.After(to) {
+		from = to
+	}
+	return from, to
+}
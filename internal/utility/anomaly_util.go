@@ -0,0 +1,142 @@
+package utility
+
+import (
+	"go-fintrack/internal/payload/entity"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// transaksi ditandai jika nominalnya minimal 3x median transaksi sebelumnya di kategori yang sama
+	LargeTransactionRatio = 3.0
+	// minimal jumlah transaksi sebelumnya di kategori agar median bisa dipercaya
+	MinLargeTransactionHistory = 5
+	// riwayat transaksi yang dipakai sebagai pembanding
+	AnomalyHistoryDays = 180
+
+	// kategori ditandai jika pengeluaran siklus bulanan berjalan minimal 50% di atas laju normalnya
+	CategoryPaceRatio = 1.5
+	// jumlah siklus sebelumnya yang dibaca, minimal MinCategoryPaceMonths siklus dengan pengeluaran
+	CategoryPaceLookbackMonths = 6
+	MinCategoryPaceMonths      = 3
+	// awal siklus laju dihitung minimal dari 7 hari agar satu transaksi di hari pertama tidak langsung ditandai
+	MinCategoryPaceDays = 7
+)
+
+// ExpenseRecord adalah expense yang dianalisis, diurutkan berdasarkan tanggal lalu ID
+type ExpenseRecord struct {
+	TransactionID uint      `gorm:"column:id"`
+	CategoryID    uint      `gorm:"column:category_id"`
+	Amount        float64   `gorm:"column:amount"`
+	Date          time.Time `gorm:"column:date"`
+}
+
+// Median mengembalikan nilai tengah values, 0 jika kosong
+func Median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// DetectLargeTransactions membandingkan setiap expense sejak since dengan median expense kategori yang sama
+// dalam AnomalyHistoryDays sebelumnya. Median dipakai agar satu transaksi besar tidak menggeser batas normal.
+func DetectLargeTransactions(expenses []ExpenseRecord, since time.Time, config PeriodConfig) []entity.SpendingAnomaly {
+	byCategory := map[uint][]ExpenseRecord{}
+	for _, expense := range expenses {
+		byCategory[expense.CategoryID] = append(byCategory[expense.CategoryID], expense)
+	}
+
+	var anomalies []entity.SpendingAnomaly
+	for _, records := range byCategory {
+		for i, record := range records {
+			if record.Date.Before(since) {
+				continue
+			}
+
+			historyStart := record.Date.AddDate(0, 0, -AnomalyHistoryDays)
+			var history []float64
+			for _, previous := range records[:i] {
+				if !previous.Date.Before(historyStart) {
+					history = append(history, previous.Amount)
+				}
+			}
+			if len(history) < MinLargeTransactionHistory {
+				continue
+			}
+
+			median := Median(history)
+			if median <= 0 || record.Amount < median*LargeTransactionRatio {
+				continue
+			}
+
+			transactionID := record.TransactionID
+			anomalies = append(anomalies, entity.SpendingAnomaly{
+				Kind:          entity.AnomalyLargeTransaction,
+				TransactionID: &transactionID,
+				CategoryID:    record.CategoryID,
+				Date:          record.Date,
+				Month:         CycleMonthOf(record.Date, config),
+				Amount:        record.Amount,
+				Baseline:      median,
+				Ratio:         roundRatio(record.Amount / median),
+			})
+		}
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool { return *anomalies[i].TransactionID < *anomalies[j].TransactionID })
+	return anomalies
+}
+
+// DetectCategoryPace membandingkan pengeluaran kategori dari awal siklus bulanan sampai today dengan median total
+// siklus sebelumnya yang dikalikan porsi siklus yang sudah berjalan
+func DetectCategoryPace(monthToDate map[uint]float64, monthlyHistory map[uint][]float64, today time.Time, config PeriodConfig) []entity.SpendingAnomaly {
+	cycleStart := PeriodStart(today, GranularityMonth, config)
+	cycleEnd := AddPeriods(cycleStart, GranularityMonth, 1, config)
+	daysInMonth := int(cycleEnd.Sub(cycleStart).Hours() / 24)
+	elapsed := int(CalendarDate(today).Sub(cycleStart).Hours()/24) + 1
+	if elapsed < MinCategoryPaceDays {
+		elapsed = MinCategoryPaceDays
+	}
+	if elapsed > daysInMonth {
+		elapsed = daysInMonth
+	}
+
+	var anomalies []entity.SpendingAnomaly
+	for categoryID, spent := range monthToDate {
+		history := monthlyHistory[categoryID]
+		if len(history) < MinCategoryPaceMonths {
+			continue
+		}
+
+		expected := Median(history) * float64(elapsed) / float64(daysInMonth)
+		if expected <= 0 || spent < expected*CategoryPaceRatio {
+			continue
+		}
+
+		anomalies = append(anomalies, entity.SpendingAnomaly{
+			Kind:       entity.AnomalyCategoryPace,
+			CategoryID: categoryID,
+			Date:       today,
+			Month:      MonthStart(cycleStart),
+			Amount:     spent,
+			Baseline:   expected,
+			Ratio:      roundRatio(spent / expected),
+		})
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool { return anomalies[i].CategoryID < anomalies[j].CategoryID })
+	return anomalies
+}
+
+func roundRatio(ratio float64) float64 {
+	return math.Round(ratio*100) / 100
+}
@@ -0,0 +1,32 @@
+package utility
+
+import "strings"
+
+// ChartPalette dipakai untuk kategori yang warnanya tidak bisa dibaca sebagai warna chart
+var ChartPalette = []string{"#10B981", "#3B82F6", "#F59E0B", "#6366F1", "#EC4899", "#8B5CF6"}
+
+// warna shade 500 Tailwind, cukup pekat untuk garis dan batang chart
+var tailwindColors = map[string]string{
+	"slate": "#64748B", "gray": "#6B7280", "zinc": "#71717A", "neutral": "#737373", "stone": "#78716C",
+	"red": "#EF4444", "orange": "#F97316", "amber": "#F59E0B", "yellow": "#EAB308", "lime": "#84CC16",
+	"green": "#22C55E", "emerald": "#10B981", "teal": "#14B8A6", "cyan": "#06B6D4", "sky": "#0EA5E9",
+	"blue": "#3B82F6", "indigo": "#6366F1", "violet": "#8B5CF6", "purple": "#A855F7", "fuchsia": "#D946EF",
+	"pink": "#EC4899", "rose": "#F43F5E",
+}
+
+// CategoryChartColor mengubah Color kategori menjadi warna chart. Warna CSS (#hex, rgb()) dipakai apa adanya,
+// class Tailwind seperti bg-blue-100 memakai shade 500 dari hue yang sama, selain itu palette ke-index.
+func CategoryChartColor(color string, index int) string {
+	color = strings.TrimSpace(color)
+	if strings.HasPrefix(color, "#") || strings.HasPrefix(color, "rgb") || strings.HasPrefix(color, "hsl") {
+		return color
+	}
+
+	parts := strings.Split(color, "-")
+	if len(parts) >= 2 {
+		if hex, ok := tailwindColors[parts[1]]; ok {
+			return hex
+		}
+	}
+	return ChartPalette[index%len(ChartPalette)]
+}
@@ -2,15 +2,18 @@ package utility
 
 import (
 	"errors"
+	"go-fintrack/internal/payload/request"
 	"strconv"
 
+	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
 var (
-	ErrUserIDNotFound = errors.New("user ID not found in context")
-	ErrInvalidUserID  = errors.New("invalid user ID format")
+	ErrUserIDNotFound    = errors.New("user ID not found in context")
+	ErrInvalidUserID     = errors.New("invalid user ID format")
+	ErrWorkspaceNotFound = errors.New("workspace not found in context")
 )
 
 func GetUserIDFromContext(ctx *gin.Context) (uint, error) {
@@ -44,3 +47,44 @@ func GetUserIDFromContext(ctx *gin.Context) (uint, error) {
 		return 0, ErrInvalidUserID
 	}
 }
+
+// GetWorkspaceScope mengambil workspace aktif yang sudah divalidasi middleware WorkspaceContext
+func GetWorkspaceScope(ctx *gin.Context) (request.WorkspaceScope, error) {
+	userID, err := GetUserIDFromContext(ctx)
+	if err != nil {
+		return request.WorkspaceScope{}, err
+	}
+
+	workspaceID := ctx.GetUint("workspaceID")
+	if workspaceID == 0 {
+		return request.WorkspaceScope{}, ErrWorkspaceNotFound
+	}
+
+	return request.WorkspaceScope{
+		WorkspaceID: workspaceID,
+		UserID:      userID,
+		Role:        ctx.GetString("workspaceRole"),
+	}, nil
+}
+
+// GetWorkspaceClaim mengambil claim wid dari JWT, 0 jika token belum pernah switch workspace
+func GetWorkspaceClaim(ctx *gin.Context) uint {
+	claims, _ := ctx.Get("claims")
+	mapClaims, _ := claims.(jwt.MapClaims)
+	if wid, ok := mapClaims["wid"].(float64); ok {
+		return uint(wid)
+	}
+	return 0
+}
+
+func GetSessionIDFromContext(ctx *gin.Context) string {
+	return ctx.GetString("sessionID")
+}
+
+// GetClientInfo mengambil user agent dan IP client, sama seperti yang dicatat LoggingMiddleware
+func GetClientInfo(ctx *gin.Context) request.ClientInfo {
+	return request.ClientInfo{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+}
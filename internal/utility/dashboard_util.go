@@ -1,6 +1,9 @@
 package utility
 
 import (
+	"context"
+	"strconv"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -10,151 +13,288 @@ type DashboardUtil struct {
 	DB *gorm.DB
 }
 
-// Financial Overview
-func (u *DashboardUtil) CalculateCurrentBalance(userID uint) (float64, error) {
-	var balance float64
-	err := u.DB.Table("transactions").
-		Select("COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE -amount END), 0)").
-		Where("user_id = ?", userID).
-		Row().
-		Scan(&balance)
-	return balance, err
+// DashboardTotals adalah saldo dan total income/expense dalam satu rentang
+type DashboardTotals struct {
+	Balance float64
+	Income  float64
+	Expense float64
 }
 
-func (u *DashboardUtil) CalculateMonthlyIncome(userID uint, startOfMonth string) (float64, error) {
-	var income float64
-	err := u.DB.Table("transactions").
-		Select("COALESCE(SUM(amount), 0)").
-		Where("user_id = ? AND type = 'income' AND date >= ?", userID, startOfMonth).
-		Row().
-		Scan(&income)
-	return income, err
+// CategoryTotal adalah total expense satu kategori
+type CategoryTotal struct {
+	Category string  `gorm:"column:category_name"`
+	Color    string  `gorm:"column:category_color"` // Color kategori, lihat CategoryChartColor
+	Total    float64 `gorm:"column:total"`
 }
 
-func (u *DashboardUtil) CalculateMonthlyExpense(userID uint, startOfMonth string) (float64, error) {
-	var expense float64
-	err := u.DB.Table("transactions").
-		Select("COALESCE(SUM(amount), 0)").
-		Where("user_id = ? AND type = 'expense' AND date >= ?", userID, startOfMonth).
-		Row().
-		Scan(&expense)
-	return expense, err
+// transaksi aktif di workspace, tanggal transaksi disimpan sebagai tanggal kalender 00:00 UTC
+func (u *DashboardUtil) transactions(workspaceID uint) *gorm.DB {
+	return u.DB.Table("transactions").
+		Where("transactions.workspace_id = ? AND transactions.deleted_at IS NULL", workspaceID)
 }
 
-func (u *DashboardUtil) CalculateTotalSavings(userID uint) (float64, error) {
-	var savings float64
-	err := u.DB.Table("transactions").
-		Select("COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE -amount END), 0)").
-		Where("user_id = ?", userID).
+func (u *DashboardUtil) aggregates(workspaceID uint) *gorm.DB {
+	return u.DB.Table("monthly_aggregates").Where("monthly_aggregates.workspace_id = ?", workspaceID)
+}
+
+// Financial Overview
+// CalculateTotals menghitung saldo sebelum end serta income dan expense di rentang [start, end) dalam satu query.
+// Bulan penuh dibaca dari monthly_aggregates, hanya bulan yang terpotong di ujung rentang yang membaca transaksi.
+func (u *DashboardUtil) CalculateTotals(ctx context.Context, workspaceID uint, start, end time.Time) (DashboardTotals, error) {
+	from, to := aggregateSpan(start, end)
+
+	aggregated := u.aggregates(workspaceID).
+		Select(`SUM(CASE WHEN type = 'income' THEN total ELSE -total END) AS balance,
+			SUM(CASE WHEN type = 'income' AND month >= ? THEN total ELSE 0 END) AS income,
+			SUM(CASE WHEN type = 'expense' AND month >= ? THEN total ELSE 0 END) AS expense`, from, from).
+		Where("month < ?", to)
+	partial := u.transactions(workspaceID).
+		Select(`SUM(CASE WHEN date >= ? THEN (CASE WHEN type = 'income' THEN amount ELSE -amount END) ELSE 0 END) AS balance,
+			SUM(CASE WHEN type = 'income' AND date >= ? THEN amount ELSE 0 END) AS income,
+			SUM(CASE WHEN type = 'expense' AND date >= ? THEN amount ELSE 0 END) AS expense`, to, start, start).
+		Where("date < ? AND ((date >= ? AND date < ?) OR date >= ?)", end, start, from, to)
+
+	var totals DashboardTotals
+	err := u.DB.WithContext(ctx).
+		Raw(`SELECT COALESCE(SUM(balance), 0), COALESCE(SUM(income), 0), COALESCE(SUM(expense), 0)
+			FROM (? UNION ALL ?) AS totals`, aggregated, partial).
 		Row().
-		Scan(&savings)
-	return savings, err
+		Scan(&totals.Balance, &totals.Income, &totals.Expense)
+	return totals, err
 }
 
 // Expense Analysis
-func (u *DashboardUtil) GetLastSixMonthsData(userID uint) ([]string, []float64, []float64, error) {
-	var labels []string
-	var incomeData []float64
-	var expenseData []float64
-
-	// Get current time
-	now := time.Now().UTC()
-
-	// Generate last 6 months
-	for i := 5; i >= 0; i-- {
-		date := now.AddDate(0, -i, 0)
-		startOfMonth := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
-		endOfMonth := startOfMonth.AddDate(0, 1, 0).Add(-time.Second)
-
-		// Get monthly income
-		var income float64
-		err := u.DB.Table("transactions").
-			Select("COALESCE(SUM(amount), 0)").
-			Where("user_id = ? AND type = 'income' AND deleted_at IS NULL AND date BETWEEN ? AND ?",
-				userID, startOfMonth.Format("2006-01-02"), endOfMonth.Format("2006-01-02")).
-			Row().
-			Scan(&income)
-		if err != nil {
-			return nil, nil, nil, err
-		}
+// GetIncomeExpenseSeries menghitung income dan expense per periode dengan satu query GROUP BY periode dan tipe.
+// Periode ditentukan lewat CASE dari batas akhir tiap periode sehingga siklus bulanan custom tetap benar.
+// Periode yang tepat satu atau beberapa bulan kalender penuh dibaca dari monthly_aggregates.
+func (u *DashboardUtil) GetIncomeExpenseSeries(ctx context.Context, workspaceID uint, periods []Period) ([]float64, []float64, error) {
+	incomeData := make([]float64, len(periods))
+	expenseData := make([]float64, len(periods))
+	if len(periods) == 0 {
+		return incomeData, expenseData, nil
+	}
 
-		// Get monthly expense
-		var expense float64
-		err = u.DB.Table("transactions").
-			Select("COALESCE(SUM(amount), 0)").
-			Where("user_id = ? AND type = 'expense' AND deleted_at IS NULL AND date BETWEEN ? AND ?",
-				userID, startOfMonth.Format("2006-01-02"), endOfMonth.Format("2006-01-02")).
-			Row().
-			Scan(&expense)
-		if err != nil {
-			return nil, nil, nil, err
-		}
+	first, last := periods[0].Start, periods[len(periods)-1].End
+	from, to := alignedPeriods(periods)
+
+	monthBucket, monthArgs := periodBucket("month", periods)
+	aggregated := u.aggregates(workspaceID).
+		Select(monthBucket+" AS bucket, type, SUM(total) AS total", monthArgs...).
+		Where("month >= ? AND month < ?", from, to).
+		Group("bucket, type")
+
+	dateBucket, dateArgs := periodBucket("date", periods)
+	partial := u.transactions(workspaceID).
+		Select(dateBucket+" AS bucket, type, SUM(amount) AS total", dateArgs...).
+		Where("(date >= ? AND date < ?) OR (date >= ? AND date < ?)", first, from, to, last).
+		Group("bucket, type")
 
-		labels = append(labels, date.Format("Jan"))
-		incomeData = append(incomeData, income)
-		expenseData = append(expenseData, expense)
+	var rows []struct {
+		Bucket int
+		Type   string
+		Total  float64
+	}
+	err := u.DB.WithContext(ctx).
+		Raw("SELECT bucket, type, SUM(total) AS total FROM (? UNION ALL ?) AS series GROUP BY bucket, type", aggregated, partial).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, row := range rows {
+		if row.Bucket < 0 || row.Bucket >= len(periods) {
+			continue
+		}
+		if row.Type == "income" {
+			incomeData[row.Bucket] += row.Total
+		} else {
+			expenseData[row.Bucket] += row.Total
+		}
 	}
 
-	return labels, incomeData, expenseData, nil
+	return incomeData, expenseData, nil
 }
 
-func (u *DashboardUtil) GetCategoryDistribution(userID uint) ([]string, []float64, error) {
-	type CategoryTotal struct {
-		Category string  `gorm:"column:category_name"`
-		Total    float64 `gorm:"column:total"`
+// GetCategorySeries menghitung total transaksi bertipe txType per periode untuk setiap kategori categoryIDs
+// dengan satu query GROUP BY periode dan kategori, periode bulan kalender penuh dibaca dari monthly_aggregates
+func (u *DashboardUtil) GetCategorySeries(ctx context.Context, workspaceID uint, txType string, categoryIDs []uint, periods []Period) (map[uint][]float64, error) {
+	series := make(map[uint][]float64, len(categoryIDs))
+	for _, categoryID := range categoryIDs {
+		series[categoryID] = make([]float64, len(periods))
+	}
+	if len(periods) == 0 || len(categoryIDs) == 0 {
+		return series, nil
 	}
 
-	var results []CategoryTotal
+	first, last := periods[0].Start, periods[len(periods)-1].End
+	from, to := alignedPeriods(periods)
 
-	err := u.DB.Table("transactions").
-		Select("categories.name as category_name, COALESCE(SUM(transactions.amount), 0) as total").
-		Joins("LEFT JOIN categories ON transactions.category_id = categories.id").
-		Where("transactions.user_id = ? AND transactions.type = 'expense' AND transactions.deleted_at IS NULL AND categories.deleted_at IS NULL", userID).
-		Group("categories.name").
-		Order("total DESC").
-		Find(&results).Error
+	monthBucket, monthArgs := periodBucket("month", periods)
+	aggregated := u.aggregates(workspaceID).
+		Select(monthBucket+" AS bucket, category_id, SUM(total) AS total", monthArgs...).
+		Where("type = ? AND category_id IN ?", txType, categoryIDs).
+		Where("month >= ? AND month < ?", from, to).
+		Group("bucket, category_id")
+
+	dateBucket, dateArgs := periodBucket("date", periods)
+	partial := u.transactions(workspaceID).
+		Select(dateBucket+" AS bucket, category_id, SUM(amount) AS total", dateArgs...).
+		Where("type = ? AND category_id IN ?", txType, categoryIDs).
+		Where("(date >= ? AND date < ?) OR (date >= ? AND date < ?)", first, from, to, last).
+		Group("bucket, category_id")
+
+	var rows []struct {
+		Bucket     int
+		CategoryID uint
+		Total      float64
+	}
+	err := u.DB.WithContext(ctx).
+		Raw("SELECT bucket, category_id, SUM(total) AS total FROM (? UNION ALL ?) AS series GROUP BY bucket, category_id", aggregated, partial).
+		Scan(&rows).Error
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	var labels []string
-	var data []float64
+	for _, row := range rows {
+		if data, ok := series[row.CategoryID]; ok && row.Bucket >= 0 && row.Bucket < len(periods) {
+			data[row.Bucket] += row.Total
+		}
+	}
+	return series, nil
+}
+
+// DailyTotal adalah total expense satu tanggal kalender
+type DailyTotal struct {
+	Date  time.Time `gorm:"column:date"`
+	Total float64   `gorm:"column:total"`
+	Count int64     `gorm:"column:count"`
+}
+
+// GetDailyExpenses menjumlahkan expense per tanggal di rentang [start, end), hanya tanggal yang punya expense
+func (u *DashboardUtil) GetDailyExpenses(ctx context.Context, workspaceID uint, start, end time.Time) ([]DailyTotal, error) {
+	var totals []DailyTotal
+	err := u.transactions(workspaceID).WithContext(ctx).
+		Select("date, SUM(amount) AS total, COUNT(*) AS count").
+		Where("type = 'expense' AND date >= ? AND date < ?", start, end).
+		Group("date").
+		Order("date").
+		Scan(&totals).Error
+	return totals, err
+}
 
-	for _, result := range results {
-		labels = append(labels, result.Category)
-		data = append(data, result.Total)
+// alignedPeriods mengembalikan rentang [from, to) dari deret periode berurutan yang tepat berupa bulan kalender
+// penuh. Jika tidak ada, from dan to sama dengan akhir periode terakhir sehingga semua dibaca dari transaksi.
+func alignedPeriods(periods []Period) (time.Time, time.Time) {
+	isAligned := func(p Period) bool {
+		return p.Start.Day() == 1 && p.End.Day() == 1
 	}
 
-	return labels, data, nil
+	i := 0
+	for i < len(periods) && !isAligned(periods[i]) {
+		i++
+	}
+	if i == len(periods) {
+		last := periods[len(periods)-1].End
+		return last, last
+	}
+
+	j := i
+	for j+1 < len(periods) && isAligned(periods[j+1]) {
+		j++
+	}
+	return periods[i].Start, periods[j].End
 }
 
-func (u *DashboardUtil) GetTopExpenseCategories(userID uint, limit int) ([]string, []float64, error) {
-	type CategoryTotal struct {
-		Category string  `gorm:"column:category_name"`
-		Total    float64 `gorm:"column:total"`
+// periodBucket membuat ekspresi CASE yang mengembalikan index periode untuk kolom tanggal column
+func periodBucket(column string, periods []Period) (string, []interface{}) {
+	var sql strings.Builder
+	args := make([]interface{}, 0, len(periods)-1)
+
+	sql.WriteString("CASE")
+	for i, period := range periods[:len(periods)-1] {
+		sql.WriteString(" WHEN " + column + " < ? THEN ")
+		sql.WriteString(strconv.Itoa(i))
+		args = append(args, period.End)
 	}
+	sql.WriteString(" ELSE ")
+	sql.WriteString(strconv.Itoa(len(periods) - 1))
+	sql.WriteString(" END")
 
-	var results []CategoryTotal
+	return sql.String(), args
+}
 
-	err := u.DB.Table("transactions").
-		Select("categories.name as category_name, COALESCE(SUM(transactions.amount), 0) as total").
+// GetExpenseByCategory menjumlahkan expense per kategori di rentang [start, end), urut dari total terbesar
+func (u *DashboardUtil) GetExpenseByCategory(ctx context.Context, workspaceID uint, start, end time.Time) ([]CategoryTotal, error) {
+	from, to := aggregateSpan(start, end)
+
+	aggregated := u.aggregates(workspaceID).
+		Select("categories.name AS category_name, MAX(categories.color) AS category_color, SUM(monthly_aggregates.total) AS total").
+		Joins("LEFT JOIN categories ON monthly_aggregates.category_id = categories.id").
+		Where("monthly_aggregates.type = 'expense' AND categories.deleted_at IS NULL").
+		Where("monthly_aggregates.month >= ? AND monthly_aggregates.month < ?", from, to).
+		Group("categories.name")
+	partial := u.transactions(workspaceID).
+		Select("categories.name AS category_name, MAX(categories.color) AS category_color, SUM(transactions.amount) AS total").
 		Joins("LEFT JOIN categories ON transactions.category_id = categories.id").
-		Where("transactions.user_id = ? AND transactions.type = 'expense' AND transactions.deleted_at IS NULL AND categories.deleted_at IS NULL", userID).
-		Group("categories.name").
-		Order("total DESC").
-		Limit(limit).
-		Find(&results).Error
-	if err != nil {
-		return nil, nil, err
-	}
+		Where("transactions.type = 'expense' AND categories.deleted_at IS NULL").
+		Where("transactions.date >= ? AND transactions.date < ?", start, end).
+		Where("(transactions.date < ? OR transactions.date >= ?)", from, to).
+		Group("categories.name")
 
-	var labels []string
-	var data []float64
+	var results []CategoryTotal
+	err := u.DB.WithContext(ctx).
+		Raw(`SELECT category_name, MAX(category_color) AS category_color, SUM(total) AS total FROM (? UNION ALL ?) AS expenses
+			GROUP BY category_name ORDER BY total DESC`, aggregated, partial).
+		Scan(&results).Error
+	return results, err
+}
 
-	for _, result := range results {
-		labels = append(labels, result.Category)
-		data = append(data, result.Total)
+// CategoryAmount adalah total satu kategori beserta ID-nya
+type CategoryAmount struct {
+	CategoryID uint    `gorm:"column:category_id"`
+	Category   string  `gorm:"column:category_name"`
+	Total      float64 `gorm:"column:total"`
+}
+
+// GetCategoryTotals menjumlahkan transaksi bertipe txType per kategori di rentang [start, end). Kategori yang
+// sudah dihapus tetap dihitung agar totalnya sama dengan daftar transaksi dengan filter tanggal yang sama.
+func (u *DashboardUtil) GetCategoryTotals(ctx context.Context, workspaceID uint, txType string, start, end time.Time) ([]CategoryAmount, error) {
+	from, to := aggregateSpan(start, end)
+
+	aggregated := u.aggregates(workspaceID).
+		Select("monthly_aggregates.category_id, SUM(monthly_aggregates.total) AS total").
+		Where("monthly_aggregates.type = ?", txType).
+		Where("monthly_aggregates.month >= ? AND monthly_aggregates.month < ?", from, to).
+		Group("monthly_aggregates.category_id")
+	partial := u.transactions(workspaceID).
+		Select("transactions.category_id, SUM(transactions.amount) AS total").
+		Where("transactions.type = ?", txType).
+		Where("transactions.date >= ? AND transactions.date < ?", start, end).
+		Where("(transactions.date < ? OR transactions.date >= ?)", from, to).
+		Group("transactions.category_id")
+
+	var results []CategoryAmount
+	err := u.DB.WithContext(ctx).
+		Raw(`SELECT totals.category_id, categories.name AS category_name, SUM(totals.total) AS total
+			FROM (? UNION ALL ?) AS totals LEFT JOIN categories ON totals.category_id = categories.id
+			GROUP BY totals.category_id, categories.name HAVING SUM(totals.total) <> 0`, aggregated, partial).
+		Scan(&results).Error
+	return results, err
+}
+
+// SplitCategoryTotals memisahkan nama kategori, total dan warna chart kategori, limit 0 berarti semua kategori
+func SplitCategoryTotals(totals []CategoryTotal, limit int) ([]string, []float64, []string) {
+	if limit > 0 && len(totals) > limit {
+		totals = totals[:limit]
 	}
 
-	return labels, data, nil
+	labels := make([]string, len(totals))
+	data := make([]float64, len(totals))
+	colors := make([]string, len(totals))
+	for i, total := range totals {
+		labels[i] = total.Category
+		data[i] = total.Total
+		colors[i] = CategoryChartColor(total.Color, i)
+	}
+	return labels, data, colors
 }
@@ -0,0 +1,81 @@
+package utility
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// EmailSender adalah abstraksi pengiriman email agar bisa diganti SMTP atau outbox lokal
+type EmailSender interface {
+	Send(message EmailMessage) error
+}
+
+// SMTPSender mengirim email melalui server SMTP
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+func (s *SMTPSender) Send(message EmailMessage) error {
+	addr := net.JoinHostPort(s.Host, s.Port)
+
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+
+	if err := smtp.SendMail(addr, auth, s.From, []string{message.To}, buildMIMEMessage(s.From, message)); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+
+	return nil
+}
+
+// FileSender menulis email ke folder outbox, dipakai untuk development lokal dan testing
+type FileSender struct {
+	Dir  string
+	From string
+}
+
+func (s *FileSender) Send(message EmailMessage) error {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create outbox directory: %v", err)
+	}
+
+	filename := fmt.Sprintf("%s_%s.eml", time.Now().Format("20060102T150405"), uuid.New().String())
+	if err := os.WriteFile(filepath.Join(s.Dir, filename), buildMIMEMessage(s.From, message), 0644); err != nil {
+		return fmt.Errorf("failed to write email to outbox: %v", err)
+	}
+
+	return nil
+}
+
+func buildMIMEMessage(from string, message EmailMessage) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("From: " + from + "\r\n")
+	buf.WriteString("To: " + message.To + "\r\n")
+	buf.WriteString("Subject: " + message.Subject + "\r\n")
+	buf.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	return buf.Bytes()
+}
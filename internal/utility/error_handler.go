@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 )
 
@@ -49,6 +50,26 @@ func ValidationErrorResponse(ctx *gin.Context, err error) {
 	})
 }
 
+// untuk error binding request body, pesan validasi dibuat readable
+func BindingErrorResponse(ctx *gin.Context, err error) {
+	if validationErrors, ok := err.(validator.ValidationErrors); ok {
+		formattedErrors := FormatValidationError(validationErrors)
+		messages := make([]string, len(formattedErrors))
+		for i, err := range formattedErrors {
+			messages[i] = GetReadableErrorMessage(err)
+		}
+		ErrorResponse(ctx, http.StatusBadRequest, messages[0], []response.ErrorDetail{
+			{
+				Field:   "validation",
+				Message: messages,
+			},
+		})
+		return
+	}
+
+	ErrorResponse(ctx, http.StatusBadRequest, "Invalid input format", nil)
+}
+
 func Recovery() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		defer func() {
@@ -0,0 +1,200 @@
+package utility
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// jumlah bulan penuh berturut-turut yang harus memuat transaksi yang sama agar dianggap rutin
+	RecurringLookbackMonths = 3
+	// jendela rata-rata pengeluaran variabel per hari
+	VariableSpendWindowDays = 90
+)
+
+// ForecastTransaction adalah transaksi yang dipakai forecast, baik riwayat maupun yang bertanggal di masa depan
+type ForecastTransaction struct {
+	CategoryID uint      `gorm:"column:category_id"`
+	Category   string    `gorm:"column:category_name"`
+	Type       string    `gorm:"column:type"`
+	Amount     float64   `gorm:"column:amount"`
+	Date       time.Time `gorm:"column:date"`
+}
+
+// RecurringItem adalah transaksi rutin bulanan yang terdeteksi dari riwayat, diproyeksikan di tanggal DayOfMonth
+type RecurringItem struct {
+	CategoryID uint
+	Category   string
+	Type       string
+	Amount     float64
+	DayOfMonth int
+	// tanggal kemunculan terakhir, bulan yang sudah memuat item ini tidak diproyeksikan lagi
+	LastDate time.Time
+}
+
+// CategorySpend adalah rata-rata pengeluaran variabel per hari untuk satu kategori
+type CategorySpend struct {
+	CategoryID   uint
+	Category     string
+	DailyAverage float64
+}
+
+// ForecastDay adalah proyeksi arus kas satu hari, Balance adalah saldo di akhir hari
+type ForecastDay struct {
+	Date    time.Time
+	Income  float64
+	Expense float64
+	Balance float64
+}
+
+type recurringKey struct {
+	categoryID uint
+	txType     string
+	cents      int64
+}
+
+func keyOf(tx ForecastTransaction) recurringKey {
+	return recurringKey{categoryID: tx.CategoryID, txType: tx.Type, cents: int64(math.Round(tx.Amount * 100))}
+}
+
+func (item RecurringItem) key() recurringKey {
+	return recurringKey{categoryID: item.CategoryID, txType: item.Type, cents: int64(math.Round(item.Amount * 100))}
+}
+
+// GetForecastTransactions mengambil transaksi aktif workspace di rentang [start, end) beserta nama kategorinya
+func (u *DashboardUtil) GetForecastTransactions(ctx context.Context, workspaceID uint, start, end time.Time) ([]ForecastTransaction, error) {
+	var transactions []ForecastTransaction
+	err := u.transactions(workspaceID).WithContext(ctx).
+		Select("transactions.category_id, categories.name AS category_name, transactions.type, transactions.amount, transactions.date").
+		Joins("LEFT JOIN categories ON transactions.category_id = categories.id").
+		Where("transactions.date >= ? AND transactions.date < ?", start, end).
+		Order("transactions.date").
+		Scan(&transactions).Error
+	return transactions, err
+}
+
+// DetectRecurring mencari transaksi dengan kategori, tipe dan nominal yang sama di setiap bulan kalender penuh
+// selama RecurringLookbackMonths sebelum bulan today. Transaksi setelah today (terjadwal) hanya memperbarui LastDate.
+func DetectRecurring(transactions []ForecastTransaction, today time.Time) []RecurringItem {
+	currentMonth := MonthStart(today)
+	lookbackStart := currentMonth.AddDate(0, -RecurringLookbackMonths, 0)
+
+	months := map[recurringKey]map[time.Time]bool{}
+	latest := map[recurringKey]ForecastTransaction{}
+	for _, tx := range transactions {
+		key := keyOf(tx)
+		if !tx.Date.Before(lookbackStart) && tx.Date.Before(currentMonth) {
+			if months[key] == nil {
+				months[key] = map[time.Time]bool{}
+			}
+			months[key][MonthStart(tx.Date)] = true
+		}
+		if last, ok := latest[key]; !ok || tx.Date.After(last.Date) {
+			latest[key] = tx
+		}
+	}
+
+	var items []RecurringItem
+	for key, seen := range months {
+		if len(seen) < RecurringLookbackMonths {
+			continue
+		}
+
+		// tanggal rutin diambil dari kemunculan terakhir yang sudah terjadi, bukan yang terjadwal
+		var dayOfMonth int
+		for _, tx := range transactions {
+			if keyOf(tx) == key && !tx.Date.After(today) {
+				dayOfMonth = tx.Date.Day()
+			}
+		}
+
+		last := latest[key]
+		items = append(items, RecurringItem{
+			CategoryID: last.CategoryID,
+			Category:   last.Category,
+			Type:       last.Type,
+			Amount:     last.Amount,
+			DayOfMonth: dayOfMonth,
+			LastDate:   last.Date,
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].DayOfMonth != items[j].DayOfMonth {
+			return items[i].DayOfMonth < items[j].DayOfMonth
+		}
+		return items[i].Category < items[j].Category
+	})
+	return items
+}
+
+// VariableSpend menghitung rata-rata expense per hari per kategori selama VariableSpendWindowDays sampai today,
+// tanpa transaksi yang sudah dihitung sebagai item rutin. Hasil diurutkan dari rata-rata terbesar.
+func VariableSpend(transactions []ForecastTransaction, recurring []RecurringItem, today time.Time) []CategorySpend {
+	recurringKeys := map[recurringKey]bool{}
+	for _, item := range recurring {
+		recurringKeys[item.key()] = true
+	}
+
+	windowStart := today.AddDate(0, 0, 1-VariableSpendWindowDays)
+	totals := map[uint]*CategorySpend{}
+	var order []uint
+	for _, tx := range transactions {
+		if tx.Type != "expense" || tx.Date.Before(windowStart) || tx.Date.After(today) || recurringKeys[keyOf(tx)] {
+			continue
+		}
+		if totals[tx.CategoryID] == nil {
+			totals[tx.CategoryID] = &CategorySpend{CategoryID: tx.CategoryID, Category: tx.Category}
+			order = append(order, tx.CategoryID)
+		}
+		totals[tx.CategoryID].DailyAverage += tx.Amount
+	}
+
+	spends := make([]CategorySpend, 0, len(order))
+	for _, categoryID := range order {
+		spend := *totals[categoryID]
+		spend.DailyAverage /= VariableSpendWindowDays
+		spends = append(spends, spend)
+	}
+	sort.SliceStable(spends, func(i, j int) bool { return spends[i].DailyAverage > spends[j].DailyAverage })
+	return spends
+}
+
+// ProjectCashFlow memproyeksikan saldo harian mulai hari setelah today selama days hari dari transaksi
+// terjadwal, item rutin dan pengeluaran variabel harian
+func ProjectCashFlow(today time.Time, days int, balance float64, scheduled []ForecastTransaction, recurring []RecurringItem, dailyVariable float64) []ForecastDay {
+	projection := make([]ForecastDay, days)
+	for i := range projection {
+		date := today.AddDate(0, 0, i+1)
+		day := ForecastDay{Date: date, Expense: dailyVariable}
+
+		for _, tx := range scheduled {
+			if tx.Date.Equal(date) {
+				addForecastAmount(&day, tx.Type, tx.Amount)
+			}
+		}
+		for _, item := range recurring {
+			// tanggal 31 jatuh di hari terakhir bulan pendek, item yang sudah tercatat atau terjadwal
+			// di bulan yang sama tidak dihitung dua kali
+			due := CycleStart(date.Year(), date.Month(), item.DayOfMonth)
+			if due.Equal(date) && MonthStart(date).After(MonthStart(item.LastDate)) {
+				addForecastAmount(&day, item.Type, item.Amount)
+			}
+		}
+
+		balance += day.Income - day.Expense
+		day.Balance = balance
+		projection[i] = day
+	}
+	return projection
+}
+
+func addForecastAmount(day *ForecastDay, txType string, amount float64) {
+	if txType == "income" {
+		day.Income += amount
+	} else {
+		day.Expense += amount
+	}
+}
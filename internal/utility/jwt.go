@@ -16,12 +16,18 @@ func getJWTSecret() []byte {
 	return []byte(secret)
 }
 
-func GenerateJWT(userID uint, username string, isAdmin bool) (string, error) {
+// masa berlaku access token, dipakai juga untuk expiry session
+const TokenTTL = 24 * time.Hour
+
+// role tidak disimpan di token, permission selalu dicek ke database oleh middleware.
+// workspaceID 0 berarti workspace personal user.
+func GenerateJWT(userID uint, username string, sessionID string, workspaceID uint) (string, error) {
 	claims := jwt.MapClaims{
 		"sub":      userID,
 		"username": username,
-		"is_admin": isAdmin,
-		"exp":      time.Now().Add(24 * time.Hour).Unix(), // 24 jam
+		"sid":      sessionID,
+		"wid":      workspaceID,
+		"exp":      time.Now().Add(TokenTTL).Unix(), // 24 jam
 	}
 
 	jwtSecret := getJWTSecret()
@@ -46,3 +52,40 @@ func ParseJWT(tokenString string) (*jwt.Token, error) {
 
 	return token, nil
 }
+
+// masa berlaku challenge token untuk langkah kedua login 2FA
+const ChallengeTokenTTL = 5 * time.Minute
+
+var ErrInvalidChallengeToken = errors.New("invalid or expired two-factor challenge")
+
+// GenerateChallengeJWT membuat token singkat yang hanya bisa ditukar di endpoint verifikasi 2FA.
+// Token ini tidak punya sid sehingga ditolak oleh middleware Authentication.
+func GenerateChallengeJWT(userID uint) (string, error) {
+	claims := jwt.MapClaims{
+		"sub": userID,
+		"typ": "2fa_challenge",
+		"exp": time.Now().Add(ChallengeTokenTTL).Unix(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(getJWTSecret())
+}
+
+func ParseChallengeJWT(tokenString string) (uint, error) {
+	token, err := ParseJWT(tokenString)
+	if err != nil || !token.Valid {
+		return 0, ErrInvalidChallengeToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["typ"] != "2fa_challenge" {
+		return 0, ErrInvalidChallengeToken
+	}
+
+	sub, ok := claims["sub"].(float64)
+	if !ok {
+		return 0, ErrInvalidChallengeToken
+	}
+
+	return uint(sub), nil
+}
@@ -0,0 +1,65 @@
+package utility
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nama bulan singkat untuk locale bahasa Indonesia, locale lain memakai format bawaan Go (bahasa Inggris)
+var indonesianMonths = [...]string{"Jan", "Feb", "Mar", "Apr", "Mei", "Jun", "Jul", "Agu", "Sep", "Okt", "Nov", "Des"}
+
+// MonthLabel membuat label bulan singkat untuk chart sesuai locale user
+func MonthLabel(t time.Time, locale string) string {
+	if isIndonesianLocale(locale) {
+		return indonesianMonths[t.Month()-1]
+	}
+	return t.Format("Jan")
+}
+
+// nama hari singkat untuk locale bahasa Indonesia, urutan mengikuti time.Weekday
+var indonesianWeekdays = [...]string{"Min", "Sen", "Sel", "Rab", "Kam", "Jum", "Sab"}
+
+// WeekdayLabel membuat label hari singkat untuk chart sesuai locale user
+func WeekdayLabel(day time.Weekday, locale string) string {
+	if isIndonesianLocale(locale) {
+		return indonesianWeekdays[day]
+	}
+	return day.String()[:3]
+}
+
+// FormatAmount memformat nominal sesuai preferensi number format, mis. "dot_comma" -> 1.234,56
+func FormatAmount(amount float64, numberFormat string) string {
+	thousands, decimal := ",", "."
+	switch numberFormat {
+	case "dot_comma":
+		thousands, decimal = ".", ","
+	case "space_comma":
+		thousands, decimal = " ", ","
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	cents := int64(math.Round(amount * 100))
+	whole := strconv.FormatInt(cents/100, 10)
+
+	var b strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(thousands)
+		}
+		b.WriteRune(digit)
+	}
+
+	return sign + b.String() + decimal + strconv.FormatInt(100+cents%100, 10)[1:]
+}
+
+func isIndonesianLocale(locale string) bool {
+	locale = strings.ToLower(locale)
+	return locale == "id" || strings.HasPrefix(locale, "id-") || strings.HasPrefix(locale, "id_")
+}
@@ -0,0 +1,127 @@
+package utility
+
+import (
+	"go-fintrack/internal/payload/entity"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// jumlah hari terakhir yang disimpan sebagai snapshot harian, snapshot bulanan disimpan untuk seluruh riwayat
+const NetWorthDailyDays = 366
+
+type periodFlow struct {
+	Period  time.Time
+	Income  float64
+	Expense float64
+}
+
+// BuildNetWorthSnapshots menghitung snapshot bulanan sejak bulan transaksi pertama dan snapshot harian
+// NetWorthDailyDays terakhir sampai today. Bulan sebelum bulan berjalan dibaca dari monthly_aggregates,
+// sisanya dari transaksi sehingga transaksi bertanggal setelah today tidak ikut dihitung.
+func BuildNetWorthSnapshots(db *gorm.DB, workspaceID uint, today time.Time) ([]entity.NetWorthSnapshot, error) {
+	currentMonth := MonthStart(today)
+	dailyStart := today.AddDate(0, 0, 1-NetWorthDailyDays)
+	scanStart := MonthStart(dailyStart)
+
+	var months []periodFlow
+	if err := db.Table("monthly_aggregates").
+		Select(`month AS period,
+			SUM(CASE WHEN type = 'income' THEN total ELSE 0 END) AS income,
+			SUM(CASE WHEN type = 'expense' THEN total ELSE 0 END) AS expense`).
+		Where("workspace_id = ? AND month < ?", workspaceID, currentMonth).
+		Group("month").
+		Having("SUM(transaction_count) > 0").
+		Order("month").
+		Scan(&months).Error; err != nil {
+		return nil, err
+	}
+
+	var days []periodFlow
+	if err := db.Table("transactions").
+		Select(`date AS period,
+			SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END) AS income,
+			SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END) AS expense`).
+		Where("workspace_id = ? AND deleted_at IS NULL AND date >= ? AND date <= ?", workspaceID, scanStart, today).
+		Group("date").
+		Order("date").
+		Scan(&days).Error; err != nil {
+		return nil, err
+	}
+
+	// bulan berjalan dijumlahkan dari transaksi harian
+	var current periodFlow
+	for _, day := range days {
+		if !day.Period.Before(currentMonth) {
+			current.Income += day.Income
+			current.Expense += day.Expense
+		}
+	}
+	if current.Income != 0 || current.Expense != 0 {
+		months = append(months, periodFlow{Period: currentMonth, Income: current.Income, Expense: current.Expense})
+	}
+	if len(months) == 0 {
+		return nil, nil
+	}
+
+	var snapshots []entity.NetWorthSnapshot
+	monthFlows := make(map[time.Time]periodFlow, len(months))
+	for _, month := range months {
+		monthFlows[MonthStart(month.Period)] = month
+	}
+
+	// snapshot bulanan tanpa celah sejak bulan pertama, bulan tanpa transaksi membawa saldo sebelumnya
+	var netWorth, openingDaily float64
+	for month := MonthStart(months[0].Period); !month.After(currentMonth); month = month.AddDate(0, 1, 0) {
+		if month.Equal(scanStart) {
+			openingDaily = netWorth
+		}
+		flow := monthFlows[month]
+		netWorth += flow.Income - flow.Expense
+
+		end := month.AddDate(0, 1, -1)
+		if end.After(today) {
+			end = today
+		}
+		snapshots = append(snapshots, entity.NetWorthSnapshot{
+			WorkspaceID: workspaceID,
+			Granularity: entity.NetWorthMonthly,
+			Date:        end,
+			Income:      flow.Income,
+			Expense:     flow.Expense,
+			NetWorth:    netWorth,
+		})
+	}
+	if MonthStart(months[0].Period).After(scanStart) {
+		openingDaily = 0
+	}
+
+	dayFlows := make(map[time.Time]periodFlow, len(days))
+	for _, day := range days {
+		dayFlows[CalendarDate(day.Period.UTC())] = day
+	}
+
+	// snapshot harian dimulai dari hari pertama yang punya riwayat
+	first := MonthStart(months[0].Period)
+	if first.Before(scanStart) {
+		first = scanStart
+	}
+	netWorth = openingDaily
+	for day := first; !day.After(today); day = day.AddDate(0, 0, 1) {
+		flow := dayFlows[day]
+		netWorth += flow.Income - flow.Expense
+		if day.Before(dailyStart) {
+			continue
+		}
+		snapshots = append(snapshots, entity.NetWorthSnapshot{
+			WorkspaceID: workspaceID,
+			Granularity: entity.NetWorthDaily,
+			Date:        day,
+			Income:      flow.Income,
+			Expense:     flow.Expense,
+			NetWorth:    netWorth,
+		})
+	}
+
+	return snapshots, nil
+}
@@ -0,0 +1,175 @@
+package utility
+
+import (
+	"fmt"
+	"time"
+)
+
+// granularity periode dashboard
+const (
+	GranularityDay     = "day"
+	GranularityWeek    = "week"
+	GranularityMonth   = "month"
+	GranularityQuarter = "quarter"
+	GranularityYear    = "year"
+)
+
+// Period adalah satu bucket chart. Start dan End (eksklusif) berupa tanggal kalender (00:00 UTC) yang sudah
+// dipotong ke rentang yang diminta; Key mengikuti ISO 8601 (2024-08-01, 2024-W31, 2024-08, 2024-Q3, 2024).
+type Period struct {
+	Key   string
+	Label string
+	Start time.Time
+	End   time.Time
+}
+
+// PeriodConfig berisi preferensi user yang memengaruhi batas periode
+type PeriodConfig struct {
+	FirstDayOfWeek int // mengikuti time.Weekday
+	// tanggal mulai siklus bulanan (1-31), mis. 25 untuk gajian tanggal 25. Bulan yang lebih pendek
+	// memakai tanggal terakhirnya, sehingga siklus tanggal 31 dimulai 29/28 februari.
+	CycleStartDay int
+	Locale        string
+}
+
+// CalendarDate mengambil tanggal kalender t di zona waktunya sendiri sebagai 00:00 UTC,
+// sehingga perhitungan tanggal berikutnya tidak terpengaruh DST
+func CalendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// PeriodStart mengembalikan awal periode yang memuat tanggal date. Periode bulanan mengikuti CycleStartDay
+// dan diberi nama sesuai bulan tempat siklus dimulai (siklus 25 Agu - 24 Sep adalah "2024-08").
+func PeriodStart(date time.Time, granularity string, config PeriodConfig) time.Time {
+	date = CalendarDate(date)
+	switch granularity {
+	case GranularityWeek:
+		offset := (int(date.Weekday()) - config.FirstDayOfWeek + 7) % 7
+		return date.AddDate(0, 0, -offset)
+	case GranularityMonth:
+		start := CycleStart(date.Year(), date.Month(), config.CycleStartDay)
+		if date.Before(start) {
+			start = CycleStart(date.Year(), date.Month()-1, config.CycleStartDay)
+		}
+		return start
+	case GranularityQuarter:
+		month := (date.Month()-1)/3*3 + 1
+		return time.Date(date.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+	case GranularityYear:
+		return time.Date(date.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return date
+	}
+}
+
+// CycleStart mengembalikan tanggal mulai siklus bulanan di bulan tertentu, month boleh di luar 1-12
+func CycleStart(year int, month time.Month, cycleStartDay int) time.Time {
+	firstOfMonth := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+
+	day := cycleStartDay
+	if day < 1 {
+		day = 1
+	}
+	if day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
+
+// CycleMonth mengembalikan rentang inklusif siklus bulanan untuk month berformat 2006-01
+func CycleMonth(month string, cycleStartDay int) (time.Time, time.Time, error) {
+	parsed, err := time.Parse("2006-01", month)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	start := CycleStart(parsed.Year(), parsed.Month(), cycleStartDay)
+	end := CycleStart(parsed.Year(), parsed.Month()+1, cycleStartDay).AddDate(0, 0, -1)
+	return start, end, nil
+}
+
+// CycleMonthOf mengembalikan nama bulan siklus (tanggal 1, 00:00 UTC) yang memuat date,
+// mis. 3 Sep pada siklus tanggal 25 masuk bulan "2024-08"
+func CycleMonthOf(date time.Time, config PeriodConfig) time.Time {
+	return MonthStart(PeriodStart(date, GranularityMonth, config))
+}
+
+// AddPeriods menggeser awal periode sebanyak n periode (boleh negatif)
+func AddPeriods(start time.Time, granularity string, n int, config PeriodConfig) time.Time {
+	switch granularity {
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7*n)
+	case GranularityMonth:
+		// dihitung ulang dari bulan siklus, AddDate biasa akan menggeser tanggal 31 ke bulan berikutnya
+		return CycleStart(start.Year(), start.Month()+time.Month(n), config.CycleStartDay)
+	case GranularityQuarter:
+		return start.AddDate(0, 3*n, 0)
+	case GranularityYear:
+		return start.AddDate(n, 0, 0)
+	default:
+		return start.AddDate(0, 0, n)
+	}
+}
+
+// BuildPeriods membagi rentang [start, end) menjadi periode sesuai granularity
+func BuildPeriods(start, end time.Time, granularity string, config PeriodConfig) []Period {
+	var periods []Period
+	multiYear := start.Year() != end.AddDate(0, 0, -1).Year()
+
+	for periodStart := PeriodStart(start, granularity, config); periodStart.Before(end); {
+		periodEnd := AddPeriods(periodStart, granularity, 1, config)
+
+		period := Period{
+			Key:   PeriodKey(periodStart, granularity),
+			Label: periodLabel(periodStart, granularity, config.Locale, multiYear),
+			Start: periodStart,
+			End:   periodEnd,
+		}
+		if period.Start.Before(start) {
+			period.Start = start
+		}
+		if period.End.After(end) {
+			period.End = end
+		}
+		periods = append(periods, period)
+
+		periodStart = periodEnd
+	}
+
+	return periods
+}
+
+// PeriodKey membuat key ISO 8601 untuk periode yang dimulai pada start
+func PeriodKey(start time.Time, granularity string) string {
+	switch granularity {
+	case GranularityWeek:
+		// minggu yang dimulai hari minggu/sabtu tetap memuat tepat satu hari senin, key mengikuti minggu ISO hari tersebut
+		monday := start.AddDate(0, 0, (int(time.Monday)-int(start.Weekday())+7)%7)
+		year, week := monday.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case GranularityMonth:
+		return start.Format("2006-01")
+	case GranularityQuarter:
+		return fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())-1)/3+1)
+	case GranularityYear:
+		return start.Format("2006")
+	default:
+		return start.Format("2006-01-02")
+	}
+}
+
+func periodLabel(start time.Time, granularity, locale string, multiYear bool) string {
+	switch granularity {
+	case GranularityDay, GranularityWeek:
+		return fmt.Sprintf("%d %s", start.Day(), MonthLabel(start, locale))
+	case GranularityMonth:
+		if multiYear {
+			return fmt.Sprintf("%s %d", MonthLabel(start, locale), start.Year())
+		}
+		return MonthLabel(start, locale)
+	case GranularityQuarter:
+		return fmt.Sprintf("Q%d %d", (int(start.Month())-1)/3+1, start.Year())
+	default:
+		return start.Format("2006")
+	}
+}
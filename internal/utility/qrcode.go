@@ -0,0 +1,22 @@
+package utility
+
+import (
+	"errors"
+	"fmt"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+var ErrQRCodeTooLong = errors.New("content is too long for QR code")
+
+// EncodeQRCodePNG membuat PNG QR code (error correction level M) dengan quiet zone 4 module,
+// scale adalah ukuran pixel per module
+func EncodeQRCodePNG(content string, scale int) ([]byte, error) {
+	qr, err := qrcode.New(content, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrQRCodeTooLong, err)
+	}
+
+	// ukuran negatif membuat gambar dengan ukuran module tetap
+	return qr.PNG(-scale)
+}
@@ -0,0 +1,43 @@
+package utility
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// GenerateSecureToken membuat token acak yang aman dikirim lewat URL
+func GenerateSecureToken() string {
+	return GenerateRandomString(43)
+}
+
+// HashToken menghasilkan sha256 hex dari token, yang disimpan di database hanya hash-nya
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+const recoveryCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GenerateRecoveryCode membuat recovery code 2FA dengan format XXXXX-XXXXX
+func GenerateRecoveryCode() string {
+	b := make([]byte, 10)
+	rand.Read(b)
+
+	code := make([]byte, 0, 11)
+	for i, v := range b {
+		if i == 5 {
+			code = append(code, '-')
+		}
+		code = append(code, recoveryCodeAlphabet[int(v)%len(recoveryCodeAlphabet)])
+	}
+
+	return string(code)
+}
+
+// NormalizeRecoveryCode menyamakan format input user sebelum di-hash
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -2,7 +2,6 @@ package utility
 
 import (
 	"fmt"
-	"go-fintrack/internal/payload/entity"
 	"go-fintrack/internal/payload/request"
 	"go-fintrack/internal/payload/response"
 	"time"
@@ -43,25 +42,40 @@ func (u *TransactionUtil) BuildFilterQuery(query *gorm.DB, filter request.Transa
 	return newQuery
 }
 
-func (u *TransactionUtil) CalculateTransactionSummary(baseQuery *gorm.DB, filter request.TransactionFilter) (*response.TransactionSummary, error) {
-	var totalIncome, totalExpense float64
-
-	// hitung total income
-	incomeQuery := baseQuery.Session(&gorm.Session{})
-	if err := incomeQuery.Model(&entity.Transaction{}).
-		Where("type = ?", "income").
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&totalIncome).Error; err != nil {
-		return nil, fmt.Errorf("failed to calculate total income: %v", err)
+// CalculateTransactionSummary menghitung total income dan expense sesuai filter dalam satu query. Bulan kalender
+// penuh dibaca dari monthly_aggregates, tanggal di luar bulan penuh dibaca langsung dari transaksi.
+func (u *TransactionUtil) CalculateTransactionSummary(workspaceID uint, filter request.TransactionFilter) (*response.TransactionSummary, error) {
+	// tanpa filter tanggal rentang mencakup semua transaksi
+	start := time.Date(1, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(9999, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if filter.StartDate != "" {
+		if startDate, err := time.Parse("2006-01-02", filter.StartDate); err == nil {
+			start = startDate
+		}
+	}
+	if filter.EndDate != "" {
+		if endDate, err := time.Parse("2006-01-02", filter.EndDate); err == nil {
+			end = endDate.AddDate(0, 0, 1)
+		}
 	}
+	from, to := aggregateSpan(start, end)
 
-	// hitung total expense
-	expenseQuery := baseQuery.Session(&gorm.Session{})
-	if err := expenseQuery.Model(&entity.Transaction{}).
-		Where("type = ?", "expense").
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&totalExpense).Error; err != nil {
-		return nil, fmt.Errorf("failed to calculate total expense: %v", err)
+	aggregated := u.DB.Table("monthly_aggregates").
+		Select(`SUM(CASE WHEN type = 'income' THEN total ELSE 0 END) AS income,
+			SUM(CASE WHEN type = 'expense' THEN total ELSE 0 END) AS expense`).
+		Where("workspace_id = ? AND month >= ? AND month < ?", workspaceID, from, to)
+	partial := u.DB.Table("transactions").
+		Select(`SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END) AS income,
+			SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END) AS expense`).
+		Where("workspace_id = ? AND deleted_at IS NULL AND date >= ? AND date < ?", workspaceID, start, end).
+		Where("(date < ? OR date >= ?)", from, to)
+
+	var totalIncome, totalExpense float64
+	if err := u.DB.Raw("SELECT COALESCE(SUM(income), 0), COALESCE(SUM(expense), 0) FROM (? UNION ALL ?) AS summary",
+		applySummaryFilter(aggregated, filter), applySummaryFilter(partial, filter)).
+		Row().
+		Scan(&totalIncome, &totalExpense); err != nil {
+		return nil, fmt.Errorf("failed to calculate transaction summary: %v", err)
 	}
 
 	return &response.TransactionSummary{
@@ -70,3 +84,14 @@ func (u *TransactionUtil) CalculateTransactionSummary(baseQuery *gorm.DB, filter
 		Balance:      totalIncome - totalExpense,
 	}, nil
 }
+
+// applySummaryFilter menerapkan filter kategori dan tipe yang sama pada query agregat dan transaksi
+func applySummaryFilter(query *gorm.DB, filter request.TransactionFilter) *gorm.DB {
+	if filter.CategoryID != 0 {
+		query = query.Where("category_id = ?", filter.CategoryID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	return query
+}
@@ -4,9 +4,40 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
 )
 
+// batas username untuk registrasi, update profile dan username yang dibuat dari akun OAuth
+const (
+	UsernameMinLength = 4
+	UsernameMaxLength = 20
+)
+
+// tag binding "username" dipakai RegisterRequest dan UpdateProfileRequest agar aturannya sama
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		if err := v.RegisterValidation("username", func(fl validator.FieldLevel) bool {
+			return IsValidUsername(fl.Field().String())
+		}); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// IsValidUsername memeriksa username hanya berisi huruf/angka ASCII dengan panjang UsernameMinLength-UsernameMaxLength
+func IsValidUsername(username string) bool {
+	if len(username) < UsernameMinLength || len(username) > UsernameMaxLength {
+		return false
+	}
+	for _, r := range username {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
 type ValidationError struct {
 	Field string
 	Tag   string
@@ -51,6 +82,8 @@ func GetReadableErrorMessage(err ValidationError) string {
 		return fmt.Sprintf("%s must be at least %s characters", err.Field, err.Value)
 	case "max":
 		return fmt.Sprintf("%s cannot be longer than %s characters", err.Field, err.Value)
+	case "username":
+		return fmt.Sprintf("%s must be %d-%d letters or numbers", err.Field, UsernameMinLength, UsernameMaxLength)
 	case "alphanum":
 		return fmt.Sprintf("%s can only contain letters and numbers", err.Field)
 	case "eqfield":
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// TrackActivity mencatat user yang berhasil mengakses API sebagai user aktif hari ini.
+// Dipasang di group /api, dicek setelah handler selesai sehingga userID dari Authentication sudah tersedia.
+func TrackActivity(activityService *service.ActivityService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		if ctx.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+
+		if _, exists := ctx.Get("userID"); !exists {
+			return
+		}
+
+		userID, err := utility.GetUserIDFromContext(ctx)
+		if err != nil {
+			return
+		}
+
+		if err := activityService.TrackActive(userID); err != nil {
+			logrus.Errorf("Failed to track user activity: %v", err)
+		}
+	}
+}
+
+// CountChatUsage mencatat jumlah pesan chat per user, isi pesan tidak disimpan
+func CountChatUsage(activityService *service.ActivityService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, err := utility.GetUserIDFromContext(ctx)
+		if err == nil {
+			if err := activityService.RecordChatMessage(userID); err != nil {
+				logrus.Errorf("Failed to record chat usage: %v", err)
+			}
+		}
+
+		ctx.Next()
+	}
+}
@@ -2,16 +2,19 @@ package middleware
 
 import (
 	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
 	"net/http"
 
-	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 )
 
-func AdminOnly() gin.HandlerFunc {
+// RequirePermission memastikan role user memiliki permission tertentu.
+// Role dicek ke database (dengan cache singkat) sehingga perubahan role berlaku tanpa menunggu token baru.
+func RequirePermission(rbacService *service.RBACService, permission string) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		claims, exists := ctx.Get("claims")
-		if !exists {
+		userID, err := utility.GetUserIDFromContext(ctx)
+		if err != nil {
 			ctx.JSON(http.StatusUnauthorized, response.SuccessResponse{
 				ResponseStatus:  false,
 				ResponseMessage: "Unauthorized",
@@ -21,17 +24,21 @@ func AdminOnly() gin.HandlerFunc {
 			return
 		}
 
-		if mapClaims, ok := claims.(jwt.MapClaims); ok {
-			isAdmin, exists := mapClaims["is_admin"]
-			if !exists || !isAdmin.(bool) {
-				ctx.JSON(http.StatusForbidden, response.SuccessResponse{
-					ResponseStatus:  false,
-					ResponseMessage: "Access denied: Admin only",
-					Data:            nil,
-				})
-				ctx.Abort()
-				return
-			}
+		allowed, err := rbacService.HasPermission(userID, permission)
+		if err != nil && err != service.ErrUserNotFound {
+			utility.InternalServerErrorResponse(ctx, "Failed to check permission", err)
+			ctx.Abort()
+			return
+		}
+
+		if !allowed {
+			ctx.JSON(http.StatusForbidden, response.SuccessResponse{
+				ResponseStatus:  false,
+				ResponseMessage: service.ErrPermissionRequired.Error() + ": " + permission,
+				Data:            nil,
+			})
+			ctx.Abort()
+			return
 		}
 
 		ctx.Next()
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"go-fintrack/internal/utility"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const apiTokenScopeRuleKey = "apiTokenScopeRule"
+
+type apiTokenScopeRule struct {
+	read  string
+	write string
+}
+
+func (r apiTokenScopeRule) scopeFor(method string) string {
+	if method == http.MethodGet || method == http.MethodHead {
+		return r.read
+	}
+	return r.write
+}
+
+// APITokenScopes mengizinkan personal access token pada route group dengan scope read (GET) dan write (method lain).
+// Harus dipasang sebelum Authentication. Scope kosong berarti method tersebut tidak bisa diakses dengan API token.
+func APITokenScopes(readScope, writeScope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(apiTokenScopeRuleKey, apiTokenScopeRule{read: readScope, write: writeScope})
+		ctx.Next()
+	}
+}
+
+// RequireScope menambahkan scope wajib untuk satu route jika request memakai API token
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		scopes, isAPIToken := ctx.Get("apiTokenScopes")
+		if !isAPIToken {
+			ctx.Next()
+			return
+		}
+
+		for _, s := range scopes.([]string) {
+			if s == scope {
+				ctx.Next()
+				return
+			}
+		}
+
+		utility.ErrorResponse(ctx, http.StatusForbidden, "API token is missing the required scope: "+scope, nil)
+		ctx.Abort()
+	}
+}
@@ -2,19 +2,18 @@ package middleware
 
 import (
 	"go-fintrack/internal/payload/response"
+	"go-fintrack/internal/service"
 	"go-fintrack/internal/utility"
 	"net/http"
 	"strings"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
-	"github.com/sirupsen/logrus"
 )
 
-func Authentication() gin.HandlerFunc {
+func Authentication(sessionService *service.SessionService, apiTokenService *service.APITokenService, userService *service.UserService) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
-		logrus.Infof("Auth header: %s", authHeader) // debug
 		if authHeader == "" {
 			ctx.JSON(http.StatusUnauthorized, response.SuccessResponse{
 				ResponseStatus:  false,
@@ -26,7 +25,14 @@ func Authentication() gin.HandlerFunc {
 		}
 
 		// ambil token setelah bearer
-		tokenString := strings.Split(authHeader, "Bearer ")[1]
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+
+		// personal access token untuk script dan integrasi
+		if service.IsAPIToken(tokenString) {
+			authenticateAPIToken(ctx, apiTokenService, userService, tokenString)
+			return
+		}
+
 		token, err := utility.ParseJWT(tokenString)
 		if err != nil || !token.Valid {
 			ctx.JSON(http.StatusUnauthorized, response.SuccessResponse{
@@ -38,12 +44,129 @@ func Authentication() gin.HandlerFunc {
 			return
 		}
 
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok {
+			ctx.JSON(http.StatusUnauthorized, response.SuccessResponse{
+				ResponseStatus:  false,
+				ResponseMessage: "Invalid token",
+				Data:            nil,
+			})
+			ctx.Abort()
+			return
+		}
+
 		// menyimpan info user dari token ke dalam context
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			ctx.Set("userID", claims["sub"])
-			ctx.Set("username", claims["username"])
+		ctx.Set("claims", claims)
+		ctx.Set("userID", claims["sub"])
+		ctx.Set("username", claims["username"])
+
+		// token harus terikat ke session yang belum di-revoke
+		sessionID, _ := claims["sid"].(string)
+		userID, err := utility.GetUserIDFromContext(ctx)
+		if sessionID == "" || err != nil {
+			ctx.JSON(http.StatusUnauthorized, response.SuccessResponse{
+				ResponseStatus:  false,
+				ResponseMessage: "Invalid token",
+				Data:            nil,
+			})
+			ctx.Abort()
+			return
+		}
+
+		if _, err := sessionService.ValidateSession(sessionID, userID); err != nil {
+			if err != service.ErrSessionInvalid {
+				utility.InternalServerErrorResponse(ctx, "Failed to validate session", err)
+				ctx.Abort()
+				return
+			}
+
+			ctx.JSON(http.StatusUnauthorized, response.SuccessResponse{
+				ResponseStatus:  false,
+				ResponseMessage: "Session has been revoked or expired",
+				Data:            nil,
+			})
+			ctx.Abort()
+			return
+		}
+		ctx.Set("sessionID", sessionID)
+
+		if !checkAccountActive(ctx, userService, userID) {
+			return
 		}
 
 		ctx.Next()
 	}
 }
+
+// authenticateAPIToken memvalidasi personal access token dan scope yang diizinkan route group (lihat APITokenScopes)
+func authenticateAPIToken(ctx *gin.Context, apiTokenService *service.APITokenService, userService *service.UserService, tokenString string) {
+	rule, allowed := ctx.Get(apiTokenScopeRuleKey)
+	if !allowed {
+		utility.ErrorResponse(ctx, http.StatusForbidden, "API tokens are not allowed for this endpoint", nil)
+		ctx.Abort()
+		return
+	}
+
+	apiToken, err := apiTokenService.Authenticate(tokenString)
+	if err != nil {
+		if err != service.ErrInvalidAPIToken {
+			utility.InternalServerErrorResponse(ctx, "Failed to validate API token", err)
+			ctx.Abort()
+			return
+		}
+
+		ctx.JSON(http.StatusUnauthorized, response.SuccessResponse{
+			ResponseStatus:  false,
+			ResponseMessage: err.Error(),
+			Data:            nil,
+		})
+		ctx.Abort()
+		return
+	}
+
+	required := rule.(apiTokenScopeRule).scopeFor(ctx.Request.Method)
+	if required == "" || !apiToken.HasScope(required) {
+		utility.ErrorResponse(ctx, http.StatusForbidden, "API token is missing the required scope: "+required, nil)
+		ctx.Abort()
+		return
+	}
+
+	ctx.Set("userID", apiToken.UserID)
+	ctx.Set("apiTokenID", apiToken.ID)
+	ctx.Set("apiTokenScopes", apiToken.ScopeList())
+
+	if !checkAccountActive(ctx, userService, apiToken.UserID) {
+		return
+	}
+
+	ctx.Next()
+}
+
+// checkAccountActive menolak request dari akun yang di-suspend atau sudah dihapus walaupun token-nya masih berlaku
+func checkAccountActive(ctx *gin.Context, userService *service.UserService, userID uint) bool {
+	if err := userService.CheckAccountActive(userID); err != nil {
+		if err != service.ErrAccountInactive {
+			utility.InternalServerErrorResponse(ctx, "Failed to check account status", err)
+			ctx.Abort()
+			return false
+		}
+
+		utility.ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
+		ctx.Abort()
+		return false
+	}
+
+	return true
+}
+
+// OptionalAuthentication menjalankan authentication hanya jika header Authorization dikirim, dipakai endpoint publik
+// yang perilakunya bergantung pada user yang login (mis. callback OAuth untuk alur link identity)
+func OptionalAuthentication(authentication gin.HandlerFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("Authorization") == "" {
+			ctx.Next()
+			return
+		}
+		authentication(ctx)
+	}
+}
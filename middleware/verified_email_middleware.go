@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"go-fintrack/config"
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// nama fitur untuk policy UNVERIFIED_RESTRICTED_FEATURES
+const (
+	FeatureExport = "export"
+	FeatureChat   = "chat"
+)
+
+// RequireVerifiedEmail menolak akun yang emailnya belum terverifikasi jika fitur dibatasi oleh policy
+func RequireVerifiedEmail(userService *service.UserService, feature string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !config.IsRestrictedForUnverified(feature) {
+			ctx.Next()
+			return
+		}
+
+		userID, err := utility.GetUserIDFromContext(ctx)
+		if err != nil {
+			utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+			ctx.Abort()
+			return
+		}
+
+		verified, err := userService.IsEmailVerified(userID)
+		if err != nil {
+			utility.InternalServerErrorResponse(ctx, "Failed to check email verification", err)
+			ctx.Abort()
+			return
+		}
+
+		if !verified {
+			utility.ErrorResponse(ctx, http.StatusForbidden, service.ErrEmailNotVerified.Error(), nil)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
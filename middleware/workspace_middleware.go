@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"go-fintrack/internal/service"
+	"go-fintrack/internal/utility"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WorkspaceContext menentukan workspace aktif dan memastikan user masih menjadi member.
+// JWT memakai claim wid dari endpoint switch workspace, API token memakai header X-Workspace-ID.
+// Tanpa keduanya request memakai workspace personal. Viewer hanya boleh melakukan request baca.
+func WorkspaceContext(workspaceService *service.WorkspaceService) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, err := utility.GetUserIDFromContext(ctx)
+		if err != nil {
+			utility.ErrorResponse(ctx, http.StatusUnauthorized, err.Error(), nil)
+			ctx.Abort()
+			return
+		}
+
+		workspaceID, ok := requestedWorkspaceID(ctx)
+		if !ok {
+			utility.ErrorResponse(ctx, http.StatusBadRequest, "Invalid workspace ID", nil)
+			ctx.Abort()
+			return
+		}
+
+		member, err := workspaceService.ResolveMembership(userID, workspaceID)
+		if err != nil {
+			if err == service.ErrWorkspaceAccessDenied {
+				utility.ErrorResponse(ctx, http.StatusForbidden, err.Error(), nil)
+				ctx.Abort()
+				return
+			}
+			utility.InternalServerErrorResponse(ctx, "Failed to resolve workspace", err)
+			ctx.Abort()
+			return
+		}
+
+		method := ctx.Request.Method
+		if method != http.MethodGet && method != http.MethodHead && !member.CanWrite() {
+			utility.ErrorResponse(ctx, http.StatusForbidden, service.ErrWorkspaceReadOnly.Error(), nil)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set("workspaceID", member.WorkspaceID)
+		ctx.Set("workspaceRole", member.Role)
+
+		ctx.Next()
+	}
+}
+
+func requestedWorkspaceID(ctx *gin.Context) (uint, bool) {
+	if _, isAPIToken := ctx.Get("apiTokenID"); isAPIToken {
+		header := ctx.GetHeader("X-Workspace-ID")
+		if header == "" {
+			return 0, true
+		}
+		id, err := strconv.ParseUint(header, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		return uint(id), true
+	}
+
+	return utility.GetWorkspaceClaim(ctx), true
+}
@@ -19,8 +19,10 @@ export function useGoogleAuth() {
         throw new Error('Invalid state parameter')
       }
 
+      // withCredentials sends the oauth nonce cookie set by the login request
       const response = await apiClient.get('/auth/google/callback', {
-        params: { code, state }
+        params: { code, state },
+        withCredentials: true
       })
 
       console.log('Callback response:', response.data) // debug